@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"log"
 
 	"github.com/alan890104/tonutils-go/address"
@@ -11,38 +10,6 @@ import (
 	"github.com/alan890104/tonutils-go/ton"
 )
 
-// func to get storage map key
-func getShardID(shard *ton.BlockIDExt) string {
-	return fmt.Sprintf("%d|%d", shard.Workchain, shard.Shard)
-}
-
-func getNotSeenShards(ctx context.Context, api ton.APIClientWrapped, shard *ton.BlockIDExt, shardLastSeqno map[string]uint32) (ret []*ton.BlockIDExt, err error) {
-	if no, ok := shardLastSeqno[getShardID(shard)]; ok && no == shard.SeqNo {
-		return nil, nil
-	}
-
-	b, err := api.GetBlockData(ctx, shard)
-	if err != nil {
-		return nil, fmt.Errorf("get block data: %w", err)
-	}
-
-	parents, err := b.BlockInfo.GetParentBlocks()
-	if err != nil {
-		return nil, fmt.Errorf("get parent blocks (%d:%x:%d): %w", shard.Workchain, uint64(shard.Shard), shard.Shard, err)
-	}
-
-	for _, parent := range parents {
-		ext, err := getNotSeenShards(ctx, api, parent, shardLastSeqno)
-		if err != nil {
-			return nil, err
-		}
-		ret = append(ret, ext...)
-	}
-
-	ret = append(ret, shard)
-	return ret, nil
-}
-
 // FYI: You can find more advanced, optimized and parallelized block scanner in payment network implementation:
 // https://github.com/xssnick/ton-payment-network/blob/master/tonpayments/chain/block-scan.go
 
@@ -83,43 +50,16 @@ func main() {
 	// if it will go down, another lite server will be used
 	ctx := api.Client().StickyContext(context.Background())
 
-	// storage for last seen shard seqno
-	shardLastSeqno := map[string]uint32{}
+	// subscription walks shard blocks history, so blocks which were not listed
+	// in master blocks (holes) are also delivered, parents before children.
+	// To resume after restart, save last processed master seqno and subscribe from the next one.
+	updates := make(chan *ton.BlockUpdate)
+	go api.SubscribeOnBlocks(ctx, master.SeqNo, updates)
 
-	// getting information about other work-chains and shards of first master block
-	// to init storage of last seen shard seq numbers
-	firstShards, err := api.GetBlockShardsInfo(ctx, master)
-	if err != nil {
-		log.Fatalln("get shards err:", err.Error())
-		return
-	}
-	for _, shard := range firstShards {
-		shardLastSeqno[getShardID(shard)] = shard.SeqNo
-	}
+	for upd := range updates {
+		log.Printf("scanning %d master block...\n", upd.Master.SeqNo)
 
-	for {
-		log.Printf("scanning %d master block...\n", master.SeqNo)
-
-		// getting information about other work-chains and shards of master block
-		currentShards, err := api.GetBlockShardsInfo(ctx, master)
-		if err != nil {
-			log.Fatalln("get shards err:", err.Error())
-			return
-		}
-
-		// shards in master block may have holes, e.g. shard seqno 2756461, then 2756463, and no 2756462 in master chain
-		// thus we need to scan a bit back in case of discovering a hole, till last seen, to fill the misses.
-		var newShards []*ton.BlockIDExt
-		for _, shard := range currentShards {
-			notSeen, err := getNotSeenShards(ctx, api, shard, shardLastSeqno)
-			if err != nil {
-				log.Fatalln("get not seen shards err:", err.Error())
-				return
-			}
-			shardLastSeqno[getShardID(shard)] = shard.SeqNo
-			newShards = append(newShards, notSeen...)
-		}
-		newShards = append(newShards, master)
+		newShards := append(upd.Shards, upd.Master)
 
 		var txList []*tlb.Transaction
 
@@ -133,7 +73,7 @@ func main() {
 
 			// load all transactions in batches with 100 transactions in each while exists
 			for more {
-				fetchedIDs, more, err = api.WaitForBlock(master.SeqNo).GetBlockTransactionsV2(ctx, shard, 100, after)
+				fetchedIDs, more, err = api.WaitForBlock(upd.Master.SeqNo).GetBlockTransactionsV2(ctx, shard, 100, after)
 				if err != nil {
 					log.Fatalln("get tx ids err:", err.Error())
 					return
@@ -161,13 +101,7 @@ func main() {
 		}
 
 		if len(txList) == 0 {
-			log.Printf("no transactions in %d block\n", master.SeqNo)
-		}
-
-		master, err = api.WaitForBlock(master.SeqNo+1).LookupBlock(ctx, master.Workchain, master.Shard, master.SeqNo+1)
-		if err != nil {
-			log.Fatalln("get masterchain info err: ", err.Error())
-			return
+			log.Printf("no transactions in %d block\n", upd.Master.SeqNo)
 		}
 	}
 }
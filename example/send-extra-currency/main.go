@@ -47,49 +47,27 @@ func main() {
 		return
 	}
 
-	acc, err := api.GetAccount(context.Background(), block, w.WalletAddress())
+	balance, extra, err := w.GetBalanceWithExtraCurrencies(context.Background(), block)
 	if err != nil {
-		log.Fatalln("GetAccount err:", err.Error())
+		log.Fatalln("GetBalance err:", err.Error())
 		return
 	}
 
-	if !acc.IsActive {
-		log.Fatalln("Wallet is empty")
-		return
-	}
-	balance := acc.State.Balance
+	if !extra.IsEmpty() {
+		// printing all extra currencies we have
+		for _, id := range extra.IDs() {
+			log.Printf("ExtraCurrency ID: %d, Amount: %s\n", id, extra[id].String())
+		}
 
-	if !acc.State.ExtraCurrencies.IsEmpty() {
-		currencies, err := acc.State.ExtraCurrencies.LoadAll()
+		// sending all extra currencies to another address
+		msg, err := wallet.SimpleMessageWithExtraCurrencies(address.MustParseAddr("kQAbMQzuuGiCne0R7QEj9nrXsjM7gNjeVmrlBZouyC-SCALE"),
+			tlb.MustFromTON("0.05"), extra, cell.BeginCell().EndCell())
 		if err != nil {
-			log.Fatalln("LoadAll err:", err.Error())
+			log.Fatalln("Build message err:", err.Error())
 			return
 		}
 
-		ec := cell.NewDict(32)
-
-		// printing all extra currencies we have, and preparing them to send
-		for _, kv := range currencies {
-			id := kv.Key.MustLoadBigUInt(32)
-			amount := kv.Value.MustLoadVarUInt(32)
-
-			log.Printf("ExtraCurrency ID: %d, Amount: %s\n", id.Uint64(), amount.String())
-
-			// adding currency to send in tx
-			_ = ec.SetIntKey(id, cell.BeginCell().MustStoreBigVarUInt(amount, 32).EndCell())
-		}
-
-		// sending extra currencies to another address
-		tx, _, err := w.SendWaitTransaction(context.Background(), &wallet.Message{
-			Mode: wallet.PayGasSeparately + wallet.IgnoreErrors,
-			InternalMessage: &tlb.InternalMessage{
-				Bounce:          true,
-				DstAddr:         address.MustParseAddr("kQAbMQzuuGiCne0R7QEj9nrXsjM7gNjeVmrlBZouyC-SCALE"),
-				Amount:          tlb.MustFromTON("0.05"),
-				ExtraCurrencies: ec,
-				Body:            cell.BeginCell().EndCell(),
-			},
-		})
+		tx, _, err := w.SendWaitTransaction(context.Background(), msg)
 		if err != nil {
 			log.Fatalln("Send err:", err.Error())
 			return
@@ -57,16 +57,139 @@ type AccountStorage struct {
 	StateHash []byte
 }
 
+// StorageUsed is a storage stat of account
+//
+//	storage_used$_ cells:(VarUInteger 7) bits:(VarUInteger 7) = StorageUsed;
 type StorageUsed struct {
-	CellsUsed       *big.Int `tlb:"var uint 7"`
-	BitsUsed        *big.Int `tlb:"var uint 7"`
-	PublicCellsUsed *big.Int `tlb:"var uint 7"`
+	CellsUsed *big.Int `tlb:"var uint 7"`
+	BitsUsed  *big.Int `tlb:"var uint 7"`
+
+	// Deprecated: public cells were removed from schema,
+	// it has value only for accounts from old blocks where it was not zero.
+	PublicCellsUsed *big.Int `tlb:"-"`
+}
+
+// StorageExtraInfo is an additional storage info of account
+//
+//	storage_extra_none$000 = StorageExtraInfo;
+//	storage_extra_info$001 dict_hash:uint256 = StorageExtraInfo;
+type StorageExtraInfo struct {
+	// DictHash is a hash of account storage dict, it is used to cache storage stat
+	DictHash []byte
 }
 
+// StorageInfo is a storage stat and payment info of account, both current and old schemas are supported:
+//
+//	storage_info$_ used:StorageUsed storage_extra:StorageExtraInfo last_paid:uint32 due_payment:(Maybe Grams) = StorageInfo;
+//
+// In old schema storage_extra was not present and StorageUsed had public_cells:(VarUInteger 7) field in the same place.
+// Zero public cells have the same representation as storage_extra_none, so both versions are decoded by the 3 bit prefix:
+// 000 - no extra (or zero public cells), 001 - dict hash, others - legacy non-zero public cells.
+// Serialization is done only in current schema, so non-zero public cells cannot be stored.
 type StorageInfo struct {
-	StorageUsed StorageUsed `tlb:"."`
-	LastPaid    uint32      `tlb:"## 32"`
-	DuePayment  *Coins      `tlb:"maybe ."`
+	StorageUsed StorageUsed
+	// has value when storage_extra_info is set
+	StorageExtra *StorageExtraInfo
+	LastPaid     uint32
+	DuePayment   *Coins
+}
+
+const (
+	storageExtraNone = 0b000
+	storageExtraInfo = 0b001
+)
+
+func (s *StorageInfo) LoadFromCell(loader *cell.Slice) error {
+	var used StorageUsed
+	if err := LoadFromCell(&used, loader); err != nil {
+		return fmt.Errorf("failed to load storage used: %w", err)
+	}
+
+	prefix, err := loader.LoadUInt(3)
+	if err != nil {
+		return fmt.Errorf("failed to load storage extra prefix: %w", err)
+	}
+
+	var extra *StorageExtraInfo
+	switch prefix {
+	case storageExtraNone:
+	case storageExtraInfo:
+		hash, err := loader.LoadSlice(256)
+		if err != nil {
+			return fmt.Errorf("failed to load storage dict hash: %w", err)
+		}
+		extra = &StorageExtraInfo{DictHash: hash}
+	default:
+		// legacy schema, prefix is a len of public cells var uint
+		used.PublicCellsUsed, err = loader.LoadBigUInt(uint(prefix) * 8)
+		if err != nil {
+			return fmt.Errorf("failed to load public cells used: %w", err)
+		}
+	}
+
+	lastPaid, err := loader.LoadUInt(32)
+	if err != nil {
+		return fmt.Errorf("failed to load last paid: %w", err)
+	}
+
+	hasDue, err := loader.LoadBoolBit()
+	if err != nil {
+		return fmt.Errorf("failed to load due payment bit: %w", err)
+	}
+
+	var due *Coins
+	if hasDue {
+		coins, err := loader.LoadBigCoins()
+		if err != nil {
+			return fmt.Errorf("failed to load due payment: %w", err)
+		}
+		c := FromNanoTON(coins)
+		due = &c
+	}
+
+	*s = StorageInfo{
+		StorageUsed:  used,
+		StorageExtra: extra,
+		LastPaid:     uint32(lastPaid),
+		DuePayment:   due,
+	}
+	return nil
+}
+
+func (s StorageInfo) ToCell() (*cell.Cell, error) {
+	used, err := ToCell(s.StorageUsed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize storage used: %w", err)
+	}
+
+	b := cell.BeginCell()
+	if err = b.StoreBuilder(used.ToBuilder()); err != nil {
+		return nil, fmt.Errorf("failed to store storage used: %w", err)
+	}
+
+	switch {
+	case s.StorageExtra != nil:
+		if len(s.StorageExtra.DictHash) != 32 {
+			return nil, fmt.Errorf("invalid storage dict hash len %d", len(s.StorageExtra.DictHash))
+		}
+		b.MustStoreUInt(storageExtraInfo, 3).MustStoreSlice(s.StorageExtra.DictHash, 256)
+	case s.StorageUsed.PublicCellsUsed != nil && s.StorageUsed.PublicCellsUsed.Sign() != 0:
+		// legacy schema is only readable, small values would collide with storage_extra_info prefix
+		return nil, fmt.Errorf("public cells used is not supported by current storage schema")
+	default:
+		b.MustStoreUInt(storageExtraNone, 3)
+	}
+
+	b.MustStoreUInt(uint64(s.LastPaid), 32)
+	if s.DuePayment != nil {
+		b.MustStoreBoolBit(true)
+		if err = b.StoreBigCoins(s.DuePayment.Nano()); err != nil {
+			return nil, fmt.Errorf("failed to store due payment: %w", err)
+		}
+	} else {
+		b.MustStoreBoolBit(false)
+	}
+	return b.EndCell(), nil
 }
 
 type AccountState struct {
@@ -215,6 +338,85 @@ func (s *AccountStorage) LoadFromCell(loader *cell.Slice) error {
 	return nil
 }
 
+func (a AccountState) ToCell() (*cell.Cell, error) {
+	if !a.IsValid {
+		// account_none$0
+		return cell.BeginCell().MustStoreBoolBit(false).EndCell(), nil
+	}
+
+	info, err := a.StorageInfo.ToCell()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize storage info: %w", err)
+	}
+
+	store, err := a.AccountStorage.ToCell()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize account storage: %w", err)
+	}
+
+	b := cell.BeginCell().MustStoreBoolBit(true)
+	if err = b.StoreAddr(a.Address); err != nil {
+		return nil, fmt.Errorf("failed to store address: %w", err)
+	}
+	if err = b.StoreBuilder(info.ToBuilder()); err != nil {
+		return nil, fmt.Errorf("failed to store storage info: %w", err)
+	}
+	if err = b.StoreBuilder(store.ToBuilder()); err != nil {
+		return nil, fmt.Errorf("failed to store account storage: %w", err)
+	}
+	return b.EndCell(), nil
+}
+
+func (s AccountStorage) ToCell() (*cell.Cell, error) {
+	b := cell.BeginCell().MustStoreUInt(s.LastTransactionLT, 64)
+	if err := b.StoreBigCoins(s.Balance.Nano()); err != nil {
+		return nil, fmt.Errorf("failed to store balance: %w", err)
+	}
+	if err := b.StoreDict(s.ExtraCurrencies); err != nil {
+		return nil, fmt.Errorf("failed to store extra currencies: %w", err)
+	}
+
+	switch s.Status {
+	case AccountStatusActive:
+		if s.StateInit == nil {
+			return nil, fmt.Errorf("state init should be set for active account")
+		}
+
+		stInit, err := ToCell(s.StateInit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize state init: %w", err)
+		}
+		b.MustStoreBoolBit(true)
+		if err = b.StoreBuilder(stInit.ToBuilder()); err != nil {
+			return nil, fmt.Errorf("failed to store state init: %w", err)
+		}
+	case AccountStatusFrozen:
+		if len(s.StateHash) != 32 {
+			return nil, fmt.Errorf("invalid frozen state hash len %d", len(s.StateHash))
+		}
+		b.MustStoreUInt(0b01, 2).MustStoreSlice(s.StateHash, 256)
+	case AccountStatusUninit:
+		b.MustStoreUInt(0b00, 2)
+	default:
+		return nil, fmt.Errorf("account storage cannot be serialized with status %s", s.Status)
+	}
+	return b.EndCell(), nil
+}
+
+// FrozenHashMatches checks that state init is the one account was frozen with,
+// it can be used to verify state init before sending unfreeze message.
+func (s *AccountStorage) FrozenHashMatches(stateInit *StateInit) (bool, error) {
+	if s.Status != AccountStatusFrozen {
+		return false, fmt.Errorf("account is not frozen")
+	}
+
+	c, err := ToCell(stateInit)
+	if err != nil {
+		return false, fmt.Errorf("failed to serialize state init: %w", err)
+	}
+	return bytes.Equal(c.Hash(), s.StateHash), nil
+}
+
 func (a *Account) HasGetMethod(name string) bool {
 	if a.Code == nil {
 		return false
@@ -1,6 +1,7 @@
 package tlb
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
@@ -211,3 +212,116 @@ func TestAccountStorage_LoadFromCell(t *testing.T) {
 	})
 
 }
+
+func TestAccountState_ToCell(t *testing.T) {
+	accStateBOC, _ := hex.DecodeString("b5ee9c724101030100d700026fc00c419e2b8a3b6cd81acd3967dbbaf4442e1870e99eaf32278b7814a6ccaac5f802068148c314b1854000006735d812370d00764ce8d340010200deff0020dd2082014c97ba218201339cbab19f71b0ed44d0d31fd31f31d70bffe304e0a4f2608308d71820d31fd31fd31ff82313bbf263ed44d0d31fd31fd3ffd15132baf2a15144baf2a204f901541055f910f2a3f8009320d74a96d307d402fb00e8d101a4c8cb1fcb1fcbffc9ed5400500000000229a9a317d78e2ef9e6572eeaa3f206ae5c3dd4d00ddd2ffa771196dc0ab985fa84daf451c340d7fa")
+	acc, err := cell.FromBOC(accStateBOC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var as AccountState
+	if err = as.LoadFromCell(acc.BeginParse()); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := as.ToCell()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(c.Hash(), acc.Hash()) {
+		t.Fatal("hash not match after serialization")
+	}
+
+	hash := make([]byte, 32)
+	hash[0] = 0xAA
+
+	due := MustFromTON("0.1")
+	for _, info := range []StorageInfo{
+		{
+			StorageUsed:  StorageUsed{CellsUsed: big.NewInt(10), BitsUsed: big.NewInt(1000)},
+			StorageExtra: &StorageExtraInfo{DictHash: hash},
+			LastPaid:     100,
+			DuePayment:   &due,
+		},
+		{
+			StorageUsed: StorageUsed{CellsUsed: big.NewInt(10), BitsUsed: big.NewInt(1000), PublicCellsUsed: big.NewInt(0)},
+			LastPaid:    100,
+		},
+	} {
+		frozen := AccountState{
+			IsValid:     true,
+			Address:     as.Address,
+			StorageInfo: info,
+			AccountStorage: AccountStorage{
+				Status:            AccountStatusFrozen,
+				LastTransactionLT: 777,
+				Balance:           MustFromTON("1"),
+				StateHash:         hash,
+			},
+		}
+
+		c, err = frozen.ToCell()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var as2 AccountState
+		if err = as2.LoadFromCell(c.BeginParse()); err != nil {
+			t.Fatal(err)
+		}
+
+		if as2.Status != AccountStatusFrozen || !bytes.Equal(as2.StateHash, hash) || as2.LastTransactionLT != 777 ||
+			as2.StorageInfo.LastPaid != 100 || as2.StorageInfo.StorageUsed.BitsUsed.Uint64() != 1000 {
+			t.Fatal("incorrect account after round trip")
+		}
+
+		if info.StorageExtra != nil {
+			if as2.StorageInfo.StorageExtra == nil || !bytes.Equal(as2.StorageInfo.StorageExtra.DictHash, hash) ||
+				as2.StorageInfo.DuePayment.Nano().Cmp(due.Nano()) != 0 {
+				t.Fatal("incorrect storage extra")
+			}
+		} else if as2.StorageInfo.StorageExtra != nil || as2.StorageInfo.DuePayment != nil {
+			t.Fatal("incorrect storage without extra")
+		}
+
+		c2, err := as2.ToCell()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(c2.Hash(), c.Hash()) {
+			t.Fatal("hash not match after round trip")
+		}
+	}
+
+	// non-zero public cells exist only in legacy schema, they can be loaded but not stored
+	for _, publicCells := range []int64{1, 255, 300} {
+		legacy := StorageInfo{
+			StorageUsed: StorageUsed{CellsUsed: big.NewInt(10), BitsUsed: big.NewInt(1000), PublicCellsUsed: big.NewInt(publicCells)},
+			LastPaid:    100,
+		}
+		if _, err = legacy.ToCell(); err == nil {
+			t.Fatal("should be error for public cells", publicCells)
+		}
+	}
+
+	used, _ := ToCell(StorageUsed{CellsUsed: big.NewInt(10), BitsUsed: big.NewInt(1000)})
+	legacyCell := cell.BeginCell().MustStoreBuilder(used.ToBuilder()).
+		MustStoreBigVarUInt(big.NewInt(300), 7).MustStoreUInt(100, 32).MustStoreBoolBit(false).EndCell()
+
+	var legacy StorageInfo
+	if err = legacy.LoadFromCell(legacyCell.BeginParse()); err != nil {
+		t.Fatal(err)
+	}
+	if legacy.StorageExtra != nil || legacy.StorageUsed.PublicCellsUsed.Uint64() != 300 || legacy.LastPaid != 100 {
+		t.Fatal("incorrect legacy storage info")
+	}
+
+	stInit := &StateInit{Code: cell.BeginCell().EndCell()}
+	stInitCell, _ := ToCell(stInit)
+	frozen := AccountStorage{Status: AccountStatusFrozen, StateHash: stInitCell.Hash()}
+	if ok, err := frozen.FrozenHashMatches(stInit); err != nil || !ok {
+		t.Fatal("frozen hash should match", err)
+	}
+}
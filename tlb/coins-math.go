@@ -0,0 +1,174 @@
+package tlb
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// RoundingMode defines how results of inexact coins operations are rounded
+type RoundingMode int
+
+const (
+	// RoundDown truncates the fractional part
+	RoundDown RoundingMode = iota
+	// RoundUp rounds away from zero when there is any fractional part
+	RoundUp
+	// RoundHalfUp rounds to the nearest value, ties are rounded up
+	RoundHalfUp
+	// RoundHalfEven rounds to the nearest value, ties are rounded to the even one
+	RoundHalfEven
+	// RoundExact fails with ErrInexactCoins when rounding is required
+	RoundExact
+)
+
+// MaxCoinsBits is the max size of coins value, VarUInteger 16 can hold at most 15 bytes
+const MaxCoinsBits = 120
+
+var (
+	ErrCoinsOverflow         = errors.New("coins value overflows 120 bits")
+	ErrNegativeCoins         = errors.New("coins value cannot be negative")
+	ErrInexactCoins          = errors.New("coins value cannot be represented without rounding")
+	ErrCoinsDecimalsMismatch = errors.New("coins decimals are not equal")
+)
+
+func coinsChecked(val *big.Int, decimals int) (Coins, error) {
+	if val.Sign() < 0 {
+		return Coins{}, ErrNegativeCoins
+	}
+	if val.BitLen() > MaxCoinsBits {
+		return Coins{}, ErrCoinsOverflow
+	}
+	return Coins{decimals: decimals, val: val}, nil
+}
+
+// commonDecimals returns decimals to use for operation on both values,
+// zero value Coins{} is compatible with any decimals.
+func (g Coins) commonDecimals(other Coins) (int, error) {
+	switch {
+	case g.decimals == other.decimals:
+		return g.decimals, nil
+	case g.val == nil && g.decimals == 0:
+		return other.decimals, nil
+	case other.val == nil && other.decimals == 0:
+		return g.decimals, nil
+	}
+	return 0, fmt.Errorf("%w: %d and %d", ErrCoinsDecimalsMismatch, g.decimals, other.decimals)
+}
+
+// Add returns sum of values, both values must have the same decimals
+func (g Coins) Add(other Coins) (Coins, error) {
+	decimals, err := g.commonDecimals(other)
+	if err != nil {
+		return Coins{}, err
+	}
+	return coinsChecked(new(big.Int).Add(g.Nano(), other.Nano()), decimals)
+}
+
+// Sub returns difference of values, ErrNegativeCoins is returned when other is greater
+func (g Coins) Sub(other Coins) (Coins, error) {
+	decimals, err := g.commonDecimals(other)
+	if err != nil {
+		return Coins{}, err
+	}
+	return coinsChecked(new(big.Int).Sub(g.Nano(), other.Nano()), decimals)
+}
+
+// Mul returns value multiplied by non-negative integer
+func (g Coins) Mul(n *big.Int) (Coins, error) {
+	return coinsChecked(new(big.Int).Mul(g.Nano(), n), g.decimals)
+}
+
+// MulRat returns value multiplied by num/denom, result is rounded using mode
+func (g Coins) MulRat(num, denom *big.Int, mode RoundingMode) (Coins, error) {
+	if num.Sign() < 0 {
+		return Coins{}, ErrNegativeCoins
+	}
+
+	res, err := divRound(new(big.Int).Mul(g.Nano(), num), denom, mode)
+	if err != nil {
+		return Coins{}, err
+	}
+	return coinsChecked(res, g.decimals)
+}
+
+// Div returns value divided by positive integer, result is rounded using mode
+func (g Coins) Div(denom *big.Int, mode RoundingMode) (Coins, error) {
+	return g.MulRat(big.NewInt(1), denom, mode)
+}
+
+// WithDecimals converts value to another decimals precision, preserving the amount,
+// when precision is reduced result is rounded using mode
+func (g Coins) WithDecimals(decimals int, mode RoundingMode) (Coins, error) {
+	if decimals < 0 || decimals >= 128 {
+		return Coins{}, fmt.Errorf("invalid decimals")
+	}
+
+	val := g.Nano()
+	if decimals >= g.decimals {
+		return coinsChecked(val.Mul(val, pow10(decimals-g.decimals)), decimals)
+	}
+
+	res, err := divRound(val, pow10(g.decimals-decimals), mode)
+	if err != nil {
+		return Coins{}, err
+	}
+	return coinsChecked(res, decimals)
+}
+
+// Cmp compares amounts of values, decimals can be different, comparison is exact.
+// Returns -1 if g < other, 0 if equal and +1 if g > other.
+func (g Coins) Cmp(other Coins) int {
+	a, b := g.Nano(), other.Nano()
+	if g.decimals > other.decimals {
+		b.Mul(b, pow10(g.decimals-other.decimals))
+	} else if g.decimals < other.decimals {
+		a.Mul(a, pow10(other.decimals-g.decimals))
+	}
+	return a.Cmp(b)
+}
+
+// Equals returns true when amounts are equal, decimals can be different
+func (g Coins) Equals(other Coins) bool {
+	return g.Cmp(other) == 0
+}
+
+// IsZero returns true when amount is zero
+func (g Coins) IsZero() bool {
+	return g.val == nil || g.val.Sign() == 0
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// divRound divides non-negative num by positive denom using rounding mode
+func divRound(num, denom *big.Int, mode RoundingMode) (*big.Int, error) {
+	if denom.Sign() <= 0 {
+		return nil, fmt.Errorf("denominator should be positive")
+	}
+
+	q, r := new(big.Int).QuoRem(num, denom, new(big.Int))
+	if r.Sign() == 0 {
+		return q, nil
+	}
+
+	var inc bool
+	switch mode {
+	case RoundDown:
+	case RoundUp:
+		inc = true
+	case RoundHalfUp, RoundHalfEven:
+		c := r.Lsh(r, 1).Cmp(denom)
+		inc = c > 0 || (c == 0 && (mode == RoundHalfUp || q.Bit(0) == 1))
+	case RoundExact:
+		return nil, ErrInexactCoins
+	default:
+		return nil, fmt.Errorf("unknown rounding mode %d", mode)
+	}
+
+	if inc {
+		q.Add(q, big.NewInt(1))
+	}
+	return q, nil
+}
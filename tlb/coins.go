@@ -224,12 +224,9 @@ func (g *Coins) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Compare is the same as Cmp, values with different decimals are compared by amount
 func (g *Coins) Compare(coins *Coins) int {
-	if g.decimals != coins.decimals {
-		panic("invalid comparsion")
-	}
-
-	return g.Nano().Cmp(coins.Nano())
+	return g.Cmp(*coins)
 }
 
 func (g *Coins) Decimals() int {
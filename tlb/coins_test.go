@@ -2,6 +2,7 @@ package tlb
 
 import (
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"math/big"
 	"reflect"
@@ -484,3 +485,110 @@ func TestCoins_ValueScanRoundTrip(t *testing.T) {
 		})
 	}
 }
+
+func TestCoins_Arithmetic(t *testing.T) {
+	a := MustFromTON("1.5")
+	b := MustFromTON("0.25")
+
+	sum, err := a.Add(b)
+	if err != nil || sum.String() != "1.75" || sum.Decimals() != 9 {
+		t.Fatal("incorrect sum", sum.String(), err)
+	}
+
+	diff, err := a.Sub(b)
+	if err != nil || diff.String() != "1.25" {
+		t.Fatal("incorrect diff", diff.String(), err)
+	}
+
+	if _, err = b.Sub(a); err != ErrNegativeCoins {
+		t.Fatal("expected negative error", err)
+	}
+
+	if _, err = a.Add(MustFromDecimal("1", 6)); !errors.Is(err, ErrCoinsDecimalsMismatch) {
+		t.Fatal("expected decimals mismatch", err)
+	}
+
+	sum, err = Coins{}.Add(MustFromDecimal("1", 6))
+	if err != nil || sum.Decimals() != 6 || sum.String() != "1" {
+		t.Fatal("zero value should be compatible", err)
+	}
+
+	maxCoins := MustFromNano(new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), MaxCoinsBits), big.NewInt(1)), 9)
+	if _, err = maxCoins.Add(MustFromNano(big.NewInt(1), 9)); err != ErrCoinsOverflow {
+		t.Fatal("expected overflow", err)
+	}
+
+	if _, err = maxCoins.Mul(big.NewInt(2)); err != ErrCoinsOverflow {
+		t.Fatal("expected overflow", err)
+	}
+
+	if _, err = a.Mul(big.NewInt(-1)); err != ErrNegativeCoins {
+		t.Fatal("expected negative error", err)
+	}
+
+	x := MustFromNano(big.NewInt(10), 0)
+	for _, tt := range []struct {
+		mode RoundingMode
+		num  int64
+		den  int64
+		want string
+		err  error
+	}{
+		{RoundDown, 1, 4, "2", nil},
+		{RoundUp, 1, 4, "3", nil},
+		{RoundHalfUp, 1, 4, "3", nil},
+		{RoundHalfEven, 1, 4, "2", nil},
+		{RoundHalfEven, 3, 4, "8", nil},
+		{RoundHalfEven, 1, 3, "3", nil},
+		{RoundHalfUp, 2, 3, "7", nil},
+		{RoundExact, 1, 2, "5", nil},
+		{RoundExact, 1, 3, "", ErrInexactCoins},
+	} {
+		res, err := x.MulRat(big.NewInt(tt.num), big.NewInt(tt.den), tt.mode)
+		if err != tt.err {
+			t.Fatal("unexpected error", tt, err)
+		}
+		if err == nil && res.String() != tt.want {
+			t.Fatal("incorrect result", tt, res.String())
+		}
+	}
+
+	if _, err = x.Div(big.NewInt(0), RoundDown); err == nil {
+		t.Fatal("division by zero should fail")
+	}
+}
+
+func TestCoins_WithDecimals(t *testing.T) {
+	a := MustFromTON("1.123456789")
+
+	b, err := a.WithDecimals(6, RoundDown)
+	if err != nil || b.String() != "1.123456" || b.Decimals() != 6 {
+		t.Fatal("incorrect down conversion", b.String(), err)
+	}
+
+	b, err = a.WithDecimals(6, RoundHalfUp)
+	if err != nil || b.String() != "1.123457" {
+		t.Fatal("incorrect half up conversion", b.String(), err)
+	}
+
+	if _, err = a.WithDecimals(6, RoundExact); err != ErrInexactCoins {
+		t.Fatal("expected inexact error", err)
+	}
+
+	c, err := a.WithDecimals(18, RoundExact)
+	if err != nil || c.String() != "1.123456789" || c.Nano().String() != "1123456789000000000" {
+		t.Fatal("incorrect up conversion", c.String(), err)
+	}
+
+	if !c.Equals(a) || c.Cmp(b) != -1 || b.Cmp(c) != 1 {
+		t.Fatal("incorrect cross decimals comparison")
+	}
+
+	if c.Compare(&b) != c.Cmp(b) || a.Compare(&c) != 0 {
+		t.Fatal("compare should match cmp")
+	}
+
+	if !(Coins{}).IsZero() || a.IsZero() {
+		t.Fatal("incorrect is zero")
+	}
+}
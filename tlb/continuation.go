@@ -0,0 +1,582 @@
+package tlb
+
+import (
+	"fmt"
+
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+/*
+vm_ctl_data$_ nargs:(Maybe uint13) stack:(Maybe VmStack) save:VmSaveList cp:(Maybe int16) = VmControlData;
+_ cregs:(HashmapE 4 VmStackValue) = VmSaveList;
+
+vmc_std$00 cdata:VmControlData code:VmCellSlice = VmCont;
+vmc_envelope$01 cdata:VmControlData next:^VmCont = VmCont;
+vmc_quit$1000 exit_code:int32 = VmCont;
+vmc_quit_exc$1001 = VmCont;
+vmc_repeat$10100 count:uint63 body:^VmCont after:^VmCont = VmCont;
+vmc_until$110000 body:^VmCont after:^VmCont = VmCont;
+vmc_again$110001 body:^VmCont = VmCont;
+vmc_while_cond$110010 cond:^VmCont body:^VmCont after:^VmCont = VmCont;
+vmc_while_body$110011 cond:^VmCont body:^VmCont after:^VmCont = VmCont;
+vmc_pushint$1111 value:int32 next:^VmCont = VmCont;
+*/
+
+// VmCont is a TVM continuation, it is one of:
+// *VmContStd, *VmContEnvelope, *VmContQuit, *VmContQuitExc, *VmContRepeat,
+// *VmContUntil, *VmContAgain, *VmContWhile, *VmContPushInt
+type VmCont interface {
+	ToCell() (*cell.Cell, error)
+	vmCont()
+}
+
+// VmSaveList is a set of saved control registers, key is a register index (c0 = 0, c7 = 7),
+// values are stack values: continuations, cells or tuple
+type VmSaveList map[uint8]any
+
+type VmControlData struct {
+	// NumArgs is a number of arguments continuation expects, nil when any
+	NumArgs *uint16
+	Stack   *Stack
+	Save    VmSaveList
+	// CP is a codepage, nil when not set
+	CP *int16
+}
+
+type VmContStd struct {
+	Data VmControlData
+	Code *cell.Slice
+}
+
+type VmContEnvelope struct {
+	Data VmControlData
+	Next VmCont
+}
+
+type VmContQuit struct {
+	ExitCode int32
+}
+
+type VmContQuitExc struct{}
+
+type VmContRepeat struct {
+	Count uint64
+	Body  VmCont
+	After VmCont
+}
+
+type VmContUntil struct {
+	Body  VmCont
+	After VmCont
+}
+
+type VmContAgain struct {
+	Body VmCont
+}
+
+type VmContWhile struct {
+	// InBody is true for vmc_while_body (condition is already checked), false for vmc_while_cond
+	InBody bool
+	Cond   VmCont
+	Body   VmCont
+	After  VmCont
+}
+
+type VmContPushInt struct {
+	Value int32
+	Next  VmCont
+}
+
+type vmContPrefix struct {
+	prefix uint64
+	bits   uint
+	create func() vmContLoader
+}
+
+type vmContLoader interface {
+	VmCont
+	LoadFromCell(loader *cell.Slice) error
+}
+
+var vmContPrefixes = []vmContPrefix{
+	{0b00, 2, func() vmContLoader { return &VmContStd{} }},
+	{0b01, 2, func() vmContLoader { return &VmContEnvelope{} }},
+	{0b1000, 4, func() vmContLoader { return &VmContQuit{} }},
+	{0b1001, 4, func() vmContLoader { return &VmContQuitExc{} }},
+	{0b10100, 5, func() vmContLoader { return &VmContRepeat{} }},
+	{0b110000, 6, func() vmContLoader { return &VmContUntil{} }},
+	{0b110001, 6, func() vmContLoader { return &VmContAgain{} }},
+	{0b110010, 6, func() vmContLoader { return &VmContWhile{} }},
+	{0b110011, 6, func() vmContLoader { return &VmContWhile{} }},
+	{0b1111, 4, func() vmContLoader { return &VmContPushInt{} }},
+}
+
+// ParseVmCont loads continuation of any type
+func ParseVmCont(loader *cell.Slice) (VmCont, error) {
+	for _, p := range vmContPrefixes {
+		if loader.BitsLeft() < p.bits {
+			continue
+		}
+
+		pfx, err := loader.PreloadUInt(p.bits)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load continuation prefix: %w", err)
+		}
+
+		if pfx == p.prefix {
+			c := p.create()
+			if err = c.LoadFromCell(loader); err != nil {
+				return nil, err
+			}
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown continuation type")
+}
+
+func (c *VmContStd) vmCont()      {}
+func (c *VmContEnvelope) vmCont() {}
+func (c *VmContQuit) vmCont()     {}
+func (c *VmContQuitExc) vmCont()  {}
+func (c *VmContRepeat) vmCont()   {}
+func (c *VmContUntil) vmCont()    {}
+func (c *VmContAgain) vmCont()    {}
+func (c *VmContWhile) vmCont()    {}
+func (c *VmContPushInt) vmCont()  {}
+
+func (c *VmContStd) LoadFromCell(loader *cell.Slice) error {
+	if err := loadVmContPrefix(loader, 0b00, 2); err != nil {
+		return err
+	}
+
+	var data VmControlData
+	if err := data.LoadFromCell(loader); err != nil {
+		return fmt.Errorf("failed to load control data: %w", err)
+	}
+
+	code, err := loadVmCellSlice(loader)
+	if err != nil {
+		return fmt.Errorf("failed to load code: %w", err)
+	}
+
+	*c = VmContStd{Data: data, Code: code}
+	return nil
+}
+
+func (c *VmContStd) ToCell() (*cell.Cell, error) {
+	if c.Code == nil {
+		return nil, fmt.Errorf("code is nil")
+	}
+
+	b := cell.BeginCell().MustStoreUInt(0b00, 2)
+	if err := c.Data.store(b); err != nil {
+		return nil, err
+	}
+	storeVmCellSlice(b, c.Code)
+	return b.EndCell(), nil
+}
+
+func (c *VmContEnvelope) LoadFromCell(loader *cell.Slice) error {
+	if err := loadVmContPrefix(loader, 0b01, 2); err != nil {
+		return err
+	}
+
+	var data VmControlData
+	if err := data.LoadFromCell(loader); err != nil {
+		return fmt.Errorf("failed to load control data: %w", err)
+	}
+
+	next, err := loadVmContRef(loader, "next")
+	if err != nil {
+		return err
+	}
+
+	*c = VmContEnvelope{Data: data, Next: next}
+	return nil
+}
+
+func (c *VmContEnvelope) ToCell() (*cell.Cell, error) {
+	b := cell.BeginCell().MustStoreUInt(0b01, 2)
+	if err := c.Data.store(b); err != nil {
+		return nil, err
+	}
+	if err := storeVmContRef(b, c.Next, "next"); err != nil {
+		return nil, err
+	}
+	return b.EndCell(), nil
+}
+
+func (c *VmContQuit) LoadFromCell(loader *cell.Slice) error {
+	if err := loadVmContPrefix(loader, 0b1000, 4); err != nil {
+		return err
+	}
+
+	code, err := loader.LoadInt(32)
+	if err != nil {
+		return fmt.Errorf("failed to load exit code: %w", err)
+	}
+	c.ExitCode = int32(code)
+	return nil
+}
+
+func (c *VmContQuit) ToCell() (*cell.Cell, error) {
+	return cell.BeginCell().MustStoreUInt(0b1000, 4).MustStoreInt(int64(c.ExitCode), 32).EndCell(), nil
+}
+
+func (c *VmContQuitExc) LoadFromCell(loader *cell.Slice) error {
+	return loadVmContPrefix(loader, 0b1001, 4)
+}
+
+func (c *VmContQuitExc) ToCell() (*cell.Cell, error) {
+	return cell.BeginCell().MustStoreUInt(0b1001, 4).EndCell(), nil
+}
+
+func (c *VmContRepeat) LoadFromCell(loader *cell.Slice) error {
+	if err := loadVmContPrefix(loader, 0b10100, 5); err != nil {
+		return err
+	}
+
+	count, err := loader.LoadUInt(63)
+	if err != nil {
+		return fmt.Errorf("failed to load count: %w", err)
+	}
+
+	body, err := loadVmContRef(loader, "body")
+	if err != nil {
+		return err
+	}
+
+	after, err := loadVmContRef(loader, "after")
+	if err != nil {
+		return err
+	}
+
+	*c = VmContRepeat{Count: count, Body: body, After: after}
+	return nil
+}
+
+func (c *VmContRepeat) ToCell() (*cell.Cell, error) {
+	if c.Count >= 1<<63 {
+		return nil, fmt.Errorf("too big repeat count")
+	}
+
+	b := cell.BeginCell().MustStoreUInt(0b10100, 5).MustStoreUInt(c.Count, 63)
+	if err := storeVmContRef(b, c.Body, "body"); err != nil {
+		return nil, err
+	}
+	if err := storeVmContRef(b, c.After, "after"); err != nil {
+		return nil, err
+	}
+	return b.EndCell(), nil
+}
+
+func (c *VmContUntil) LoadFromCell(loader *cell.Slice) error {
+	if err := loadVmContPrefix(loader, 0b110000, 6); err != nil {
+		return err
+	}
+
+	body, err := loadVmContRef(loader, "body")
+	if err != nil {
+		return err
+	}
+
+	after, err := loadVmContRef(loader, "after")
+	if err != nil {
+		return err
+	}
+
+	*c = VmContUntil{Body: body, After: after}
+	return nil
+}
+
+func (c *VmContUntil) ToCell() (*cell.Cell, error) {
+	b := cell.BeginCell().MustStoreUInt(0b110000, 6)
+	if err := storeVmContRef(b, c.Body, "body"); err != nil {
+		return nil, err
+	}
+	if err := storeVmContRef(b, c.After, "after"); err != nil {
+		return nil, err
+	}
+	return b.EndCell(), nil
+}
+
+func (c *VmContAgain) LoadFromCell(loader *cell.Slice) error {
+	if err := loadVmContPrefix(loader, 0b110001, 6); err != nil {
+		return err
+	}
+
+	body, err := loadVmContRef(loader, "body")
+	if err != nil {
+		return err
+	}
+
+	c.Body = body
+	return nil
+}
+
+func (c *VmContAgain) ToCell() (*cell.Cell, error) {
+	b := cell.BeginCell().MustStoreUInt(0b110001, 6)
+	if err := storeVmContRef(b, c.Body, "body"); err != nil {
+		return nil, err
+	}
+	return b.EndCell(), nil
+}
+
+func (c *VmContWhile) LoadFromCell(loader *cell.Slice) error {
+	pfx, err := loader.LoadUInt(6)
+	if err != nil {
+		return fmt.Errorf("failed to load continuation prefix: %w", err)
+	}
+	if pfx != 0b110010 && pfx != 0b110011 {
+		return fmt.Errorf("incorrect while continuation prefix %b", pfx)
+	}
+
+	cond, err := loadVmContRef(loader, "cond")
+	if err != nil {
+		return err
+	}
+
+	body, err := loadVmContRef(loader, "body")
+	if err != nil {
+		return err
+	}
+
+	after, err := loadVmContRef(loader, "after")
+	if err != nil {
+		return err
+	}
+
+	*c = VmContWhile{InBody: pfx == 0b110011, Cond: cond, Body: body, After: after}
+	return nil
+}
+
+func (c *VmContWhile) ToCell() (*cell.Cell, error) {
+	pfx := uint64(0b110010)
+	if c.InBody {
+		pfx = 0b110011
+	}
+
+	b := cell.BeginCell().MustStoreUInt(pfx, 6)
+	if err := storeVmContRef(b, c.Cond, "cond"); err != nil {
+		return nil, err
+	}
+	if err := storeVmContRef(b, c.Body, "body"); err != nil {
+		return nil, err
+	}
+	if err := storeVmContRef(b, c.After, "after"); err != nil {
+		return nil, err
+	}
+	return b.EndCell(), nil
+}
+
+func (c *VmContPushInt) LoadFromCell(loader *cell.Slice) error {
+	if err := loadVmContPrefix(loader, 0b1111, 4); err != nil {
+		return err
+	}
+
+	val, err := loader.LoadInt(32)
+	if err != nil {
+		return fmt.Errorf("failed to load value: %w", err)
+	}
+
+	next, err := loadVmContRef(loader, "next")
+	if err != nil {
+		return err
+	}
+
+	*c = VmContPushInt{Value: int32(val), Next: next}
+	return nil
+}
+
+func (c *VmContPushInt) ToCell() (*cell.Cell, error) {
+	b := cell.BeginCell().MustStoreUInt(0b1111, 4).MustStoreInt(int64(c.Value), 32)
+	if err := storeVmContRef(b, c.Next, "next"); err != nil {
+		return nil, err
+	}
+	return b.EndCell(), nil
+}
+
+func (d *VmControlData) LoadFromCell(loader *cell.Slice) error {
+	var res VmControlData
+
+	hasArgs, err := loader.LoadBoolBit()
+	if err != nil {
+		return fmt.Errorf("failed to load nargs bit: %w", err)
+	}
+	if hasArgs {
+		nargs, err := loader.LoadUInt(13)
+		if err != nil {
+			return fmt.Errorf("failed to load nargs: %w", err)
+		}
+		n := uint16(nargs)
+		res.NumArgs = &n
+	}
+
+	hasStack, err := loader.LoadBoolBit()
+	if err != nil {
+		return fmt.Errorf("failed to load stack bit: %w", err)
+	}
+	if hasStack {
+		res.Stack = NewStack()
+		if err = res.Stack.LoadFromCell(loader); err != nil {
+			return fmt.Errorf("failed to load stack: %w", err)
+		}
+	}
+
+	if res.Save, err = loadVmSaveList(loader); err != nil {
+		return err
+	}
+
+	hasCP, err := loader.LoadBoolBit()
+	if err != nil {
+		return fmt.Errorf("failed to load cp bit: %w", err)
+	}
+	if hasCP {
+		cp, err := loader.LoadInt(16)
+		if err != nil {
+			return fmt.Errorf("failed to load cp: %w", err)
+		}
+		v := int16(cp)
+		res.CP = &v
+	}
+
+	*d = res
+	return nil
+}
+
+func (d VmControlData) ToCell() (*cell.Cell, error) {
+	b := cell.BeginCell()
+	if err := d.store(b); err != nil {
+		return nil, err
+	}
+	return b.EndCell(), nil
+}
+
+func (d *VmControlData) store(b *cell.Builder) error {
+	if d.NumArgs != nil {
+		if *d.NumArgs >= 1<<13 {
+			return fmt.Errorf("too big nargs %d", *d.NumArgs)
+		}
+		b.MustStoreBoolBit(true).MustStoreUInt(uint64(*d.NumArgs), 13)
+	} else {
+		b.MustStoreBoolBit(false)
+	}
+
+	if d.Stack != nil {
+		st, err := d.Stack.ToCell()
+		if err != nil {
+			return fmt.Errorf("failed to serialize stack: %w", err)
+		}
+		b.MustStoreBoolBit(true)
+		if err = b.StoreBuilder(st.ToBuilder()); err != nil {
+			return fmt.Errorf("failed to store stack: %w", err)
+		}
+	} else {
+		b.MustStoreBoolBit(false)
+	}
+
+	if err := storeVmSaveList(b, d.Save); err != nil {
+		return err
+	}
+
+	if d.CP != nil {
+		b.MustStoreBoolBit(true).MustStoreInt(int64(*d.CP), 16)
+	} else {
+		b.MustStoreBoolBit(false)
+	}
+	return nil
+}
+
+func loadVmSaveList(loader *cell.Slice) (VmSaveList, error) {
+	dict, err := loader.LoadDict(4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load save list: %w", err)
+	}
+
+	kvs, err := dict.LoadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load save list values: %w", err)
+	}
+
+	if len(kvs) == 0 {
+		return nil, nil
+	}
+
+	save := VmSaveList{}
+	for _, kv := range kvs {
+		idx, err := kv.Key.LoadUInt(4)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load save list key: %w", err)
+		}
+
+		val, err := ParseStackValue(kv.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse c%d value: %w", idx, err)
+		}
+		save[uint8(idx)] = val
+	}
+	return save, nil
+}
+
+func storeVmSaveList(b *cell.Builder, save VmSaveList) error {
+	dict := cell.NewDict(4)
+	for idx, val := range save {
+		if idx > 15 {
+			return fmt.Errorf("invalid control register index %d", idx)
+		}
+
+		vb := cell.BeginCell()
+		if err := SerializeStackValue(vb, val); err != nil {
+			return fmt.Errorf("failed to serialize c%d value: %w", idx, err)
+		}
+
+		if err := dict.Set(cell.BeginCell().MustStoreUInt(uint64(idx), 4).EndCell(), vb.EndCell()); err != nil {
+			return fmt.Errorf("failed to set c%d value: %w", idx, err)
+		}
+	}
+
+	if err := b.StoreDict(dict); err != nil {
+		return fmt.Errorf("failed to store save list: %w", err)
+	}
+	return nil
+}
+
+func loadVmContPrefix(loader *cell.Slice, prefix uint64, bits uint) error {
+	pfx, err := loader.LoadUInt(bits)
+	if err != nil {
+		return fmt.Errorf("failed to load continuation prefix: %w", err)
+	}
+	if pfx != prefix {
+		return fmt.Errorf("incorrect continuation prefix %b, want %b", pfx, prefix)
+	}
+	return nil
+}
+
+func loadVmContRef(loader *cell.Slice, name string) (VmCont, error) {
+	ref, err := loader.LoadRef()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s continuation ref: %w", name, err)
+	}
+
+	c, err := ParseVmCont(ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s continuation: %w", name, err)
+	}
+	return c, nil
+}
+
+func storeVmContRef(b *cell.Builder, c VmCont, name string) error {
+	if c == nil {
+		return fmt.Errorf("%s continuation is nil", name)
+	}
+
+	cl, err := c.ToCell()
+	if err != nil {
+		return fmt.Errorf("failed to serialize %s continuation: %w", name, err)
+	}
+
+	if err = b.StoreRef(cl); err != nil {
+		return fmt.Errorf("failed to store %s continuation: %w", name, err)
+	}
+	return nil
+}
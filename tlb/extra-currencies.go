@@ -0,0 +1,243 @@
+package tlb
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+// ExtraCurrencies is a typed view of extra currencies dictionary, currency id -> amount.
+//
+//	extra_currencies$_ dict:(HashmapE 32 (VarUInteger 32)) = ExtraCurrencyCollection;
+type ExtraCurrencies map[uint32]*big.Int
+
+// ExtraCurrenciesFromDict parses extra currencies dictionary, nil dictionary is treated as empty
+func ExtraCurrenciesFromDict(dict *cell.Dictionary) (ExtraCurrencies, error) {
+	res := ExtraCurrencies{}
+	if dict == nil || dict.IsEmpty() {
+		return res, nil
+	}
+
+	kvs, err := dict.LoadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load extra currencies dict: %w", err)
+	}
+
+	for _, kv := range kvs {
+		id, err := kv.Key.LoadUInt(32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load currency id: %w", err)
+		}
+
+		amount, err := kv.Value.LoadVarUInt(32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load amount of currency %d: %w", id, err)
+		}
+		res[uint32(id)] = amount
+	}
+	return res, nil
+}
+
+// ToDict serializes extra currencies to dictionary, zero amounts are skipped
+func (e ExtraCurrencies) ToDict() (*cell.Dictionary, error) {
+	dict := cell.NewDict(32)
+	for id, amount := range e {
+		if amount == nil || amount.Sign() == 0 {
+			continue
+		}
+		if amount.Sign() < 0 {
+			return nil, fmt.Errorf("negative amount of currency %d", id)
+		}
+
+		val := cell.BeginCell()
+		if err := val.StoreBigVarUInt(amount, 32); err != nil {
+			return nil, fmt.Errorf("failed to store amount of currency %d: %w", id, err)
+		}
+
+		if err := dict.Set(cell.BeginCell().MustStoreUInt(uint64(id), 32).EndCell(), val.EndCell()); err != nil {
+			return nil, fmt.Errorf("failed to set currency %d: %w", id, err)
+		}
+	}
+	return dict, nil
+}
+
+// Get returns amount of currency, zero if it is not present
+func (e ExtraCurrencies) Get(id uint32) *big.Int {
+	if v := e[id]; v != nil {
+		return new(big.Int).Set(v)
+	}
+	return big.NewInt(0)
+}
+
+// IsEmpty returns true when there are no currencies with non-zero amount
+func (e ExtraCurrencies) IsEmpty() bool {
+	for _, v := range e {
+		if v != nil && v.Sign() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IDs returns sorted ids of currencies with non-zero amount
+func (e ExtraCurrencies) IDs() []uint32 {
+	ids := make([]uint32, 0, len(e))
+	for id, v := range e {
+		if v != nil && v.Sign() != 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func (e ExtraCurrencies) Copy() ExtraCurrencies {
+	res := make(ExtraCurrencies, len(e))
+	for id, v := range e {
+		if v != nil && v.Sign() != 0 {
+			res[id] = new(big.Int).Set(v)
+		}
+	}
+	return res
+}
+
+// Add returns sum of both collections
+func (e ExtraCurrencies) Add(other ExtraCurrencies) ExtraCurrencies {
+	res := e.Copy()
+	for id, v := range other {
+		if v == nil || v.Sign() == 0 {
+			continue
+		}
+		res[id] = new(big.Int).Add(res.Get(id), v)
+	}
+	return res
+}
+
+// Sub returns difference of collections, error is returned when any amount becomes negative
+func (e ExtraCurrencies) Sub(other ExtraCurrencies) (ExtraCurrencies, error) {
+	res := e.Copy()
+	for id, v := range other {
+		if v == nil || v.Sign() == 0 {
+			continue
+		}
+
+		left := new(big.Int).Sub(res.Get(id), v)
+		if left.Sign() < 0 {
+			return nil, fmt.Errorf("not enough amount of currency %d", id)
+		}
+
+		if left.Sign() == 0 {
+			delete(res, id)
+			continue
+		}
+		res[id] = left
+	}
+	return res, nil
+}
+
+// Equals returns true when both collections have the same non-zero amounts
+func (e ExtraCurrencies) Equals(other ExtraCurrencies) bool {
+	return e.Covers(other) && other.Covers(e)
+}
+
+// Covers returns true when amount of each currency is greater or equal than in other collection
+func (e ExtraCurrencies) Covers(other ExtraCurrencies) bool {
+	for id, v := range other {
+		if v != nil && e.Get(id).Cmp(v) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Cmp compares amount of single currency with value, returns -1, 0 or +1
+func (e ExtraCurrencies) Cmp(id uint32, amount *big.Int) int {
+	return e.Get(id).Cmp(amount)
+}
+
+// MarshalJSON serializes currencies as object, where key is currency id and value is amount in string
+func (e ExtraCurrencies) MarshalJSON() ([]byte, error) {
+	obj := make(map[string]string, len(e))
+	for id, v := range e {
+		if v != nil && v.Sign() != 0 {
+			obj[strconv.FormatUint(uint64(id), 10)] = v.String()
+		}
+	}
+	return json.Marshal(obj)
+}
+
+func (e *ExtraCurrencies) UnmarshalJSON(data []byte) error {
+	var obj map[string]json.Number
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fmt.Errorf("invalid extra currencies: %w", err)
+	}
+
+	res := make(ExtraCurrencies, len(obj))
+	for k, v := range obj {
+		id, err := strconv.ParseUint(k, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid currency id %s: %w", k, err)
+		}
+
+		amount, ok := new(big.Int).SetString(v.String(), 10)
+		if !ok || amount.Sign() < 0 {
+			return fmt.Errorf("invalid amount of currency %s", k)
+		}
+		res[uint32(id)] = amount
+	}
+	*e = res
+	return nil
+}
+
+// Value implements the driver.Valuer interface, currencies are stored as json object.
+func (e ExtraCurrencies) Value() (driver.Value, error) {
+	data, err := e.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (e *ExtraCurrencies) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*e = ExtraCurrencies{}
+		return nil
+	case []byte:
+		return e.UnmarshalJSON(v)
+	case string:
+		return e.UnmarshalJSON([]byte(v))
+	}
+	return fmt.Errorf("unsupported type for ExtraCurrencies: %T", value)
+}
+
+// ExtraCurrenciesMap returns typed view of extra currencies
+func (c CurrencyCollection) ExtraCurrenciesMap() (ExtraCurrencies, error) {
+	return ExtraCurrenciesFromDict(c.ExtraCurrencies)
+}
+
+// ExtraCurrenciesMap returns typed view of extra currencies
+func (m *InternalMessage) ExtraCurrenciesMap() (ExtraCurrencies, error) {
+	return ExtraCurrenciesFromDict(m.ExtraCurrencies)
+}
+
+// SetExtraCurrencies replaces extra currencies of message
+func (m *InternalMessage) SetExtraCurrencies(extra ExtraCurrencies) error {
+	dict, err := extra.ToDict()
+	if err != nil {
+		return err
+	}
+	m.ExtraCurrencies = dict
+	return nil
+}
+
+// ExtraCurrenciesMap returns typed view of extra currencies on account balance
+func (s *AccountStorage) ExtraCurrenciesMap() (ExtraCurrencies, error) {
+	return ExtraCurrenciesFromDict(s.ExtraCurrencies)
+}
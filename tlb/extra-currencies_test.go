@@ -0,0 +1,137 @@
+package tlb
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestExtraCurrencies_Dict(t *testing.T) {
+	ec := ExtraCurrencies{
+		1:   big.NewInt(100),
+		7:   new(big.Int).Lsh(big.NewInt(1), 200),
+		100: big.NewInt(0),
+	}
+
+	dict, err := ec.ToDict()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cc := CurrencyCollection{Coins: MustFromTON("1"), ExtraCurrencies: dict}
+	c, err := ToCell(cc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cc2 CurrencyCollection
+	if err = LoadFromCell(&cc2, c.BeginParse()); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := cc2.ExtraCurrenciesMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(parsed) != 2 || !parsed.Equals(ec) {
+		t.Fatal("parsed currencies not match", parsed)
+	}
+
+	if ids := parsed.IDs(); len(ids) != 2 || ids[0] != 1 || ids[1] != 7 {
+		t.Fatal("incorrect ids", ids)
+	}
+
+	empty, err := ExtraCurrenciesFromDict(nil)
+	if err != nil || !empty.IsEmpty() {
+		t.Fatal("nil dict should be empty")
+	}
+
+	if _, err = (ExtraCurrencies{1: big.NewInt(-1)}).ToDict(); err == nil {
+		t.Fatal("negative amount should fail")
+	}
+
+	msg := InternalMessage{}
+	if err = msg.SetExtraCurrencies(ec); err != nil {
+		t.Fatal(err)
+	}
+	if msg.ExtraCurrencies.IsEmpty() {
+		t.Fatal("message currencies should be set")
+	}
+}
+
+func TestExtraCurrencies_Arithmetic(t *testing.T) {
+	a := ExtraCurrencies{1: big.NewInt(10), 2: big.NewInt(5)}
+	b := ExtraCurrencies{1: big.NewInt(3), 3: big.NewInt(7)}
+
+	sum := a.Add(b)
+	if sum.Get(1).Int64() != 13 || sum.Get(2).Int64() != 5 || sum.Get(3).Int64() != 7 {
+		t.Fatal("incorrect sum", sum)
+	}
+
+	if a.Get(1).Int64() != 10 {
+		t.Fatal("source should not be modified")
+	}
+
+	diff, err := sum.Sub(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Equals(a) {
+		t.Fatal("incorrect diff", diff)
+	}
+
+	if _, err = a.Sub(b); err == nil {
+		t.Fatal("sub should fail on negative result")
+	}
+
+	if !sum.Covers(a) || a.Covers(sum) {
+		t.Fatal("incorrect covers")
+	}
+
+	if a.Cmp(1, big.NewInt(10)) != 0 || a.Cmp(3, big.NewInt(1)) != -1 {
+		t.Fatal("incorrect cmp")
+	}
+}
+
+func TestExtraCurrencies_JSON(t *testing.T) {
+	ec := ExtraCurrencies{1: big.NewInt(10), 77: new(big.Int).Lsh(big.NewInt(1), 100)}
+
+	data, err := json.Marshal(ec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `{"1":"10","77":"1267650600228229401496703205376"}` {
+		t.Fatal("incorrect json", string(data))
+	}
+
+	var ec2 ExtraCurrencies
+	if err = json.Unmarshal(data, &ec2); err != nil {
+		t.Fatal(err)
+	}
+	if !ec2.Equals(ec) {
+		t.Fatal("json round trip failed")
+	}
+
+	val, err := ec.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ec3 ExtraCurrencies
+	if err = ec3.Scan(val); err != nil {
+		t.Fatal(err)
+	}
+	if !ec3.Equals(ec) {
+		t.Fatal("sql round trip failed")
+	}
+
+	if err = ec3.Scan(nil); err != nil || !ec3.IsEmpty() {
+		t.Fatal("nil scan should give empty")
+	}
+
+	if err = json.Unmarshal([]byte(`{"x":"1"}`), &ec3); err == nil {
+		t.Fatal("invalid id should fail")
+	}
+}
@@ -0,0 +1,508 @@
+package tlb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+// JSONTypeKey is a key of json object which holds registered name of union type
+const JSONTypeKey = "@type"
+
+var (
+	bigIntType          = reflect.TypeOf(&big.Int{})
+	dictType            = reflect.TypeOf(&cell.Dictionary{})
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// ToJSON serializes tlb structure to json, driven by the same struct tags as LoadFromCell.
+// Field names are converted to snake_case (CreatedLT -> created_lt), json tag can be used to override name or to skip field.
+// Union fields ([TypeA,TypeB]) are serialized as objects with additional '@type' key containing registered name,
+// dictionaries are serialized as objects where key is a decimal representation of dict key and value is BoC in base64,
+// cells are BoC in base64, 64-bit integers and big integers are strings.
+func ToJSON(v any) ([]byte, error) {
+	obj, err := toJSONValue(reflect.ValueOf(v), nil)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(obj)
+}
+
+// FromJSON parses json produced by ToJSON back to tlb structure, v should be a pointer.
+func FromJSON(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("v should be a pointer and not nil")
+	}
+	return fromJSONValue(data, rv.Elem(), nil)
+}
+
+type jsonField struct {
+	key   string
+	value any
+}
+
+// jsonObject keeps order of struct fields in result
+type jsonObject []jsonField
+
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(f.key)
+		if err != nil {
+			return nil, err
+		}
+		val, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize field %s: %w", f.key, err)
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// jsonFieldName converts go field name to snake_case: CreatedLT -> created_lt, IHRFee -> ihr_fee
+func jsonFieldName(f reflect.StructField) (string, bool) {
+	if tag, ok := f.Tag.Lookup("json"); ok {
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			return "", false
+		}
+		if name != "" {
+			return name, true
+		}
+	}
+
+	runes := []rune(f.Name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String(), true
+}
+
+// registeredName returns name of type from tlb registry
+func registeredName(t reflect.Type) string {
+	registeredMx.RLock()
+	defer registeredMx.RUnlock()
+
+	for name, rt := range registered {
+		if rt == t && name == t.Name() {
+			return name
+		}
+	}
+	for name, rt := range registered {
+		if rt == t {
+			return name
+		}
+	}
+	return t.Name()
+}
+
+func toJSONValue(rv reflect.Value, fp *fieldPlan) (any, error) {
+	if !rv.IsValid() {
+		return nil, nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if rv.IsNil() {
+			return nil, nil
+		}
+	}
+
+	switch rv.Type() {
+	case bigIntType:
+		return rv.Interface().(*big.Int).String(), nil
+	case dictType:
+		return dictToJSON(rv.Interface().(*cell.Dictionary), fp)
+	}
+
+	if rv.Type().Implements(jsonMarshalerType) {
+		return rv.Interface(), nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		elem := rv.Elem()
+		if elem.Kind() == reflect.Pointer {
+			if elem.IsNil() {
+				return nil, nil
+			}
+			elem = elem.Elem()
+		}
+
+		val, err := toJSONValue(elem, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		obj := jsonObject{{key: JSONTypeKey, value: registeredName(elem.Type())}}
+		if fields, ok := val.(jsonObject); ok {
+			return append(obj, fields...), nil
+		}
+		return append(obj, jsonField{key: "value", value: val}), nil
+	case reflect.Pointer:
+		return toJSONValue(rv.Elem(), fp)
+	case reflect.Struct:
+		if reflect.PointerTo(rv.Type()).Implements(jsonMarshalerType) {
+			// value is not addressable, so we copy it
+			ptr := reflect.New(rv.Type())
+			ptr.Elem().Set(rv)
+			return ptr.Interface(), nil
+		}
+
+		plan := getStructPlan(rv.Type())
+		obj := make(jsonObject, 0, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			f := rv.Type().Field(i)
+			if !f.IsExported() || f.Type == magicType {
+				continue
+			}
+
+			name, ok := jsonFieldName(f)
+			if !ok {
+				continue
+			}
+
+			val, err := toJSONValue(rv.Field(i), &plan.fields[i])
+			if err != nil {
+				return nil, fmt.Errorf("failed to serialize field %s: %w", f.Name, err)
+			}
+			obj = append(obj, jsonField{key: name, value: val})
+		}
+		return obj, nil
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			if rv.Kind() == reflect.Array {
+				arr := make([]byte, rv.Len())
+				reflect.Copy(reflect.ValueOf(arr), rv)
+				return base64.StdEncoding.EncodeToString(arr), nil
+			}
+			return base64.StdEncoding.EncodeToString(rv.Bytes()), nil
+		}
+
+		list := make([]any, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			val, err := toJSONValue(rv.Index(i), nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to serialize element %d: %w", i, err)
+			}
+			list[i] = val
+		}
+		return list, nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("only maps with string keys are supported")
+		}
+
+		obj := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			val, err := toJSONValue(iter.Value(), nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to serialize map value %s: %w", iter.Key().String(), err)
+			}
+			obj[iter.Key().String()] = val
+		}
+		return obj, nil
+	case reflect.Int64, reflect.Int:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint64, reflect.Uint:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Int32, reflect.Int16, reflect.Int8, reflect.Uint32, reflect.Uint16, reflect.Uint8,
+		reflect.Bool, reflect.String, reflect.Float32, reflect.Float64:
+		return rv.Interface(), nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", rv.Type().String())
+}
+
+func dictToJSON(d *cell.Dictionary, fp *fieldPlan) (any, error) {
+	if fp == nil || !fp.numValid {
+		return nil, fmt.Errorf("dictionary key size is unknown, it should be a field with dict tag")
+	}
+
+	kvs, err := d.LoadAll(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dictionary: %w", err)
+	}
+
+	obj := make(map[string]any, len(kvs))
+	for _, kv := range kvs {
+		key, err := kv.Key.LoadBigUInt(uint(fp.num))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load dictionary key: %w", err)
+		}
+
+		val, err := kv.Value.ToCell()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert dictionary value: %w", err)
+		}
+		obj[key.String()] = base64.StdEncoding.EncodeToString(val.ToBOC())
+	}
+	return obj, nil
+}
+
+func fromJSONValue(data []byte, rv reflect.Value, fp *fieldPlan) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	}
+
+	switch rv.Type() {
+	case bigIntType:
+		str, err := jsonNumberString(data)
+		if err != nil {
+			return err
+		}
+
+		val, ok := new(big.Int).SetString(str, 10)
+		if !ok {
+			return fmt.Errorf("invalid big integer %s", str)
+		}
+		rv.Set(reflect.ValueOf(val))
+		return nil
+	case dictType:
+		d, err := dictFromJSON(data, fp)
+		if err != nil {
+			return err
+		}
+		rv.Set(reflect.ValueOf(d))
+		return nil
+	}
+
+	if rv.Kind() != reflect.Interface && rv.Kind() != reflect.Pointer && rv.Addr().Type().Implements(jsonUnmarshalerType) {
+		return json.Unmarshal(data, rv.Addr().Interface())
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		var typ struct {
+			Type string `json:"@type"`
+		}
+		if err := json.Unmarshal(data, &typ); err != nil {
+			return fmt.Errorf("failed to parse union type: %w", err)
+		}
+
+		t, ok := getRegistered(typ.Type)
+		if !ok {
+			return fmt.Errorf("unknown type %s", typ.Type)
+		}
+
+		val := reflect.New(t)
+		if err := fromJSONValue(data, val.Elem(), nil); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", typ.Type, err)
+		}
+
+		switch {
+		case t.AssignableTo(rv.Type()):
+			rv.Set(val.Elem())
+		case val.Type().AssignableTo(rv.Type()):
+			rv.Set(val)
+		default:
+			return fmt.Errorf("type %s cannot be assigned to field of type %s", typ.Type, rv.Type().String())
+		}
+		return nil
+	case reflect.Pointer:
+		val := reflect.New(rv.Type().Elem())
+		if err := fromJSONValue(data, val.Elem(), fp); err != nil {
+			return err
+		}
+		rv.Set(val)
+		return nil
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return fmt.Errorf("failed to parse object of %s: %w", rv.Type().String(), err)
+		}
+
+		plan := getStructPlan(rv.Type())
+		for i := 0; i < rv.NumField(); i++ {
+			f := rv.Type().Field(i)
+			if !f.IsExported() || f.Type == magicType {
+				continue
+			}
+
+			name, ok := jsonFieldName(f)
+			if !ok {
+				continue
+			}
+
+			raw, ok := fields[name]
+			if !ok {
+				continue
+			}
+
+			if err := fromJSONValue(raw, rv.Field(i), &plan.fields[i]); err != nil {
+				return fmt.Errorf("failed to parse field %s: %w", f.Name, err)
+			}
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			var b []byte
+			if err := json.Unmarshal(data, &b); err != nil {
+				return err
+			}
+
+			if rv.Kind() == reflect.Array {
+				if len(b) != rv.Len() {
+					return fmt.Errorf("incorrect bytes len %d, want %d", len(b), rv.Len())
+				}
+				reflect.Copy(rv, reflect.ValueOf(b))
+				return nil
+			}
+			rv.SetBytes(b)
+			return nil
+		}
+
+		var list []json.RawMessage
+		if err := json.Unmarshal(data, &list); err != nil {
+			return err
+		}
+
+		if rv.Kind() == reflect.Array {
+			if len(list) != rv.Len() {
+				return fmt.Errorf("incorrect array len %d, want %d", len(list), rv.Len())
+			}
+		} else {
+			rv.Set(reflect.MakeSlice(rv.Type(), len(list), len(list)))
+		}
+
+		for i := range list {
+			if err := fromJSONValue(list[i], rv.Index(i), nil); err != nil {
+				return fmt.Errorf("failed to parse element %d: %w", i, err)
+			}
+		}
+		return nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("only maps with string keys are supported")
+		}
+
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+
+		m := reflect.MakeMapWithSize(rv.Type(), len(obj))
+		for k, raw := range obj {
+			val := reflect.New(rv.Type().Elem()).Elem()
+			if err := fromJSONValue(raw, val, nil); err != nil {
+				return fmt.Errorf("failed to parse map value %s: %w", k, err)
+			}
+			m.SetMapIndex(reflect.ValueOf(k).Convert(rv.Type().Key()), val)
+		}
+		rv.Set(m)
+		return nil
+	case reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8, reflect.Int:
+		str, err := jsonNumberString(data)
+		if err != nil {
+			return err
+		}
+
+		val, err := strconv.ParseInt(str, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetInt(val)
+		return nil
+	case reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8, reflect.Uint:
+		str, err := jsonNumberString(data)
+		if err != nil {
+			return err
+		}
+
+		val, err := strconv.ParseUint(str, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetUint(val)
+		return nil
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64:
+		return json.Unmarshal(data, rv.Addr().Interface())
+	}
+	return fmt.Errorf("unsupported type %s", rv.Type().String())
+}
+
+// jsonNumberString accepts both numbers and numbers as strings
+func jsonNumberString(data []byte) (string, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return "", err
+		}
+		return str, nil
+	}
+
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err != nil {
+		return "", err
+	}
+	return num.String(), nil
+}
+
+func dictFromJSON(data []byte, fp *fieldPlan) (*cell.Dictionary, error) {
+	if fp == nil || !fp.numValid {
+		return nil, fmt.Errorf("dictionary key size is unknown, it should be a field with dict tag")
+	}
+
+	var obj map[string]string
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	d := cell.NewDict(uint(fp.num))
+	for k, v := range obj {
+		key, ok := new(big.Int).SetString(k, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid dictionary key %s", k)
+		}
+
+		boc, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dictionary value of key %s: %w", k, err)
+		}
+
+		val, err := cell.FromBOC(boc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dictionary value boc of key %s: %w", k, err)
+		}
+
+		keyCell := cell.BeginCell()
+		if err = keyCell.StoreBigUInt(key, uint(fp.num)); err != nil {
+			return nil, fmt.Errorf("invalid dictionary key %s: %w", k, err)
+		}
+
+		if err = d.Set(keyCell.EndCell(), val); err != nil {
+			return nil, fmt.Errorf("failed to set dictionary key %s: %w", k, err)
+		}
+	}
+	return d, nil
+}
@@ -0,0 +1,126 @@
+package tlb
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+func TestToJSON_Transaction(t *testing.T) {
+	txData, _ := hex.DecodeString("b5ee9c724102060100013e0003af719dd9de25ac93578116413f89610061cf28f52daf1581373bd8671f7abddfd640000244d94d3f309d7cfcadc8e05ebbd460c2c420020d8e3bfd336da4b1c2d0b53f79127093409090000244d94d3f30164d081fd0001408020105008272d38ee1e2b7328b24e8e3836bb288aa9c96218b9a81e7a8fd290e1a4ccf0a65da9be924ff9d7f16b238a76ae47db9d2f56769c1b0c1ccb0fa95522820318401090101a00301ab680122f3d92b6fb36afc55adb8e4e8ef8e2101e4b488d540f31b1826eb15e121b92b000677677896b24d5e045904fe258401873ca3d4b6bc5604dcef619c7deaf77f590404061ed7e60000489b29a7e610c9a103fac00400687362d09c0000244d94d3f303601062ad47c00800731f1286645e6ced11b52e9a2c07cab0d6ea42390b5b969fd204a0e031294cd0001104084049a0187a12026ec7dc45")
+	txCell, err := cell.FromBOC(txData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tx Transaction
+	if err = LoadFromCell(&tx, txCell.BeginParse()); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ToJSON(&tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, exp := range []string{`"lt":"`, `"@type":"TransactionDescriptionOrdinary"`, `"@type":"InternalMessage"`, `"now":`} {
+		if !strings.Contains(string(data), exp) {
+			t.Fatal("json has no", exp, string(data))
+		}
+	}
+
+	var tx2 Transaction
+	if err = FromJSON(data, &tx2); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := ToCell(tx2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(c.Hash(), txCell.Hash()) {
+		t.Fatal("hash not match after json round trip")
+	}
+}
+
+func TestToJSON_Types(t *testing.T) {
+	type testInner struct {
+		_  Magic  `tlb:"#01"`
+		ID uint32 `tlb:"## 32"`
+	}
+
+	type testJSON struct {
+		_         Magic            `tlb:"#aa"`
+		CreatedLT uint64           `tlb:"## 64"`
+		IHRFee    Coins            `tlb:"."`
+		Big       *big.Int         `tlb:"## 100"`
+		Hash      []byte           `tlb:"bits 256"`
+		Inner     testInner        `tlb:"^"`
+		Dict      *cell.Dictionary `tlb:"dict 16"`
+		Renamed   bool             `tlb:"bool" json:"flag"`
+		Skipped   bool             `tlb:"-" json:"-"`
+	}
+
+	d := cell.NewDict(16)
+	if err := d.SetIntKey(big.NewInt(7), cell.BeginCell().MustStoreUInt(5, 8).EndCell()); err != nil {
+		t.Fatal(err)
+	}
+
+	v := testJSON{
+		CreatedLT: 1 << 60,
+		IHRFee:    MustFromTON("1.5"),
+		Big:       new(big.Int).Lsh(big.NewInt(1), 90),
+		Hash:      make([]byte, 32),
+		Inner:     testInner{ID: 77},
+		Dict:      d,
+		Renamed:   true,
+		Skipped:   true,
+	}
+
+	data, err := ToJSON(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var obj map[string]json.RawMessage
+	if err = json.Unmarshal(data, &obj); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(obj["created_lt"]) != `"1152921504606846976"` || string(obj["ihr_fee"]) != `"1500000000"` ||
+		string(obj["flag"]) != "true" || string(obj["inner"]) != `{"id":77}` {
+		t.Fatal("incorrect json", string(data))
+	}
+	if _, ok := obj["skipped"]; ok {
+		t.Fatal("skipped field should not be in json")
+	}
+
+	var v2 testJSON
+	if err = FromJSON(data, &v2); err != nil {
+		t.Fatal(err)
+	}
+
+	c1, err := ToCell(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c2, err := ToCell(v2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(c1.Hash(), c2.Hash()) {
+		t.Fatal("hash not match after json round trip")
+	}
+
+	if err = FromJSON([]byte(`{"v":{"@type":"Unknown"}}`), &struct {
+		V any `tlb:"[ExternalMessage]"`
+	}{}); err == nil {
+		t.Fatal("should be error for unknown type")
+	}
+}
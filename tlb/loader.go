@@ -2,10 +2,9 @@ package tlb
 
 import (
 	"fmt"
+	"math"
 	"math/big"
 	"reflect"
-	"strconv"
-	"strings"
 
 	"github.com/alan890104/tonutils-go/address"
 	"github.com/alan890104/tonutils-go/tvm/cell"
@@ -26,7 +25,19 @@ type Marshaller interface {
 // ^ - loads ref and calls recursively, if field type is *cell.Cell, it loads without parsing
 // . - calls recursively to continue load from current loader (inner struct)
 // dict [inline] N - loads dictionary with key size N, example: 'dict 256', inline option can be used if dict is Hashmap and not HashmapE
+// int N / uint N - signed / unsigned integer with N bits, loads to int or uint of any size, if N > 64 it loads to *big.Int
+// var uint N / var int N / varint N - VarUInteger N or VarInteger N, loads to *big.Int
 // bits N - loads bit slice N len to []byte
+// array {N|FieldName} {element tag} - loads N elements one by one using element tag to slice, count can be taken from previous integer field,
+//
+//	example: 'array Count uint 16', 'array 4 bits 256'
+//
+// list {element tag} - loads slice stored as a chain of refs: maybe bit, then ref to cell with element and the same maybe ref to next element
+// enum [name:]{magic} ... - reads one of specified constructor tags, to string field it sets name of matched option,
+//
+//	to integer field it sets name as number, or index of option if name is not set.
+//	Unknown tag or value which is not in a list is an error, example: 'enum uninit:$00 frozen:$01 active:$10 nonexist:$11'
+//
 // bool - loads 1 bit boolean
 // addr - loads ton address
 // maybe - reads 1 bit, and loads rest if its 1, can be used in combination with others only
@@ -66,19 +77,20 @@ func loadFromCell(v any, slice *cell.Slice, skipProofBranches, skipMagic bool) e
 		return nil
 	}
 
+	plan := getStructPlan(rv.Type())
 	for i := 0; i < rv.NumField(); i++ {
 		loader := slice
 		structField := rv.Type().Field(i)
 		parseType := structField.Type
-		tag := strings.TrimSpace(structField.Tag.Get("tlb"))
-		if tag == "-" {
+		fp := &plan.fields[i]
+		if fp.skip {
 			continue
 		}
-		settings := strings.Split(tag, " ")
-
-		if len(settings) == 0 {
-			continue
+		if fp.tagPanic != "" {
+			// we panic, because its developer's issue, need to fix tag
+			panic(fp.tagPanic)
 		}
+		settings := fp.settings
 
 		if settings[0][0] == '?' {
 			// conditional tlb parse depending on some field value of this struct
@@ -111,19 +123,8 @@ func loadFromCell(v any, slice *cell.Slice, skipProofBranches, skipMagic bool) e
 		}
 
 		if settings[0] == "either" {
-			settings = settings[1:]
-			if len(settings) < 2 {
-				panic("either tag should have 2 args")
-			}
-
-			if settings[0] == "leave" {
-				settings = settings[1:]
-
-				if len(settings) < 3 {
-					panic("either tag should have 2 args and leave tag should have 1 arg")
-				}
-				// skip leave
-				settings = settings[1:]
+			if fp.eitherPanic != "" {
+				panic(fp.eitherPanic)
 			}
 
 			isSecond, err := loader.LoadBoolBit()
@@ -132,9 +133,9 @@ func loadFromCell(v any, slice *cell.Slice, skipProofBranches, skipMagic bool) e
 			}
 
 			if !isSecond {
-				settings = []string{settings[0]}
+				settings = fp.either[0]
 			} else {
-				settings = []string{settings[1]}
+				settings = fp.either[1]
 			}
 		}
 
@@ -175,22 +176,17 @@ func loadFromCell(v any, slice *cell.Slice, skipProofBranches, skipMagic bool) e
 		}
 
 		if structField.Type.Kind() == reflect.Interface {
-			allowed := strings.Join(settings, "")
-			if !strings.HasPrefix(allowed, "[") || !strings.HasSuffix(allowed, "]") {
-				panic("corrupted allowed list tag of field " + structField.Name + ", should be [a,b,c], got " + allowed)
+			if fp.unionPanic != "" {
+				panic(fp.unionPanic)
 			}
 
-			// cut brackets
-			allowed = allowed[1 : len(allowed)-1]
-			types := strings.Split(allowed, ",")
-
-			for _, typ := range types {
-				t, ok := registered[typ]
+			for _, typ := range fp.union {
+				t, ok := getRegistered(typ)
 				if !ok {
 					panic("unregistered type " + typ)
 				}
 
-				if !checkMagic(t.Field(0).Tag.Get("tlb"), loader.Copy()) {
+				if !checkMagic(&getStructPlan(t).fields[0], loader.Copy()) {
 					continue
 				}
 
@@ -216,12 +212,13 @@ func loadFromCell(v any, slice *cell.Slice, skipProofBranches, skipMagic bool) e
 
 		// bits
 		if settings[0] == "##" {
-			num, err := strconv.ParseUint(settings[1], 10, 64)
-			if err != nil {
+			if !fp.numValid {
 				// we panic, because its developer's issue, need to fix tag
 				panic("corrupted num bits in ## tag")
 			}
+			num := fp.num
 
+			var err error
 			switch {
 			case num <= 64:
 				var x any
@@ -297,11 +294,11 @@ func loadFromCell(v any, slice *cell.Slice, skipProofBranches, skipMagic bool) e
 			setVal(reflect.ValueOf(x))
 			continue
 		} else if settings[0] == "bits" {
-			num, err := strconv.Atoi(settings[1])
-			if err != nil {
+			if !fp.numValid {
 				// we panic, because its developer's issue, need to fix tag
 				panic("corrupted num bits in bits tag")
 			}
+			num := fp.num
 
 			x, err := loader.LoadSlice(uint(num))
 			if err != nil {
@@ -316,7 +313,7 @@ func loadFromCell(v any, slice *cell.Slice, skipProofBranches, skipMagic bool) e
 				continue
 			}
 
-			if !checkMagic(settings[0], loader) {
+			if !checkMagic(fp, loader) {
 				return fmt.Errorf("magic is not correct for %s, want %s", rv.Type().String(), settings[0])
 			}
 
@@ -328,12 +325,13 @@ func loadFromCell(v any, slice *cell.Slice, skipProofBranches, skipMagic bool) e
 				inline = true
 			}
 
-			sz, err := strconv.ParseUint(settings[1], 10, 64)
-			if err != nil {
+			if !fp.numValid {
 				panic(fmt.Sprintf("cannot deserialize field '%s' as dict, bad size '%s'", structField.Name, settings[1]))
 			}
+			sz := fp.num
 
 			var dict *cell.Dictionary
+			var err error
 			if inline {
 				dict, err = loader.ToDict(uint(sz))
 				if err != nil {
@@ -358,11 +356,7 @@ func loadFromCell(v any, slice *cell.Slice, skipProofBranches, skipMagic bool) e
 			}
 
 			mappedDict := reflect.MakeMapWithSize(reflect.MapOf(structField.Type.Key(), structField.Type.Elem()), 0)
-			dictVT := reflect.StructOf([]reflect.StructField{{
-				Name: "Value",
-				Type: structField.Type.Elem(),
-				Tag:  reflect.StructTag(fmt.Sprintf("tlb:%q", strings.Join(settings[3:], " "))),
-			}})
+			dictVT := fp.dictValueType
 
 			values, err := dict.LoadAll(skipProofBranches)
 			if err != nil {
@@ -385,56 +379,100 @@ func loadFromCell(v any, slice *cell.Slice, skipProofBranches, skipMagic bool) e
 
 			setVal(mappedDict)
 			continue
-		} else if settings[0] == "var" {
-			if settings[1] == "uint" {
-				sz, err := strconv.Atoi(settings[2])
-				if err != nil {
-					panic(err.Error())
+		} else if settings[0] == "var" || settings[0] == "varint" {
+			signed := settings[0] == "varint"
+			if !signed && settings[1] != "uint" {
+				if settings[1] != "int" {
+					panic("var of type " + settings[1] + " is not supported")
 				}
+				signed = true
+			}
 
-				res, err := loader.LoadVarUInt(uint(sz))
-				if err != nil {
-					return fmt.Errorf("failed to load var uint: %w", err)
+			if !fp.numValid {
+				panic("corrupted size in var tag")
+			}
+
+			var res *big.Int
+			var err error
+			if signed {
+				res, err = loader.LoadVarInt(uint(fp.num))
+			} else {
+				res, err = loader.LoadVarUInt(uint(fp.num))
+			}
+			if err != nil {
+				return fmt.Errorf("failed to load var integer: %w", err)
+			}
+			setVal(reflect.ValueOf(res))
+			continue
+		} else if settings[0] == "int" || settings[0] == "uint" {
+			if !fp.numValid {
+				// we panic, because its developer's issue, need to fix tag
+				panic("corrupted num bits in " + settings[0] + " tag")
+			}
+
+			x, err := loadInteger(loader, parseType, uint(fp.num), settings[0] == "int")
+			if err != nil {
+				return fmt.Errorf("failed to load %s %s %d, err: %w", structField.Name, settings[0], fp.num, err)
+			}
+
+			setVal(x)
+			continue
+		} else if settings[0] == "enum" {
+			if fp.enumPanic != "" {
+				panic(fp.enumPanic)
+			}
+
+			opt, err := loadEnum(fp.enum, loader)
+			if err != nil {
+				return fmt.Errorf("failed to load enum %s, err: %w", structField.Name, err)
+			}
+
+			if parseType.Kind() == reflect.String {
+				setVal(reflect.ValueOf(opt.name))
+			} else {
+				setVal(reflect.ValueOf(opt.value))
+			}
+			continue
+		} else if settings[0] == "array" || settings[0] == "list" {
+			if fp.elemPanic != "" {
+				panic(fp.elemPanic)
+			}
+
+			var list reflect.Value
+			var err error
+			if settings[0] == "array" {
+				count := fp.arrayCount
+				if fp.arrayCountField != "" {
+					count = arrayCountFromField(rv, fp.arrayCountField)
 				}
-				setVal(reflect.ValueOf(res))
-				continue
+				list, err = loadArray(fp.elemType, structField.Type, count, loader, skipProofBranches)
 			} else {
-				panic("var of type " + settings[1] + " is not supported")
+				list, err = loadList(fp.elemType, structField.Type, loader, skipProofBranches)
 			}
+			if err != nil {
+				return fmt.Errorf("failed to load %s %s, err: %w", settings[0], structField.Name, err)
+			}
+
+			setVal(list)
+			continue
 		}
 
-		panic(fmt.Sprintf("cannot deserialize field '%s' as tag '%s'", structField.Name, tag))
+		panic(fmt.Sprintf("cannot deserialize field '%s' as tag '%s'", structField.Name, fp.tag))
 	}
 
 	return nil
 }
 
-func checkMagic(tag string, loader *cell.Slice) bool {
-	var sz, base int
-	if strings.HasPrefix(tag, "#") {
-		base = 16
-		sz = (len(tag) - 1) * 4
-	} else if strings.HasPrefix(tag, "$") {
-		base = 2
-		sz = len(tag) - 1
-	} else {
-		panic("unknown magic value type in tag: " + tag)
+func checkMagic(fp *fieldPlan, loader *cell.Slice) bool {
+	if fp.magicPanic != "" {
+		panic(fp.magicPanic)
 	}
 
-	if sz > 64 {
-		panic("too big magic value type in tag")
-	}
-
-	magic, err := strconv.ParseInt(tag[1:], base, 64)
-	if err != nil {
-		panic("corrupted magic value in tag")
-	}
-
-	ldMagic, err := loader.LoadUInt(uint(sz))
+	ldMagic, err := loader.LoadUInt(fp.magicSize)
 	if err != nil {
 		return false
 	}
-	return ldMagic == uint64(magic)
+	return ldMagic == fp.magic
 }
 
 func ToCell(v any) (*cell.Cell, error) {
@@ -456,20 +494,21 @@ func ToCell(v any) (*cell.Cell, error) {
 
 	root := cell.BeginCell()
 
+	plan := getStructPlan(rv.Type())
 next:
 	for i := 0; i < rv.NumField(); i++ {
 		structField := rv.Type().Field(i)
 		parseType := structField.Type
 		fieldVal := rv.Field(i)
-		tag := strings.TrimSpace(structField.Tag.Get("tlb"))
-		if tag == "-" {
+		fp := &plan.fields[i]
+		if fp.skip {
 			continue
 		}
-		settings := strings.Split(tag, " ")
-
-		if len(settings) == 0 {
-			continue
+		if fp.tagPanic != "" {
+			// we panic, because its developer's issue, need to fix tag
+			panic(fp.tagPanic)
 		}
+		settings := fp.settings
 
 		if settings[0][0] == '?' {
 			// conditional tlb parse depending on some field value of this struct
@@ -505,44 +544,18 @@ next:
 		}
 
 		if settings[0] == "either" {
-			settings = settings[1:]
-
-			if len(settings) < 2 {
-				panic("either tag should have 2 args")
+			if fp.eitherPanic != "" {
+				panic(fp.eitherPanic)
 			}
-
-			leaveBits, leaveRefs := 0, 0
-			if settings[0] == "leave" {
-				settings = settings[1:]
-
-				if len(settings) < 3 {
-					panic("either tag should have 2 args and leave tag should have 1 arg")
-				}
-
-				spl := strings.Split(settings[0], ",")
-				settings = settings[1:]
-
-				val, err := strconv.ParseUint(spl[0], 10, 10)
-				if err != nil {
-					panic("invalid argument for either leave bits")
-				}
-				// set how many free bits we need to have after either written
-				leaveBits = int(val)
-
-				if len(spl) > 1 {
-					val, err = strconv.ParseUint(spl[1], 10, 10)
-					if err != nil {
-						panic("invalid argument for either leave refs")
-					}
-					// set how many free efs we need to have after either written
-					leaveRefs = int(val)
-				}
+			if fp.eitherLeavePanic != "" {
+				panic(fp.eitherLeavePanic)
 			}
+			leaveBits, leaveRefs := fp.eitherLeaveBits, fp.eitherLeaveRefs
 
 			// we try first option, if it is overflows then we try second
 			for x := 0; x < 2; x++ {
 				builder := cell.BeginCell()
-				if err := storeField([]string{settings[x]}, builder, structField, fieldVal, parseType); err != nil {
+				if err := storeField(fp.either[x], fp, builder, structField, fieldVal, parseType); err != nil {
 					return nil, fmt.Errorf("failed to serialize field %s to cell as either %d: %w", structField.Name, x, err)
 				}
 
@@ -565,7 +578,14 @@ next:
 			return nil, fmt.Errorf("failed to serialize either field %s to cell: no valid options", structField.Name)
 		}
 
-		if err := storeField(settings, root, structField, fieldVal, parseType); err != nil {
+		if fp.arrayCountField != "" {
+			if count := arrayCountFromField(rv, fp.arrayCountField); count != uint64(fieldVal.Len()) {
+				return nil, fmt.Errorf("array %s should have %d elements according to %s, but has %d",
+					structField.Name, count, fp.arrayCountField, fieldVal.Len())
+			}
+		}
+
+		if err := storeField(settings, fp, root, structField, fieldVal, parseType); err != nil {
 			return nil, fmt.Errorf("failed to serialize field %s to cell: %w", structField.Name, err)
 		}
 	}
@@ -573,7 +593,7 @@ next:
 	return root.EndCell(), nil
 }
 
-func storeField(settings []string, root *cell.Builder, structField reflect.StructField, fieldVal reflect.Value, parseType reflect.Type) error {
+func storeField(settings []string, fp *fieldPlan, root *cell.Builder, structField reflect.StructField, fieldVal reflect.Value, parseType reflect.Type) error {
 	builder := root
 
 	asRef := false
@@ -592,22 +612,17 @@ func storeField(settings []string, root *cell.Builder, structField reflect.Struc
 	}
 
 	if structField.Type.Kind() == reflect.Interface {
-		allowed := strings.Join(settings, "")
-		if !strings.HasPrefix(allowed, "[") || !strings.HasSuffix(allowed, "]") {
-			panic("corrupted allowed list tag of field " + structField.Name + ", should be [a,b,c], got " + allowed)
+		if fp.unionPanic != "" {
+			panic(fp.unionPanic)
 		}
 
-		// cut brackets
-		allowed = allowed[1 : len(allowed)-1]
-		types := strings.Split(allowed, ",")
-
 		t := fieldVal.Elem().Type()
 		if t.Kind() == reflect.Pointer {
 			t = t.Elem()
 		}
 
 		found := false
-		for _, typ := range types {
+		for _, typ := range fp.union {
 			if t.Name() == typ {
 				found = true
 				break
@@ -631,12 +646,13 @@ func storeField(settings []string, root *cell.Builder, structField reflect.Struc
 			return fmt.Errorf("failed to store cell to builder for %s, err: %w", structField.Name, err)
 		}
 	} else if settings[0] == "##" {
-		num, err := strconv.ParseUint(settings[1], 10, 64)
-		if err != nil {
+		if !fp.numValid {
 			// we panic, because its developer's issue, need to fix tag
 			panic("corrupted num bits in ## tag")
 		}
+		num := fp.num
 
+		var err error
 		switch {
 		case num <= 64:
 			switch parseType.Kind() {
@@ -677,38 +693,21 @@ func storeField(settings []string, root *cell.Builder, structField reflect.Struc
 			return fmt.Errorf("failed to store bool, err: %w", err)
 		}
 	} else if settings[0] == "bits" {
-		num, err := strconv.Atoi(settings[1])
-		if err != nil {
+		if !fp.numValid {
 			// we panic, because its developer's issue, need to fix tag
 			panic("corrupted num bits in bits tag")
 		}
 
-		err = builder.StoreSlice(fieldVal.Bytes(), uint(num))
+		err := builder.StoreSlice(fieldVal.Bytes(), uint(fp.num))
 		if err != nil {
-			return fmt.Errorf("failed to store bits %d, err: %w", num, err)
+			return fmt.Errorf("failed to store bits %d, err: %w", fp.num, err)
 		}
 	} else if parseType == reflect.TypeOf(Magic{}) {
-		var sz, base int
-		if strings.HasPrefix(settings[0], "#") {
-			base = 16
-			sz = (len(settings[0]) - 1) * 4
-		} else if strings.HasPrefix(settings[0], "$") {
-			base = 2
-			sz = len(settings[0]) - 1
-		} else {
-			panic("unknown magic value type in tag")
-		}
-
-		if sz > 64 {
-			panic("too big magic value type in tag")
-		}
-
-		magic, err := strconv.ParseInt(settings[0][1:], base, 64)
-		if err != nil {
-			panic("corrupted magic value in tag")
+		if fp.magicPanic != "" {
+			panic(fp.magicPanic)
 		}
 
-		err = builder.StoreUInt(uint64(magic), uint(sz))
+		err := builder.StoreUInt(fp.magic, fp.magicSize)
 		if err != nil {
 			return fmt.Errorf("failed to store magic: %w", err)
 		}
@@ -732,10 +731,10 @@ func storeField(settings []string, root *cell.Builder, structField reflect.Struc
 				return fmt.Errorf("map key should be string, but instead got %s type", fieldVal.Type().Key())
 			}
 
-			sz, err := strconv.ParseUint(settings[0], 10, 64)
-			if err != nil {
+			if !fp.numValid {
 				panic(fmt.Sprintf("cannot deserialize field '%s' as dict, bad size '%s'", structField.Name, settings[0]))
 			}
+			sz := fp.num
 
 			dict = cell.NewDict(uint(sz))
 
@@ -752,12 +751,7 @@ func storeField(settings []string, root *cell.Builder, structField reflect.Struc
 
 				mapV := fieldVal.MapIndex(mapK)
 
-				cellVT := reflect.StructOf([]reflect.StructField{{
-					Name: "Value",
-					Type: mapV.Type(),
-					Tag:  reflect.StructTag(fmt.Sprintf("tlb:%q", strings.Join(settings[2:], " "))),
-				}})
-				cellV := reflect.New(cellVT).Elem()
+				cellV := reflect.New(fp.dictValueType).Elem()
 				cellV.Field(0).Set(mapV)
 
 				mapVC, err := ToCell(cellV.Interface())
@@ -789,19 +783,61 @@ func storeField(settings []string, root *cell.Builder, structField reflect.Struc
 				return fmt.Errorf("failed to store dict for %s, err: %w", structField.Name, err)
 			}
 		}
-	} else if settings[0] == "var" {
-		if settings[1] == "uint" {
-			sz, err := strconv.Atoi(settings[2])
-			if err != nil {
-				panic(err.Error())
+	} else if settings[0] == "var" || settings[0] == "varint" {
+		signed := settings[0] == "varint"
+		if !signed && settings[1] != "uint" {
+			if settings[1] != "int" {
+				panic("var of type " + settings[1] + " is not supported")
 			}
+			signed = true
+		}
 
-			err = builder.StoreBigVarUInt(fieldVal.Interface().(*big.Int), uint(sz))
-			if err != nil {
-				return fmt.Errorf("failed to store var uint: %w", err)
+		if !fp.numValid {
+			panic("corrupted size in var tag")
+		}
+
+		var err error
+		if signed {
+			err = builder.StoreBigVarInt(fieldVal.Interface().(*big.Int), uint(fp.num))
+		} else {
+			err = builder.StoreBigVarUInt(fieldVal.Interface().(*big.Int), uint(fp.num))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to store var integer: %w", err)
+		}
+	} else if settings[0] == "int" || settings[0] == "uint" {
+		if !fp.numValid {
+			// we panic, because its developer's issue, need to fix tag
+			panic("corrupted num bits in " + settings[0] + " tag")
+		}
+
+		if err := storeInteger(builder, fieldVal, parseType, uint(fp.num), settings[0] == "int"); err != nil {
+			return fmt.Errorf("failed to store %s %d, err: %w", settings[0], fp.num, err)
+		}
+	} else if settings[0] == "enum" {
+		if fp.enumPanic != "" {
+			panic(fp.enumPanic)
+		}
+
+		if err := storeEnum(fp.enum, builder, fieldVal); err != nil {
+			return fmt.Errorf("failed to store enum %s, err: %w", structField.Name, err)
+		}
+	} else if settings[0] == "array" || settings[0] == "list" {
+		if fp.elemPanic != "" {
+			panic(fp.elemPanic)
+		}
+
+		var err error
+		if settings[0] == "array" {
+			if fp.arrayCountField == "" && uint64(fieldVal.Len()) != fp.arrayCount {
+				return fmt.Errorf("array %s should have %d elements, but has %d", structField.Name, fp.arrayCount, fieldVal.Len())
 			}
+			err = storeArray(fp.elemType, builder, fieldVal)
 		} else {
-			panic("var of type " + settings[1] + " is not supported")
+			err = storeList(fp.elemType, builder, fieldVal)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to store %s %s, err: %w", settings[0], structField.Name, err)
 		}
 	} else {
 		panic(fmt.Sprintf("cannot serialize field '%s' as tag '%s', use manual serialization", structField.Name, structField.Tag.Get("tlb")))
@@ -863,3 +899,214 @@ func structStore(field reflect.Value, name string) (*cell.Cell, error) {
 	}
 	return c, nil
 }
+
+func loadInteger(loader *cell.Slice, parseType reflect.Type, sz uint, signed bool) (reflect.Value, error) {
+	if parseType == reflect.TypeOf(&big.Int{}) {
+		var x *big.Int
+		var err error
+		if signed {
+			x, err = loader.LoadBigInt(sz)
+		} else {
+			x, err = loader.LoadBigUInt(sz)
+		}
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(x), nil
+	}
+
+	switch parseType.Kind() {
+	case reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8, reflect.Int,
+		reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8, reflect.Uint:
+	default:
+		panic("unexpected field type for integer tag - " + parseType.String())
+	}
+
+	if sz > 64 {
+		panic("integer of size > 64 can be loaded only to *big.Int")
+	}
+
+	if signed {
+		x, err := loader.LoadInt(sz)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(x), nil
+	}
+
+	x, err := loader.LoadUInt(sz)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return reflect.ValueOf(x), nil
+}
+
+func storeInteger(builder *cell.Builder, fieldVal reflect.Value, parseType reflect.Type, sz uint, signed bool) error {
+	if parseType == reflect.TypeOf(&big.Int{}) {
+		x := fieldVal.Interface().(*big.Int)
+		if signed {
+			// copy, because builder modifies negative values
+			return builder.StoreBigInt(new(big.Int).Set(x), sz)
+		}
+		return builder.StoreBigUInt(x, sz)
+	}
+
+	switch parseType.Kind() {
+	case reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8, reflect.Int:
+		if signed {
+			return builder.StoreInt(fieldVal.Int(), sz)
+		}
+		if fieldVal.Int() < 0 {
+			return fmt.Errorf("negative value cannot be stored as uint")
+		}
+		return builder.StoreUInt(uint64(fieldVal.Int()), sz)
+	case reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8, reflect.Uint:
+		if signed {
+			if fieldVal.Uint() > math.MaxInt64 {
+				return fmt.Errorf("too big value to be stored as int")
+			}
+			return builder.StoreInt(int64(fieldVal.Uint()), sz)
+		}
+		return builder.StoreUInt(fieldVal.Uint(), sz)
+	}
+	panic("unexpected field type for integer tag - " + parseType.String())
+}
+
+func loadEnum(options []enumOption, loader *cell.Slice) (*enumOption, error) {
+	for i := range options {
+		if loader.BitsLeft() < options[i].size {
+			continue
+		}
+
+		tag, err := loader.PreloadUInt(options[i].size)
+		if err != nil {
+			return nil, err
+		}
+
+		if tag == options[i].tag {
+			if _, err = loader.LoadUInt(options[i].size); err != nil {
+				return nil, err
+			}
+			return &options[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown enum constructor")
+}
+
+func storeEnum(options []enumOption, builder *cell.Builder, fieldVal reflect.Value) error {
+	for i := range options {
+		var match bool
+		switch fieldVal.Kind() {
+		case reflect.String:
+			match = fieldVal.String() == options[i].name
+		case reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8, reflect.Int:
+			match = fieldVal.Int() >= 0 && uint64(fieldVal.Int()) == options[i].value
+		default:
+			match = fieldVal.Uint() == options[i].value
+		}
+
+		if match {
+			return builder.StoreUInt(options[i].tag, options[i].size)
+		}
+	}
+	return fmt.Errorf("value %v is not in enum", fieldVal.Interface())
+}
+
+func arrayCountFromField(rv reflect.Value, name string) uint64 {
+	f := rv.FieldByName(name)
+	switch f.Kind() {
+	case reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8, reflect.Int:
+		if f.Int() < 0 {
+			return 0
+		}
+		return uint64(f.Int())
+	case reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8, reflect.Uint:
+		return f.Uint()
+	}
+	panic("array count field " + name + " should be declared before and be integer")
+}
+
+func loadArray(elemType, sliceType reflect.Type, count uint64, loader *cell.Slice, skipProofBranches bool) (reflect.Value, error) {
+	// count is untrusted, so we not preallocate too much
+	prealloc := count
+	if prealloc > 256 {
+		prealloc = 256
+	}
+
+	list := reflect.MakeSlice(sliceType, 0, int(prealloc))
+	for i := uint64(0); i < count; i++ {
+		elem := reflect.New(elemType)
+		if err := loadFromCell(elem.Interface(), loader, skipProofBranches, false); err != nil {
+			return reflect.Value{}, fmt.Errorf("failed to load element %d: %w", i, err)
+		}
+		list = reflect.Append(list, elem.Elem().Field(0))
+	}
+	return list, nil
+}
+
+func storeArray(elemType reflect.Type, builder *cell.Builder, fieldVal reflect.Value) error {
+	for i := 0; i < fieldVal.Len(); i++ {
+		elem := reflect.New(elemType).Elem()
+		elem.Field(0).Set(fieldVal.Index(i))
+
+		c, err := ToCell(elem.Interface())
+		if err != nil {
+			return fmt.Errorf("failed to serialize element %d: %w", i, err)
+		}
+
+		if err = builder.StoreBuilder(c.ToBuilder()); err != nil {
+			return fmt.Errorf("failed to store element %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// loadList - loads list stored as a chain: maybe ^[element, maybe ^[element, ...]]
+func loadList(elemType, sliceType reflect.Type, loader *cell.Slice, skipProofBranches bool) (reflect.Value, error) {
+	list := reflect.MakeSlice(sliceType, 0, 0)
+	for i := 0; ; i++ {
+		has, err := loader.LoadBoolBit()
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("failed to load next flag of element %d: %w", i, err)
+		}
+
+		if !has {
+			return list, nil
+		}
+
+		loader, err = loader.LoadRef()
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("failed to load ref of element %d: %w", i, err)
+		}
+
+		elem := reflect.New(elemType)
+		if err = loadFromCell(elem.Interface(), loader, skipProofBranches, false); err != nil {
+			return reflect.Value{}, fmt.Errorf("failed to load element %d: %w", i, err)
+		}
+		list = reflect.Append(list, elem.Elem().Field(0))
+	}
+}
+
+func storeList(elemType reflect.Type, builder *cell.Builder, fieldVal reflect.Value) error {
+	var next *cell.Cell
+	for i := fieldVal.Len() - 1; i >= 0; i-- {
+		elem := reflect.New(elemType).Elem()
+		elem.Field(0).Set(fieldVal.Index(i))
+
+		c, err := ToCell(elem.Interface())
+		if err != nil {
+			return fmt.Errorf("failed to serialize element %d: %w", i, err)
+		}
+
+		b := c.ToBuilder()
+		if err = b.StoreMaybeRef(next); err != nil {
+			return fmt.Errorf("failed to store next ref of element %d: %w", i, err)
+		}
+		next = b.EndCell()
+	}
+
+	if err := builder.StoreMaybeRef(next); err != nil {
+		return fmt.Errorf("failed to store first element ref: %w", err)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package tlb
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -345,3 +346,192 @@ func TestLoadFromCell_MappedDict(t *testing.T) {
 		t.Fatal("wrong hash")
 	}
 }
+
+func BenchmarkLoadFromCell_Transaction(b *testing.B) {
+	txData, _ := hex.DecodeString("b5ee9c724102060100013e0003af719dd9de25ac93578116413f89610061cf28f52daf1581373bd8671f7abddfd640000244d94d3f309d7cfcadc8e05ebbd460c2c420020d8e3bfd336da4b1c2d0b53f79127093409090000244d94d3f30164d081fd0001408020105008272d38ee1e2b7328b24e8e3836bb288aa9c96218b9a81e7a8fd290e1a4ccf0a65da9be924ff9d7f16b238a76ae47db9d2f56769c1b0c1ccb0fa95522820318401090101a00301ab680122f3d92b6fb36afc55adb8e4e8ef8e2101e4b488d540f31b1826eb15e121b92b000677677896b24d5e045904fe258401873ca3d4b6bc5604dcef619c7deaf77f590404061ed7e60000489b29a7e610c9a103fac00400687362d09c0000244d94d3f303601062ad47c00800731f1286645e6ced11b52e9a2c07cab0d6ea42390b5b969fd204a0e031294cd0001104084049a0187a12026ec7dc45")
+	txCell, err := cell.FromBOC(txData)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var tx Transaction
+		if err = LoadFromCell(&tx, txCell.BeginParse()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkToCell_Transaction(b *testing.B) {
+	txData, _ := hex.DecodeString("b5ee9c724102060100013e0003af719dd9de25ac93578116413f89610061cf28f52daf1581373bd8671f7abddfd640000244d94d3f309d7cfcadc8e05ebbd460c2c420020d8e3bfd336da4b1c2d0b53f79127093409090000244d94d3f30164d081fd0001408020105008272d38ee1e2b7328b24e8e3836bb288aa9c96218b9a81e7a8fd290e1a4ccf0a65da9be924ff9d7f16b238a76ae47db9d2f56769c1b0c1ccb0fa95522820318401090101a00301ab680122f3d92b6fb36afc55adb8e4e8ef8e2101e4b488d540f31b1826eb15e121b92b000677677896b24d5e045904fe258401873ca3d4b6bc5604dcef619c7deaf77f590404061ed7e60000489b29a7e610c9a103fac00400687362d09c0000244d94d3f303601062ad47c00800731f1286645e6ced11b52e9a2c07cab0d6ea42390b5b969fd204a0e031294cd0001104084049a0187a12026ec7dc45")
+	txCell, err := cell.FromBOC(txData)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var tx Transaction
+	if err = LoadFromCell(&tx, txCell.BeginParse()); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err = ToCell(tx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestStructPlan(t *testing.T) {
+	type planTest struct {
+		_     Magic            `tlb:"#1a2b"`
+		A     uint64           `tlb:"maybe ## 48"`
+		B     *cell.Cell       `tlb:"either leave 5,1 . ^"`
+		C     any              `tlb:"^ [StateInit,Message]"`
+		D     map[string]int64 `tlb:"dict inline 5 -> ## 32"`
+		Skip  int              `tlb:"-"`
+		Empty int
+	}
+
+	typ := reflect.TypeOf(planTest{})
+	p := getStructPlan(typ)
+	if p != getStructPlan(typ) {
+		t.Fatal("plan is not cached")
+	}
+
+	if p.fields[0].magic != 0x1a2b || p.fields[0].magicSize != 16 {
+		t.Fatal("incorrect magic")
+	}
+	if !p.fields[1].numValid || p.fields[1].num != 48 {
+		t.Fatal("incorrect num")
+	}
+	if p.fields[2].either[0][0] != "." || p.fields[2].either[1][0] != "^" ||
+		p.fields[2].eitherLeaveBits != 5 || p.fields[2].eitherLeaveRefs != 1 {
+		t.Fatal("incorrect either")
+	}
+	if len(p.fields[3].union) != 2 || p.fields[3].union[1] != "Message" {
+		t.Fatal("incorrect union")
+	}
+	if p.fields[4].num != 5 || p.fields[4].dictValueType == nil || p.fields[4].dictValueType.Field(0).Tag.Get("tlb") != "## 32" {
+		t.Fatal("incorrect dict")
+	}
+	if !p.fields[5].skip {
+		t.Fatal("should be skipped")
+	}
+	if p.fields[6].skip || p.fields[6].tagPanic == "" {
+		t.Fatal("untagged field should not be skipped")
+	}
+
+	type untagged struct {
+		A uint8 `tlb:"## 8"`
+		B uint8
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("untagged field should panic")
+			}
+		}()
+		_, _ = ToCell(untagged{A: 1, B: 2})
+	}()
+}
+
+type testEnumStatus string
+
+type testExtTags struct {
+	Signed   int16              `tlb:"int 12"`
+	Unsigned uint32             `tlb:"uint 20"`
+	BigNeg   *big.Int           `tlb:"int 100"`
+	VarNeg   *big.Int           `tlb:"varint 16"`
+	VarPos   *big.Int           `tlb:"var int 8"`
+	Count    uint8              `tlb:"## 8"`
+	Values   []uint16           `tlb:"array Count uint 16"`
+	Keys     [][]byte           `tlb:"array 2 bits 32"`
+	Coins    []Coins            `tlb:"list ."`
+	Addrs    []*address.Address `tlb:"maybe ^ list addr"`
+	Status   testEnumStatus     `tlb:"enum uninit:$00 frozen:$01 active:$10 nonexist:$11"`
+	Kind     uint8              `tlb:"enum 7:#1 9:#2"`
+	Index    int                `tlb:"enum $0 $10 $11"`
+}
+
+func TestLoadFromCell_ExtendedTags(t *testing.T) {
+	addr := address.MustParseAddr("EQAOp1zuKuX4zY6L9rEdSLam7J3gogIHhfRu_gH70u2MQnmd")
+	v := testExtTags{
+		Signed:   -1000,
+		Unsigned: 1000000,
+		BigNeg:   new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 90)),
+		VarNeg:   big.NewInt(-777),
+		VarPos:   big.NewInt(5),
+		Count:    3,
+		Values:   []uint16{1, 2, 65535},
+		Keys:     [][]byte{{1, 2, 3, 4}, {5, 6, 7, 8}},
+		Coins:    []Coins{MustFromTON("1"), MustFromTON("0.5"), MustFromTON("7")},
+		Addrs:    []*address.Address{addr, addr},
+		Status:   "active",
+		Kind:     9,
+		Index:    2,
+	}
+
+	c, err := ToCell(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var v2 testExtTags
+	if err = LoadFromCell(&v2, c.BeginParse()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(v.Values, v2.Values) || !reflect.DeepEqual(v.Keys, v2.Keys) {
+		t.Fatal("arrays not eq")
+	}
+	if len(v2.Coins) != 3 || v2.Coins[2].String() != "7" || len(v2.Addrs) != 2 || !v2.Addrs[1].Equals(addr) {
+		t.Fatal("lists not eq")
+	}
+	if v2.Signed != v.Signed || v2.Unsigned != v.Unsigned || v2.BigNeg.Cmp(v.BigNeg) != 0 ||
+		v2.VarNeg.Int64() != -777 || v2.VarPos.Int64() != 5 {
+		t.Fatal("integers not eq")
+	}
+	if v2.Status != "active" || v2.Kind != 9 || v2.Index != 2 {
+		t.Fatal("enums not eq")
+	}
+
+	v.Count = 2
+	if _, err = ToCell(v); err == nil {
+		t.Fatal("should be array count error")
+	}
+	v.Count = 3
+
+	v.Status = "unknown"
+	if _, err = ToCell(v); err == nil {
+		t.Fatal("should be enum error")
+	}
+	v.Status = "active"
+
+	v.Coins = nil
+	v.Addrs = nil
+	c, err = ToCell(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v2 = testExtTags{}
+	if err = LoadFromCell(&v2, c.BeginParse()); err != nil {
+		t.Fatal(err)
+	}
+	if len(v2.Coins) != 0 || v2.Addrs != nil {
+		t.Fatal("lists should be empty")
+	}
+
+	type enumOnly struct {
+		Kind uint8 `tlb:"enum 7:#1 9:#2"`
+	}
+	var e enumOnly
+	if err = LoadFromCell(&e, cell.BeginCell().MustStoreUInt(3, 4).EndCell().BeginParse()); err == nil {
+		t.Fatal("should be unknown enum error")
+	}
+}
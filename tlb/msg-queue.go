@@ -0,0 +1,416 @@
+package tlb
+
+import (
+	"fmt"
+
+	"github.com/alan890104/tonutils-go/address"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+func init() {
+	Register(MsgEnvelope{})
+	Register(MsgEnvelopeV2{})
+}
+
+type IntermediateAddressType string
+
+const (
+	IntermediateAddressRegular IntermediateAddressType = "REGULAR"
+	IntermediateAddressSimple  IntermediateAddressType = "SIMPLE"
+	IntermediateAddressExt     IntermediateAddressType = "EXT"
+)
+
+type IntermediateAddress struct {
+	Type IntermediateAddressType
+	// has value when regular
+	UseDestBits uint8
+	// has value when simple or ext
+	Workchain  int32
+	AddrPrefix uint64
+}
+
+type MsgMetadata struct {
+	_             Magic            `tlb:"#0"`
+	Depth         uint32           `tlb:"## 32"`
+	InitiatorAddr *address.Address `tlb:"addr"`
+	InitiatorLT   uint64           `tlb:"## 64"`
+}
+
+type MsgEnvelope struct {
+	_               Magic               `tlb:"#4"`
+	CurAddr         IntermediateAddress `tlb:"."`
+	NextAddr        IntermediateAddress `tlb:"."`
+	FwdFeeRemaining Coins               `tlb:"."`
+	Msg             *Message            `tlb:"^"`
+}
+
+type MsgEnvelopeV2 struct {
+	_               Magic               `tlb:"#5"`
+	CurAddr         IntermediateAddress `tlb:"."`
+	NextAddr        IntermediateAddress `tlb:"."`
+	FwdFeeRemaining Coins               `tlb:"."`
+	Msg             *Message            `tlb:"^"`
+	EmittedLT       *uint64             `tlb:"maybe ## 64"`
+	Metadata        *MsgMetadata        `tlb:"maybe ."`
+}
+
+type EnqueuedMsg struct {
+	EnqueuedLT uint64 `tlb:"## 64"`
+	OutMsg     any    `tlb:"^ [MsgEnvelope,MsgEnvelopeV2]"`
+}
+
+// OutMsgQueue - HashmapAugE 352 EnqueuedMsg uint64,
+// key is next hop workchain (32 bits), next hop address prefix (64 bits) and message hash (256 bits),
+// augmentation is minimal created lt of messages in branch
+type OutMsgQueue struct {
+	Messages *cell.Dictionary `tlb:"dict 352"`
+	MinLT    uint64           `tlb:"## 64"`
+}
+
+type OutMsgQueueEntry struct {
+	Workchain  int32
+	AddrPrefix uint64
+	MsgHash    []byte
+	MinLT      uint64
+	Msg        EnqueuedMsg
+}
+
+type ProcessedUpto struct {
+	LastMsgLT   uint64 `tlb:"## 64"`
+	LastMsgHash []byte `tlb:"bits 256"`
+}
+
+// ProcessedInfo - HashmapE 96 ProcessedUpto, key is shard (64 bits) and masterchain seqno (32 bits)
+type ProcessedInfo struct {
+	Info *cell.Dictionary `tlb:"dict 96"`
+}
+
+type IhrPendingSince struct {
+	ImportLT uint64 `tlb:"## 64"`
+}
+
+// IhrPendingInfo - HashmapE 320 IhrPendingSince
+type IhrPendingInfo struct {
+	Pending *cell.Dictionary `tlb:"dict 320"`
+}
+
+// AccountDispatchQueue - messages of the account by created lt
+type AccountDispatchQueue struct {
+	Messages *cell.Dictionary `tlb:"dict 64"`
+	Count    uint64           `tlb:"## 48"`
+}
+
+// DispatchQueue - HashmapAugE 256 AccountDispatchQueue uint64,
+// key is sender address, augmentation is minimal created lt
+type DispatchQueue struct {
+	Accounts *cell.Dictionary `tlb:"dict 256"`
+	MinLT    uint64           `tlb:"## 64"`
+}
+
+type OutMsgQueueExtra struct {
+	_             Magic         `tlb:"#0"`
+	DispatchQueue DispatchQueue `tlb:"."`
+	OutQueueSize  *uint64       `tlb:"maybe ## 48"`
+}
+
+type OutMsgQueueInfo struct {
+	OutQueue      OutMsgQueue
+	ProcessedInfo ProcessedInfo
+
+	// has value in old states
+	IhrPending *IhrPendingInfo
+	// has value in new states
+	Extra *OutMsgQueueExtra
+}
+
+func (s *ShardStateUnsplit) LoadOutMsgQueueInfo() (*OutMsgQueueInfo, error) {
+	if s.OutMsgQueueInfo == nil {
+		return nil, fmt.Errorf("no out msg queue info")
+	}
+
+	var info OutMsgQueueInfo
+	if err := info.LoadFromCell(s.OutMsgQueueInfo.BeginParse()); err != nil {
+		return nil, fmt.Errorf("failed to load out msg queue info: %w", err)
+	}
+	return &info, nil
+}
+
+func (o *OutMsgQueueInfo) LoadFromCell(loader *cell.Slice) error {
+	var info OutMsgQueueInfo
+	if err := LoadFromCell(&info.OutQueue, loader); err != nil {
+		return fmt.Errorf("failed to load out queue: %w", err)
+	}
+
+	if err := LoadFromCell(&info.ProcessedInfo, loader); err != nil {
+		return fmt.Errorf("failed to load processed info: %w", err)
+	}
+
+	has, err := loader.LoadBoolBit()
+	if err != nil {
+		return fmt.Errorf("failed to load extra flag: %w", err)
+	}
+
+	if has {
+		if loader.BitsLeft() == 0 {
+			// old layout, ihr_pending:IhrPendingInfo, it is HashmapE, so only ref is left
+			root, err := loader.LoadRefCell()
+			if err != nil {
+				return fmt.Errorf("failed to load ihr pending root: %w", err)
+			}
+			info.IhrPending = &IhrPendingInfo{Pending: root.AsDict(320)}
+		} else {
+			var extra OutMsgQueueExtra
+			if err = LoadFromCell(&extra, loader); err != nil {
+				return fmt.Errorf("failed to load out msg queue extra: %w", err)
+			}
+			info.Extra = &extra
+		}
+	}
+
+	*o = info
+	return nil
+}
+
+func (o OutMsgQueueInfo) ToCell() (*cell.Cell, error) {
+	if o.IhrPending != nil && o.Extra != nil {
+		return nil, fmt.Errorf("ihr pending and extra cannot be set together")
+	}
+
+	b := cell.BeginCell()
+
+	q, err := ToCell(o.OutQueue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize out queue: %w", err)
+	}
+	if err = b.StoreBuilder(q.ToBuilder()); err != nil {
+		return nil, fmt.Errorf("failed to store out queue: %w", err)
+	}
+
+	p, err := ToCell(o.ProcessedInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize processed info: %w", err)
+	}
+	if err = b.StoreBuilder(p.ToBuilder()); err != nil {
+		return nil, fmt.Errorf("failed to store processed info: %w", err)
+	}
+
+	switch {
+	case o.Extra != nil:
+		e, err := ToCell(o.Extra)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize out msg queue extra: %w", err)
+		}
+		if err = b.StoreBoolBit(true); err != nil {
+			return nil, fmt.Errorf("failed to store extra flag: %w", err)
+		}
+		if err = b.StoreBuilder(e.ToBuilder()); err != nil {
+			return nil, fmt.Errorf("failed to store out msg queue extra: %w", err)
+		}
+	case o.IhrPending != nil:
+		if err = b.StoreDict(o.IhrPending.Pending); err != nil {
+			return nil, fmt.Errorf("failed to store ihr pending: %w", err)
+		}
+	default:
+		if err = b.StoreBoolBit(false); err != nil {
+			return nil, fmt.Errorf("failed to store extra flag: %w", err)
+		}
+	}
+
+	return b.EndCell(), nil
+}
+
+// LoadAll - returns all messages in the queue, pruned branches are skipped
+func (q *OutMsgQueue) LoadAll() ([]*OutMsgQueueEntry, error) {
+	if q.Messages == nil {
+		return []*OutMsgQueueEntry{}, nil
+	}
+
+	kvs, err := q.Messages.LoadAll(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load out msg queue dict: %w", err)
+	}
+
+	res := make([]*OutMsgQueueEntry, 0, len(kvs))
+	for _, kv := range kvs {
+		var e OutMsgQueueEntry
+
+		wc, err := kv.Key.LoadInt(32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load key workchain: %w", err)
+		}
+		e.Workchain = int32(wc)
+
+		if e.AddrPrefix, err = kv.Key.LoadUInt(64); err != nil {
+			return nil, fmt.Errorf("failed to load key address prefix: %w", err)
+		}
+
+		if e.MsgHash, err = kv.Key.LoadSlice(256); err != nil {
+			return nil, fmt.Errorf("failed to load key message hash: %w", err)
+		}
+
+		if e.MinLT, err = kv.Value.LoadUInt(64); err != nil {
+			return nil, fmt.Errorf("failed to load min lt: %w", err)
+		}
+
+		if err = LoadFromCell(&e.Msg, kv.Value); err != nil {
+			return nil, fmt.Errorf("failed to load enqueued message: %w", err)
+		}
+		res = append(res, &e)
+	}
+	return res, nil
+}
+
+// ListBySender - returns messages in the queue which were sent by the given address
+func (q *OutMsgQueue) ListBySender(addr *address.Address) ([]*OutMsgQueueEntry, error) {
+	all, err := q.LoadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*OutMsgQueueEntry
+	for _, e := range all {
+		msg := e.Msg.Message()
+		if msg == nil || msg.Msg == nil {
+			continue
+		}
+
+		if src := msg.Msg.SenderAddr(); src != nil && src.Equals(addr) {
+			res = append(res, e)
+		}
+	}
+	return res, nil
+}
+
+// ListByDestination - returns messages in the queue which are going to the given address
+func (q *OutMsgQueue) ListByDestination(addr *address.Address) ([]*OutMsgQueueEntry, error) {
+	all, err := q.LoadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*OutMsgQueueEntry
+	for _, e := range all {
+		msg := e.Msg.Message()
+		if msg == nil || msg.Msg == nil {
+			continue
+		}
+
+		if dst := msg.Msg.DestAddr(); dst != nil && dst.Equals(addr) {
+			res = append(res, e)
+		}
+	}
+	return res, nil
+}
+
+// Message - returns message from envelope of any version
+func (e *EnqueuedMsg) Message() *Message {
+	switch t := e.OutMsg.(type) {
+	case MsgEnvelope:
+		return t.Msg
+	case MsgEnvelopeV2:
+		return t.Msg
+	}
+	return nil
+}
+
+// Get - returns dispatch queue of the account, if account has no messages in queue, cell.ErrNoSuchKeyInDict is returned
+func (d *DispatchQueue) Get(addr *address.Address) (*AccountDispatchQueue, error) {
+	if d.Accounts == nil {
+		return nil, cell.ErrNoSuchKeyInDict
+	}
+
+	val, err := d.Accounts.LoadValue(cell.BeginCell().MustStoreSlice(addr.Data(), 256).EndCell())
+	if err != nil {
+		return nil, err
+	}
+
+	// skip augmentation value
+	if _, err = val.LoadUInt(64); err != nil {
+		return nil, fmt.Errorf("failed to load min lt: %w", err)
+	}
+
+	var q AccountDispatchQueue
+	if err = LoadFromCell(&q, val); err != nil {
+		return nil, fmt.Errorf("failed to load account dispatch queue: %w", err)
+	}
+	return &q, nil
+}
+
+// LoadAll - returns account messages in dispatch queue, sorted by created lt
+func (a *AccountDispatchQueue) LoadAll() ([]*EnqueuedMsg, error) {
+	if a.Messages == nil {
+		return []*EnqueuedMsg{}, nil
+	}
+
+	kvs, err := a.Messages.LoadAll(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dispatch queue messages dict: %w", err)
+	}
+
+	res := make([]*EnqueuedMsg, 0, len(kvs))
+	for _, kv := range kvs {
+		var msg EnqueuedMsg
+		if err = LoadFromCell(&msg, kv.Value); err != nil {
+			return nil, fmt.Errorf("failed to load enqueued message: %w", err)
+		}
+		res = append(res, &msg)
+	}
+	return res, nil
+}
+
+func (i *IntermediateAddress) LoadFromCell(loader *cell.Slice) error {
+	isNotRegular, err := loader.LoadBoolBit()
+	if err != nil {
+		return err
+	}
+
+	if !isNotRegular {
+		bits, err := loader.LoadUInt(7)
+		if err != nil {
+			return err
+		}
+		if bits > 96 {
+			return fmt.Errorf("use dest bits should be <= 96")
+		}
+		*i = IntermediateAddress{Type: IntermediateAddressRegular, UseDestBits: uint8(bits)}
+		return nil
+	}
+
+	isExt, err := loader.LoadBoolBit()
+	if err != nil {
+		return err
+	}
+
+	wcBits, typ := uint(8), IntermediateAddressSimple
+	if isExt {
+		wcBits, typ = 32, IntermediateAddressExt
+	}
+
+	wc, err := loader.LoadInt(wcBits)
+	if err != nil {
+		return err
+	}
+
+	pfx, err := loader.LoadUInt(64)
+	if err != nil {
+		return err
+	}
+
+	*i = IntermediateAddress{Type: typ, Workchain: int32(wc), AddrPrefix: pfx}
+	return nil
+}
+
+func (i IntermediateAddress) ToCell() (*cell.Cell, error) {
+	switch i.Type {
+	case IntermediateAddressRegular:
+		if i.UseDestBits > 96 {
+			return nil, fmt.Errorf("use dest bits should be <= 96")
+		}
+		return cell.BeginCell().MustStoreUInt(0b0, 1).MustStoreUInt(uint64(i.UseDestBits), 7).EndCell(), nil
+	case IntermediateAddressSimple:
+		return cell.BeginCell().MustStoreUInt(0b10, 2).MustStoreInt(int64(i.Workchain), 8).MustStoreUInt(i.AddrPrefix, 64).EndCell(), nil
+	case IntermediateAddressExt:
+		return cell.BeginCell().MustStoreUInt(0b11, 2).MustStoreInt(int64(i.Workchain), 32).MustStoreUInt(i.AddrPrefix, 64).EndCell(), nil
+	}
+	return nil, fmt.Errorf("unknown intermediate address type %s", i.Type)
+}
@@ -0,0 +1,126 @@
+package tlb
+
+import (
+	"testing"
+
+	"github.com/alan890104/tonutils-go/address"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+func TestOutMsgQueueInfo_LoadFromCell(t *testing.T) {
+	src := address.MustParseAddr("EQAOp1zuKuX4zY6L9rEdSLam7J3gogIHhfRu_gH70u2MQnmd")
+	dst := address.MustParseAddr("EQA_B407fiLIlE5VYZCaI2rki0in6kLyjdhhwitvZNfpe7eY")
+
+	emitted := uint64(777)
+	enqueued := EnqueuedMsg{
+		EnqueuedLT: 1000,
+		OutMsg: MsgEnvelopeV2{
+			CurAddr:         IntermediateAddress{Type: IntermediateAddressRegular, UseDestBits: 0},
+			NextAddr:        IntermediateAddress{Type: IntermediateAddressSimple, Workchain: 0, AddrPrefix: 0x3F00000000000000},
+			FwdFeeRemaining: MustFromTON("0.001"),
+			Msg: &Message{
+				MsgType: MsgTypeInternal,
+				Msg: &InternalMessage{
+					Bounce:  true,
+					SrcAddr: src,
+					DstAddr: dst,
+					Amount:  MustFromTON("1"),
+					Body:    cell.BeginCell().EndCell(),
+				},
+			},
+			EmittedLT: &emitted,
+		},
+	}
+
+	msgCell, err := ToCell(enqueued)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queue := cell.NewDict(352)
+	key := cell.BeginCell().MustStoreInt(0, 32).MustStoreUInt(0x3F00000000000000, 64).MustStoreSlice(msgCell.Hash(), 256).EndCell()
+	if err = queue.Set(key, cell.BeginCell().MustStoreUInt(1000, 64).MustStoreBuilder(msgCell.ToBuilder()).EndCell()); err != nil {
+		t.Fatal(err)
+	}
+
+	accMessages := cell.NewDict(64)
+	if err = accMessages.Set(cell.BeginCell().MustStoreUInt(1000, 64).EndCell(), msgCell); err != nil {
+		t.Fatal(err)
+	}
+
+	accQueue, err := ToCell(AccountDispatchQueue{Messages: accMessages, Count: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dispatch := cell.NewDict(256)
+	if err = dispatch.Set(cell.BeginCell().MustStoreSlice(src.Data(), 256).EndCell(),
+		cell.BeginCell().MustStoreUInt(1000, 64).MustStoreBuilder(accQueue.ToBuilder()).EndCell()); err != nil {
+		t.Fatal(err)
+	}
+
+	size := uint64(1)
+	info := OutMsgQueueInfo{
+		OutQueue:      OutMsgQueue{Messages: queue, MinLT: 1000},
+		ProcessedInfo: ProcessedInfo{Info: cell.NewDict(96)},
+		Extra: &OutMsgQueueExtra{
+			DispatchQueue: DispatchQueue{Accounts: dispatch, MinLT: 1000},
+			OutQueueSize:  &size,
+		},
+	}
+
+	c, err := ToCell(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var loaded OutMsgQueueInfo
+	if err = LoadFromCell(&loaded, c.BeginParse()); err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.Extra == nil || loaded.IhrPending != nil || loaded.Extra.OutQueueSize == nil || *loaded.Extra.OutQueueSize != 1 {
+		t.Fatal("incorrect extra")
+	}
+
+	list, err := loaded.OutQueue.ListBySender(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].MinLT != 1000 || list[0].AddrPrefix != 0x3F00000000000000 {
+		t.Fatal("incorrect queue entries")
+	}
+
+	env, ok := list[0].Msg.OutMsg.(MsgEnvelopeV2)
+	if !ok {
+		t.Fatal("incorrect envelope type")
+	}
+	if env.NextAddr.Type != IntermediateAddressSimple || env.EmittedLT == nil || *env.EmittedLT != 777 {
+		t.Fatal("incorrect envelope")
+	}
+
+	if list, err = loaded.OutQueue.ListBySender(dst); err != nil || len(list) != 0 {
+		t.Fatal("should be no messages from dst", err)
+	}
+
+	if list, err = loaded.OutQueue.ListByDestination(dst); err != nil || len(list) != 1 {
+		t.Fatal("should be message to dst", err)
+	}
+
+	accQ, err := loaded.Extra.DispatchQueue.Get(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, err := accQ.LoadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accQ.Count != 1 || len(msgs) != 1 || !msgs[0].Message().Msg.DestAddr().Equals(dst) {
+		t.Fatal("incorrect dispatch queue")
+	}
+
+	if _, err = loaded.Extra.DispatchQueue.Get(dst); err == nil {
+		t.Fatal("should be not found")
+	}
+}
@@ -0,0 +1,109 @@
+package payloads
+
+import (
+	"fmt"
+
+	"github.com/alan890104/tonutils-go/tlb"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+const (
+	CommentOpcode          = 0x00000000
+	EncryptedCommentOpcode = 0x2167da4b
+	BinaryCommentOpcode    = 0xb3ddcf7d
+)
+
+// Comment is a text comment, stored as utf8 snake string after zero opcode.
+// Text is split between cells only on runes boundaries.
+type Comment struct {
+	_    tlb.Magic `tlb:"#00000000"`
+	Text string
+	// MaxChunks limits number of cells in the snake on load and store, 0 means no limit
+	MaxChunks int `tlb:"-"`
+}
+
+// BinaryComment holds arbitrary binary data, stored as snake after its opcode
+type BinaryComment struct {
+	_    tlb.Magic `tlb:"#b3ddcf7d"`
+	Data []byte
+}
+
+// EncryptedComment holds encrypted data of comment, it can be decrypted using wallet.DecryptCommentCell
+type EncryptedComment struct {
+	_    tlb.Magic `tlb:"#2167da4b"`
+	Data []byte
+}
+
+func (c *Comment) LoadFromCell(loader *cell.Slice) error {
+	if err := loadOpcode(loader, CommentOpcode); err != nil {
+		return err
+	}
+
+	text, err := loader.LoadTextSnake(c.MaxChunks)
+	if err != nil {
+		return fmt.Errorf("failed to load comment: %w", err)
+	}
+	c.Text = text
+	return nil
+}
+
+func (c Comment) ToCell() (*cell.Cell, error) {
+	b := cell.BeginCell().MustStoreUInt(CommentOpcode, 32)
+	if err := b.StoreTextSnake(c.Text, c.MaxChunks); err != nil {
+		return nil, fmt.Errorf("failed to store comment: %w", err)
+	}
+	return b.EndCell(), nil
+}
+
+func (c *EncryptedComment) LoadFromCell(loader *cell.Slice) error {
+	if err := loadOpcode(loader, EncryptedCommentOpcode); err != nil {
+		return err
+	}
+
+	data, err := loader.LoadBinarySnake()
+	if err != nil {
+		return fmt.Errorf("failed to load encrypted data: %w", err)
+	}
+	c.Data = data
+	return nil
+}
+
+func (c EncryptedComment) ToCell() (*cell.Cell, error) {
+	b := cell.BeginCell().MustStoreUInt(EncryptedCommentOpcode, 32)
+	if err := b.StoreBinarySnake(c.Data); err != nil {
+		return nil, fmt.Errorf("failed to store encrypted data: %w", err)
+	}
+	return b.EndCell(), nil
+}
+
+func (c *BinaryComment) LoadFromCell(loader *cell.Slice) error {
+	if err := loadOpcode(loader, BinaryCommentOpcode); err != nil {
+		return err
+	}
+
+	data, err := loader.LoadBinarySnake()
+	if err != nil {
+		return fmt.Errorf("failed to load binary comment: %w", err)
+	}
+	c.Data = data
+	return nil
+}
+
+func (c BinaryComment) ToCell() (*cell.Cell, error) {
+	b := cell.BeginCell().MustStoreUInt(BinaryCommentOpcode, 32)
+	if err := b.StoreBinarySnake(c.Data); err != nil {
+		return nil, fmt.Errorf("failed to store binary comment: %w", err)
+	}
+	return b.EndCell(), nil
+}
+
+func loadOpcode(loader *cell.Slice, expected uint32) error {
+	op, err := loader.LoadUInt(32)
+	if err != nil {
+		return fmt.Errorf("failed to load opcode: %w", err)
+	}
+	if op != uint64(expected) {
+		return fmt.Errorf("unexpected opcode %08x, want %08x", op, expected)
+	}
+	return nil
+}
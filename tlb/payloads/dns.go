@@ -0,0 +1,68 @@
+package payloads
+
+import (
+	"fmt"
+
+	"github.com/alan890104/tonutils-go/tlb"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+const DNSChangeRecordOpcode = 0x4eb1f0f9
+
+// DNSChangeRecord sets or deletes (when Value is nil) dns record of domain,
+// Key is sha256 of record name, like sha256("wallet").
+// https://github.com/ton-blockchain/TEPs/blob/master/text/0081-dns-standard.md
+type DNSChangeRecord struct {
+	_       tlb.Magic `tlb:"#4eb1f0f9"`
+	QueryID uint64
+	Key     []byte
+	Value   *cell.Cell
+}
+
+func (d *DNSChangeRecord) LoadFromCell(loader *cell.Slice) error {
+	if err := loadOpcode(loader, DNSChangeRecordOpcode); err != nil {
+		return err
+	}
+
+	queryID, err := loader.LoadUInt(64)
+	if err != nil {
+		return fmt.Errorf("failed to load query id: %w", err)
+	}
+
+	key, err := loader.LoadSlice(256)
+	if err != nil {
+		return fmt.Errorf("failed to load key: %w", err)
+	}
+
+	// dns contract treats presence of ref as value, some implementations store record inline
+	var value *cell.Cell
+	if loader.RefsNum() > 0 {
+		if value, err = loader.LoadRefCell(); err != nil {
+			return fmt.Errorf("failed to load value: %w", err)
+		}
+	} else if loader.BitsLeft() > 0 {
+		if value, err = loader.ToCell(); err != nil {
+			return fmt.Errorf("failed to load inline value: %w", err)
+		}
+	}
+
+	d.QueryID = queryID
+	d.Key = key
+	d.Value = value
+	return nil
+}
+
+func (d DNSChangeRecord) ToCell() (*cell.Cell, error) {
+	if len(d.Key) != 32 {
+		return nil, fmt.Errorf("key should be 32 bytes")
+	}
+
+	b := cell.BeginCell().
+		MustStoreUInt(DNSChangeRecordOpcode, 32).
+		MustStoreUInt(d.QueryID, 64).
+		MustStoreSlice(d.Key, 256)
+	if d.Value != nil {
+		b.MustStoreRef(d.Value)
+	}
+	return b.EndCell(), nil
+}
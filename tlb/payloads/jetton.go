@@ -0,0 +1,61 @@
+package payloads
+
+import (
+	"github.com/alan890104/tonutils-go/address"
+	"github.com/alan890104/tonutils-go/tlb"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+// https://github.com/ton-blockchain/TEPs/blob/master/text/0074-jettons-standard.md
+
+type JettonTransfer struct {
+	_                   tlb.Magic        `tlb:"#0f8a7ea5"`
+	QueryID             uint64           `tlb:"## 64"`
+	Amount              tlb.Coins        `tlb:"."`
+	Destination         *address.Address `tlb:"addr"`
+	ResponseDestination *address.Address `tlb:"addr"`
+	CustomPayload       *cell.Cell       `tlb:"maybe ^"`
+	ForwardTONAmount    tlb.Coins        `tlb:"."`
+	ForwardPayload      *cell.Cell       `tlb:"either . ^"`
+}
+
+type JettonTransferNotification struct {
+	_              tlb.Magic        `tlb:"#7362d09c"`
+	QueryID        uint64           `tlb:"## 64"`
+	Amount         tlb.Coins        `tlb:"."`
+	Sender         *address.Address `tlb:"addr"`
+	ForwardPayload *cell.Cell       `tlb:"either . ^"`
+}
+
+// JettonInternalTransfer is sent between jetton wallets, it is not standardized by TEP-74 but used by reference implementation
+type JettonInternalTransfer struct {
+	_                tlb.Magic        `tlb:"#178d4519"`
+	QueryID          uint64           `tlb:"## 64"`
+	Amount           tlb.Coins        `tlb:"."`
+	From             *address.Address `tlb:"addr"`
+	ResponseAddress  *address.Address `tlb:"addr"`
+	ForwardTONAmount tlb.Coins        `tlb:"."`
+	ForwardPayload   *cell.Cell       `tlb:"either . ^"`
+}
+
+type JettonBurn struct {
+	_                   tlb.Magic        `tlb:"#595f07bc"`
+	QueryID             uint64           `tlb:"## 64"`
+	Amount              tlb.Coins        `tlb:"."`
+	ResponseDestination *address.Address `tlb:"addr"`
+	CustomPayload       *cell.Cell       `tlb:"maybe ^"`
+}
+
+type JettonBurnNotification struct {
+	_                   tlb.Magic        `tlb:"#7bdd97de"`
+	QueryID             uint64           `tlb:"## 64"`
+	Amount              tlb.Coins        `tlb:"."`
+	Sender              *address.Address `tlb:"addr"`
+	ResponseDestination *address.Address `tlb:"addr"`
+}
+
+// Excesses is used by both jettons and nft to return the rest of ton
+type Excesses struct {
+	_       tlb.Magic `tlb:"#d53276db"`
+	QueryID uint64    `tlb:"## 64"`
+}
@@ -0,0 +1,40 @@
+package payloads
+
+import (
+	"math/big"
+
+	"github.com/alan890104/tonutils-go/address"
+	"github.com/alan890104/tonutils-go/tlb"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+// https://github.com/ton-blockchain/TEPs/blob/master/text/0062-nft-standard.md
+
+type NFTTransfer struct {
+	_                   tlb.Magic        `tlb:"#5fcc3d14"`
+	QueryID             uint64           `tlb:"## 64"`
+	NewOwner            *address.Address `tlb:"addr"`
+	ResponseDestination *address.Address `tlb:"addr"`
+	CustomPayload       *cell.Cell       `tlb:"maybe ^"`
+	ForwardAmount       tlb.Coins        `tlb:"."`
+	ForwardPayload      *cell.Cell       `tlb:"either . ^"`
+}
+
+type NFTOwnershipAssigned struct {
+	_              tlb.Magic        `tlb:"#05138d91"`
+	QueryID        uint64           `tlb:"## 64"`
+	PrevOwner      *address.Address `tlb:"addr"`
+	ForwardPayload *cell.Cell       `tlb:"either . ^"`
+}
+
+type NFTGetStaticData struct {
+	_       tlb.Magic `tlb:"#2fcb26a2"`
+	QueryID uint64    `tlb:"## 64"`
+}
+
+type NFTReportStaticData struct {
+	_          tlb.Magic        `tlb:"#8b771735"`
+	QueryID    uint64           `tlb:"## 64"`
+	Index      *big.Int         `tlb:"## 256"`
+	Collection *address.Address `tlb:"addr"`
+}
@@ -0,0 +1,122 @@
+// Package payloads decodes bodies of internal messages by opcode into typed structures.
+// Standard payloads (comments, jettons, nft, dns, wallet v5 extensions) are registered by default,
+// payment channel messages are registered when ton/payments package is imported,
+// other opcodes can be added using Register or RegisterDecoder.
+package payloads
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alan890104/tonutils-go/tlb"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+var ErrUnknownOpcode = errors.New("unknown opcode")
+var ErrEmptyPayload = errors.New("payload is empty")
+
+// Decoder parses payload, slice includes opcode
+type Decoder func(body *cell.Slice) (any, error)
+
+var (
+	decoders   = map[uint32]Decoder{}
+	decodersMx sync.RWMutex
+)
+
+func init() {
+	Register(Comment{})
+	Register(EncryptedComment{})
+	Register(BinaryComment{})
+
+	Register(JettonTransfer{})
+	Register(JettonTransferNotification{})
+	Register(JettonInternalTransfer{})
+	Register(JettonBurn{})
+	Register(JettonBurnNotification{})
+	Register(Excesses{})
+
+	Register(NFTTransfer{})
+	Register(NFTOwnershipAssigned{})
+	Register(NFTGetStaticData{})
+	Register(NFTReportStaticData{})
+
+	Register(DNSChangeRecord{})
+
+	Register(WalletV5ExtensionAction{})
+}
+
+// Register adds tlb structure as decoder of its opcode,
+// first field of structure should be tlb.Magic with 32 bit opcode, like `tlb:"#0f8a7ea5"`.
+// If opcode is already registered, it will be replaced.
+func Register(typ any) {
+	t := reflect.TypeOf(typ)
+	if t.Kind() != reflect.Struct || t.NumField() == 0 || t.Field(0).Type != reflect.TypeOf(tlb.Magic{}) {
+		panic("first field is not magic")
+	}
+
+	tag := strings.TrimSpace(t.Field(0).Tag.Get("tlb"))
+	if !strings.HasPrefix(tag, "#") || len(tag) != 9 {
+		panic("magic of payload should be 32 bit opcode in hex, like #0f8a7ea5")
+	}
+
+	op, err := strconv.ParseUint(tag[1:], 16, 32)
+	if err != nil {
+		panic("corrupted opcode in magic tag")
+	}
+
+	RegisterDecoder(uint32(op), func(body *cell.Slice) (any, error) {
+		v := reflect.New(t)
+		if err := tlb.LoadFromCell(v.Interface(), body); err != nil {
+			return nil, err
+		}
+		return v.Interface(), nil
+	})
+}
+
+// RegisterDecoder adds custom decoder of opcode, if opcode is already registered, it will be replaced.
+func RegisterDecoder(op uint32, decoder Decoder) {
+	decodersMx.Lock()
+	defer decodersMx.Unlock()
+
+	decoders[op] = decoder
+}
+
+// Opcode returns first 32 bits of payload
+func Opcode(body *cell.Cell) (uint32, error) {
+	if body == nil || body.BitsSize() < 32 {
+		return 0, ErrEmptyPayload
+	}
+
+	op, err := body.BeginParse().LoadUInt(32)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load opcode: %w", err)
+	}
+	return uint32(op), nil
+}
+
+// Decode parses payload using decoder registered for its opcode,
+// result is a pointer to structure, for example *JettonTransfer or *Comment.
+// ErrUnknownOpcode is returned when there is no decoder for opcode.
+func Decode(body *cell.Cell) (any, error) {
+	op, err := Opcode(body)
+	if err != nil {
+		return nil, err
+	}
+
+	decodersMx.RLock()
+	decoder, ok := decoders[op]
+	decodersMx.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %08x", ErrUnknownOpcode, op)
+	}
+
+	v, err := decoder(body.BeginParse())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode payload with opcode %08x: %w", op, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,133 @@
+package payloads
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/alan890104/tonutils-go/address"
+	"github.com/alan890104/tonutils-go/tlb"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+func TestDecode(t *testing.T) {
+	addr := address.MustParseAddr("EQC9bWZd29foipyPOGWlVNVCQzpGAjvi1rGWF7EbNcSVClpA")
+
+	comment, err := tlb.ToCell(Comment{Text: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transfer, err := tlb.ToCell(JettonTransfer{
+		QueryID:             7,
+		Amount:              tlb.MustFromTON("1"),
+		Destination:         addr,
+		ResponseDestination: addr,
+		ForwardTONAmount:    tlb.FromNanoTONU(0),
+		ForwardPayload:      comment,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := Decode(transfer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, ok := v.(*JettonTransfer)
+	if !ok {
+		t.Fatalf("incorrect type %T", v)
+	}
+	if tr.QueryID != 7 || tr.Amount.String() != "1" || !tr.Destination.Equals(addr) {
+		t.Fatal("incorrect transfer")
+	}
+
+	fwd, err := Decode(tr.ForwardPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fwd.(*Comment).Text != "hello" {
+		t.Fatal("incorrect comment")
+	}
+
+	enc := cell.BeginCell().MustStoreUInt(EncryptedCommentOpcode, 32).MustStoreBinarySnake(make([]byte, 80)).EndCell()
+	if v, err = Decode(enc); err != nil || len(v.(*EncryptedComment).Data) != 80 {
+		t.Fatal("incorrect encrypted comment", err)
+	}
+
+	bin, err := tlb.ToCell(BinaryComment{Data: []byte{0xff, 0x00, 0xfe}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err = Decode(bin); err != nil || !bytes.Equal(v.(*BinaryComment).Data, []byte{0xff, 0x00, 0xfe}) {
+		t.Fatal("incorrect binary comment", err)
+	}
+
+	if _, err = Decode(cell.BeginCell().MustStoreUInt(CommentOpcode, 32).MustStoreBinarySnake([]byte{0xff}).EndCell()); err == nil {
+		t.Fatal("invalid utf8 comment should fail")
+	}
+
+	dns := DNSChangeRecord{QueryID: 1, Key: make([]byte, 32), Value: cell.BeginCell().MustStoreUInt(0x9fd3, 16).EndCell()}
+	dnsCell, err := tlb.ToCell(dns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err = Decode(dnsCell); err != nil || !bytes.Equal(v.(*DNSChangeRecord).Value.Hash(), dns.Value.Hash()) {
+		t.Fatal("incorrect dns record", err)
+	}
+
+	ext := WalletV5ExtensionAction{
+		QueryID: 3,
+		ExtendedActions: []any{
+			&WalletV5AddExtension{Address: addr},
+			&WalletV5SetSignatureAuthAllowed{Allowed: true},
+		},
+	}
+	extCell, err := tlb.ToCell(ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err = Decode(extCell)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ext2 := v.(*WalletV5ExtensionAction)
+	if ext2.QueryID != 3 || ext2.OutActions != nil || len(ext2.ExtendedActions) != 2 ||
+		!ext2.ExtendedActions[0].(*WalletV5AddExtension).Address.Equals(addr) ||
+		!ext2.ExtendedActions[1].(*WalletV5SetSignatureAuthAllowed).Allowed {
+		t.Fatal("incorrect extension action")
+	}
+
+	if _, err = Decode(cell.BeginCell().MustStoreUInt(0xdeadbeef, 32).EndCell()); !errors.Is(err, ErrUnknownOpcode) {
+		t.Fatal("should be unknown opcode", err)
+	}
+	if _, err = Decode(cell.BeginCell().EndCell()); !errors.Is(err, ErrEmptyPayload) {
+		t.Fatal("should be empty payload", err)
+	}
+}
+
+type testCustomOp struct {
+	_     tlb.Magic `tlb:"#deadbeef"`
+	Value uint32    `tlb:"## 32"`
+}
+
+func TestRegister(t *testing.T) {
+	Register(testCustomOp{})
+
+	v, err := Decode(cell.BeginCell().MustStoreUInt(0xdeadbeef, 32).MustStoreUInt(9, 32).EndCell())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.(*testCustomOp).Value != 9 {
+		t.Fatal("incorrect value")
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("should panic for short magic")
+			}
+		}()
+		Register(WalletV5AddExtension{})
+	}()
+}
@@ -0,0 +1,145 @@
+package payloads
+
+import (
+	"fmt"
+
+	"github.com/alan890104/tonutils-go/address"
+	"github.com/alan890104/tonutils-go/tlb"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+const WalletV5ExtensionActionOpcode = 0x6578746e
+
+/*
+extension_action#6578746e query_id:uint64 inner:InnerRequest = InternalMsgBody;
+actions$_ out_actions:(Maybe OutList) has_other_actions:(## 1) other_actions:has_other_actions?^ExtendedActions = InnerRequest;
+
+action_add_ext#02 addr:MsgAddressInt = ExtendedAction;
+action_delete_ext#03 addr:MsgAddressInt = ExtendedAction;
+action_set_signature_auth_allowed#04 allowed:(## 1) = ExtendedAction;
+*/
+
+// WalletV5ExtensionAction is sent by installed extension to wallet v5 to execute actions
+type WalletV5ExtensionAction struct {
+	_       tlb.Magic `tlb:"#6578746e"`
+	QueryID uint64
+	// OutActions is a list of out actions (send message, set code), nil if not present
+	OutActions *cell.Cell
+	// ExtendedActions are *WalletV5AddExtension, *WalletV5RemoveExtension or *WalletV5SetSignatureAuthAllowed
+	ExtendedActions []any
+}
+
+type WalletV5AddExtension struct {
+	_       tlb.Magic        `tlb:"#02"`
+	Address *address.Address `tlb:"addr"`
+}
+
+type WalletV5RemoveExtension struct {
+	_       tlb.Magic        `tlb:"#03"`
+	Address *address.Address `tlb:"addr"`
+}
+
+type WalletV5SetSignatureAuthAllowed struct {
+	_       tlb.Magic `tlb:"#04"`
+	Allowed bool      `tlb:"bool"`
+}
+
+func (w *WalletV5ExtensionAction) LoadFromCell(loader *cell.Slice) error {
+	if err := loadOpcode(loader, WalletV5ExtensionActionOpcode); err != nil {
+		return err
+	}
+
+	queryID, err := loader.LoadUInt(64)
+	if err != nil {
+		return fmt.Errorf("failed to load query id: %w", err)
+	}
+
+	outActions, err := loader.LoadMaybeRef()
+	if err != nil {
+		return fmt.Errorf("failed to load out actions: %w", err)
+	}
+
+	hasOther, err := loader.LoadBoolBit()
+	if err != nil {
+		return fmt.Errorf("failed to load has other actions flag: %w", err)
+	}
+
+	var extended []any
+	if hasOther {
+		next, err := loader.LoadRef()
+		if err != nil {
+			return fmt.Errorf("failed to load extended actions: %w", err)
+		}
+
+		for next != nil {
+			op, err := next.PreloadUInt(8)
+			if err != nil {
+				return fmt.Errorf("failed to load extended action op: %w", err)
+			}
+
+			var action any
+			switch op {
+			case 0x02:
+				action = &WalletV5AddExtension{}
+			case 0x03:
+				action = &WalletV5RemoveExtension{}
+			case 0x04:
+				action = &WalletV5SetSignatureAuthAllowed{}
+			default:
+				return fmt.Errorf("unknown extended action op %02x", op)
+			}
+
+			if err = tlb.LoadFromCell(action, next); err != nil {
+				return fmt.Errorf("failed to load extended action %d: %w", len(extended), err)
+			}
+			extended = append(extended, action)
+
+			if next.RefsNum() == 0 {
+				break
+			}
+			if next, err = next.LoadRef(); err != nil {
+				return fmt.Errorf("failed to load next extended action: %w", err)
+			}
+		}
+	}
+
+	if outActions != nil {
+		if w.OutActions, err = outActions.ToCell(); err != nil {
+			return fmt.Errorf("failed to convert out actions: %w", err)
+		}
+	} else {
+		w.OutActions = nil
+	}
+	w.QueryID = queryID
+	w.ExtendedActions = extended
+	return nil
+}
+
+func (w WalletV5ExtensionAction) ToCell() (*cell.Cell, error) {
+	b := cell.BeginCell().
+		MustStoreUInt(WalletV5ExtensionActionOpcode, 32).
+		MustStoreUInt(w.QueryID, 64).
+		MustStoreMaybeRef(w.OutActions).
+		MustStoreBoolBit(len(w.ExtendedActions) > 0)
+
+	if len(w.ExtendedActions) > 0 {
+		// chain is built from the end, each action refers to the next one
+		var next *cell.Cell
+		for i := len(w.ExtendedActions) - 1; i >= 0; i-- {
+			action, err := tlb.ToCell(w.ExtendedActions[i])
+			if err != nil {
+				return nil, fmt.Errorf("failed to serialize extended action %d: %w", i, err)
+			}
+
+			ab := action.ToBuilder()
+			if next != nil {
+				if err = ab.StoreRef(next); err != nil {
+					return nil, fmt.Errorf("failed to store next extended action: %w", err)
+				}
+			}
+			next = ab.EndCell()
+		}
+		b.MustStoreRef(next)
+	}
+	return b.EndCell(), nil
+}
@@ -0,0 +1,310 @@
+package tlb
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// fieldPlan is a pre-parsed tlb tag of struct field, it is built once per type,
+// so loader and serializer are not splitting and parsing tags on every call.
+// Tag errors are kept as panic messages and raised at the same place as before, when field is processed.
+type fieldPlan struct {
+	tag      string
+	skip     bool
+	tagPanic string
+	settings []string
+
+	// number argument of ##, bits, dict and var tags
+	num      uint64
+	numValid bool
+
+	magic      uint64
+	magicSize  uint
+	magicPanic string
+
+	// names of allowed types for interface fields
+	union      []string
+	unionPanic string
+
+	// options of either tag, each has exactly 1 setting
+	either           [2][]string
+	eitherLeaveBits  int
+	eitherLeaveRefs  int
+	eitherPanic      string
+	eitherLeavePanic string
+
+	// wrapper struct type for values of dict mapped to go map
+	dictValueType reflect.Type
+
+	// wrapper struct type for elements of array and list
+	elemType        reflect.Type
+	elemPanic       string
+	arrayCount      uint64
+	arrayCountField string
+
+	enum      []enumOption
+	enumPanic string
+}
+
+type enumOption struct {
+	name  string
+	value uint64
+	tag   uint64
+	size  uint
+}
+
+type structPlan struct {
+	fields []fieldPlan
+}
+
+var structPlans sync.Map // reflect.Type -> *structPlan
+
+func getStructPlan(t reflect.Type) *structPlan {
+	if p, ok := structPlans.Load(t); ok {
+		return p.(*structPlan)
+	}
+
+	p := &structPlan{
+		fields: make([]fieldPlan, t.NumField()),
+	}
+	for i := 0; i < t.NumField(); i++ {
+		compileFieldPlan(&p.fields[i], t.Field(i))
+	}
+
+	actual, _ := structPlans.LoadOrStore(t, p)
+	return actual.(*structPlan)
+}
+
+func compileFieldPlan(fp *fieldPlan, field reflect.StructField) {
+	fp.tag = strings.TrimSpace(field.Tag.Get("tlb"))
+	if fp.tag == "-" {
+		fp.skip = true
+		return
+	}
+	fp.settings = strings.Split(fp.tag, " ")
+	if fp.settings[0] == "" {
+		// untagged fields are not skipped silently, tlb:"-" should be used explicitly
+		fp.tagPanic = "no tlb tag on field " + field.Name + `, use tlb:"-" to skip it`
+		return
+	}
+
+	settings := fp.settings
+	if settings[0][0] == '?' {
+		settings = settings[1:]
+	}
+
+	if field.Type == reflect.TypeOf(Magic{}) && len(settings) > 0 {
+		fp.magic, fp.magicSize, fp.magicPanic = parseMagic(settings[0])
+	}
+
+scan:
+	for i, s := range settings {
+		var arg int
+		switch s {
+		case "##", "bits", "int", "uint", "varint":
+			arg = i + 1
+		case "dict":
+			arg = i + 1
+			if arg < len(settings) && settings[arg] == "inline" {
+				arg++
+			}
+		case "var":
+			arg = i + 2
+		case "either":
+			compileEither(fp, settings[i+1:])
+			continue
+		case "array":
+			if i+2 >= len(settings) {
+				fp.elemPanic = "array tag should have count and element tag"
+				break scan
+			}
+
+			count, err := strconv.ParseUint(settings[i+1], 10, 64)
+			if err != nil {
+				fp.arrayCountField = settings[i+1]
+			}
+			fp.arrayCount = count
+			compileElem(fp, field, settings[i+2:])
+			break scan
+		case "list":
+			compileElem(fp, field, settings[i+1:])
+			break scan
+		case "enum":
+			compileEnum(fp, field, settings[i+1:])
+			break scan
+		default:
+			if strings.HasPrefix(s, "[") {
+				compileUnion(fp, field, settings[i:])
+				break scan
+			}
+			continue
+		}
+
+		if arg < len(settings) {
+			num, err := strconv.ParseUint(settings[arg], 10, 64)
+			fp.num, fp.numValid = num, err == nil
+		}
+
+		if s == "dict" && field.Type.Kind() == reflect.Map {
+			if arg+2 < len(settings) && settings[arg+1] == "->" {
+				fp.dictValueType = reflect.StructOf([]reflect.StructField{{
+					Name: "Value",
+					Type: field.Type.Elem(),
+					Tag:  reflect.StructTag(fmt.Sprintf("tlb:%q", strings.Join(settings[arg+2:], " "))),
+				}})
+			}
+		}
+		break
+	}
+
+	if field.Type.Kind() == reflect.Interface && fp.union == nil && fp.unionPanic == "" {
+		fp.unionPanic = "corrupted allowed list tag of field " + field.Name + ", should be [a,b,c], got " + fp.tag
+	}
+}
+
+func compileElem(fp *fieldPlan, field reflect.StructField, settings []string) {
+	if field.Type.Kind() != reflect.Slice {
+		fp.elemPanic = "array and list tags can be used only with slice, field " + field.Name
+		return
+	}
+
+	if len(settings) == 0 {
+		settings = []string{"."}
+	}
+
+	fp.elemType = reflect.StructOf([]reflect.StructField{{
+		Name: "Value",
+		Type: field.Type.Elem(),
+		Tag:  reflect.StructTag(fmt.Sprintf("tlb:%q", strings.Join(settings, " "))),
+	}})
+}
+
+func compileEnum(fp *fieldPlan, field reflect.StructField, settings []string) {
+	typ := field.Type
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	isString := typ.Kind() == reflect.String
+	switch typ.Kind() {
+	case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		fp.enumPanic = "enum tag can be used only with string or integer, field " + field.Name
+		return
+	}
+
+	if len(settings) == 0 {
+		fp.enumPanic = "enum tag should have at least 1 option, field " + field.Name
+		return
+	}
+
+	for i, opt := range settings {
+		name, tag := "", opt
+		if idx := strings.LastIndexByte(opt, ':'); idx >= 0 {
+			name, tag = opt[:idx], opt[idx+1:]
+		}
+
+		if isString && name == "" {
+			fp.enumPanic = "enum option should have name for string field " + field.Name
+			return
+		}
+
+		value := uint64(i)
+		if !isString && name != "" {
+			v, err := strconv.ParseUint(name, 10, 64)
+			if err != nil {
+				fp.enumPanic = "enum option name should be a number for integer field " + field.Name
+				return
+			}
+			value = v
+		}
+
+		magic, sz, msg := parseMagic(tag)
+		if msg != "" {
+			fp.enumPanic = msg
+			return
+		}
+
+		fp.enum = append(fp.enum, enumOption{
+			name:  name,
+			value: value,
+			tag:   magic,
+			size:  sz,
+		})
+	}
+}
+
+func compileUnion(fp *fieldPlan, field reflect.StructField, settings []string) {
+	allowed := strings.Join(settings, "")
+	if !strings.HasPrefix(allowed, "[") || !strings.HasSuffix(allowed, "]") {
+		fp.unionPanic = "corrupted allowed list tag of field " + field.Name + ", should be [a,b,c], got " + allowed
+		return
+	}
+
+	// cut brackets
+	fp.union = strings.Split(allowed[1:len(allowed)-1], ",")
+}
+
+func compileEither(fp *fieldPlan, settings []string) {
+	if len(settings) < 2 {
+		fp.eitherPanic = "either tag should have 2 args"
+		return
+	}
+
+	if settings[0] == "leave" {
+		settings = settings[1:]
+
+		if len(settings) < 3 {
+			fp.eitherPanic = "either tag should have 2 args and leave tag should have 1 arg"
+			return
+		}
+
+		spl := strings.Split(settings[0], ",")
+		settings = settings[1:]
+
+		val, err := strconv.ParseUint(spl[0], 10, 10)
+		if err != nil {
+			fp.eitherLeavePanic = "invalid argument for either leave bits"
+		}
+		// set how many free bits we need to have after either written
+		fp.eitherLeaveBits = int(val)
+
+		if len(spl) > 1 {
+			val, err = strconv.ParseUint(spl[1], 10, 10)
+			if err != nil && fp.eitherLeavePanic == "" {
+				fp.eitherLeavePanic = "invalid argument for either leave refs"
+			}
+			// set how many free efs we need to have after either written
+			fp.eitherLeaveRefs = int(val)
+		}
+	}
+
+	fp.either = [2][]string{settings[0:1:1], settings[1:2:2]}
+}
+
+func parseMagic(tag string) (uint64, uint, string) {
+	var sz, base int
+	if strings.HasPrefix(tag, "#") {
+		base = 16
+		sz = (len(tag) - 1) * 4
+	} else if strings.HasPrefix(tag, "$") {
+		base = 2
+		sz = len(tag) - 1
+	} else {
+		return 0, 0, "unknown magic value type in tag: " + tag
+	}
+
+	if sz > 64 {
+		return 0, 0, "too big magic value type in tag"
+	}
+
+	magic, err := strconv.ParseUint(tag[1:], base, 64)
+	if err != nil {
+		return 0, 0, "corrupted magic value in tag"
+	}
+	return magic, uint(sz), ""
+}
@@ -1,14 +1,51 @@
 package tlb
 
 import (
+	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
+
+	"github.com/alan890104/tonutils-go/tvm/cell"
 )
 
-var registered = map[string]reflect.Type{}
+var (
+	registered   = map[string]reflect.Type{}
+	registeredMx sync.RWMutex
+)
 
 var magicType = reflect.TypeOf(Magic{})
 
+// Constructor describes registered tlb type
+type Constructor struct {
+	// Name is a registered name, used in union tags like [A,B]
+	Name string
+	Type reflect.Type
+	// Magic is a constructor prefix, which is checked before parsing
+	Magic     uint64
+	MagicBits uint
+	Fields    []ConstructorField
+}
+
+// ConstructorField is a layout of serializable field of registered type
+type ConstructorField struct {
+	Name string
+	// GoType is a go type of field, like uint64 or *cell.Cell
+	GoType string
+	// Tag is a tlb tag of field
+	Tag string
+}
+
+// ParseResult is a result of parsing cell with registered constructor
+type ParseResult struct {
+	Constructor Constructor
+	// Value is a pointer to loaded struct
+	Value any
+	// Complete is true when all bits and refs of cell were consumed by constructor
+	Complete bool
+}
+
 func register(name string, t reflect.Type) {
 	magic := t.Field(0)
 	if magic.Type != magicType {
@@ -20,7 +57,17 @@ func register(name string, t reflect.Type) {
 		panic("invalid magic tag")
 	}
 
+	registeredMx.Lock()
 	registered[name] = t
+	registeredMx.Unlock()
+}
+
+func getRegistered(name string) (reflect.Type, bool) {
+	registeredMx.RLock()
+	defer registeredMx.RUnlock()
+
+	t, ok := registered[name]
+	return t, ok
 }
 
 func RegisterWithName(name string, typ any) {
@@ -32,3 +79,112 @@ func Register(typ any) {
 	t := reflect.TypeOf(typ)
 	register(t.Name(), t)
 }
+
+func describeConstructor(name string, t reflect.Type) Constructor {
+	fp := &getStructPlan(t).fields[0]
+
+	c := Constructor{
+		Name:      name,
+		Type:      t,
+		Magic:     fp.magic,
+		MagicBits: fp.magicSize,
+	}
+
+	for i := 1; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if getStructPlan(t).fields[i].skip {
+			continue
+		}
+
+		c.Fields = append(c.Fields, ConstructorField{
+			Name:   f.Name,
+			GoType: f.Type.String(),
+			Tag:    strings.TrimSpace(f.Tag.Get("tlb")),
+		})
+	}
+	return c
+}
+
+// Constructors returns all registered constructors sorted by name
+func Constructors() []Constructor {
+	registeredMx.RLock()
+	list := make([]Constructor, 0, len(registered))
+	for name, t := range registered {
+		list = append(list, describeConstructor(name, t))
+	}
+	registeredMx.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// LookupConstructor returns registered constructor by name
+func LookupConstructor(name string) (Constructor, bool) {
+	t, ok := getRegistered(name)
+	if !ok {
+		return Constructor{}, false
+	}
+	return describeConstructor(name, t), true
+}
+
+// FindConstructors returns registered constructors which magic matches prefix of slice,
+// for example it can be used to find message body type by opcode.
+// Result is sorted by magic length, longest (most specific) first.
+func FindConstructors(loader *cell.Slice) []Constructor {
+	var list []Constructor
+	for _, c := range Constructors() {
+		if c.MagicBits > loader.BitsLeft() {
+			continue
+		}
+
+		if c.MagicBits > 0 {
+			v, err := loader.Copy().LoadUInt(c.MagicBits)
+			if err != nil || v != c.Magic {
+				continue
+			}
+		}
+		list = append(list, c)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].MagicBits > list[j].MagicBits
+	})
+	return list
+}
+
+// TryParse tries to load cell using all registered constructors with matching magic
+// and returns the ones which were parsed successfully.
+// Results that consumed the whole cell go first, then sorted by magic length.
+func TryParse(c *cell.Cell) []ParseResult {
+	var res []ParseResult
+	for _, con := range FindConstructors(c.BeginParse()) {
+		loader := c.BeginParse()
+		v := reflect.New(con.Type)
+		if err := parseSafe(v.Interface(), loader); err != nil {
+			continue
+		}
+
+		res = append(res, ParseResult{
+			Constructor: con,
+			Value:       v.Interface(),
+			Complete:    loader.BitsLeft() == 0 && loader.RefsNum() == 0,
+		})
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Complete && !res[j].Complete
+	})
+	return res
+}
+
+// parseSafe is LoadFromCell which returns error instead of panic on corrupted tags of registered types
+func parseSafe(v any, loader *cell.Slice) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to parse: %v", r)
+		}
+	}()
+	return LoadFromCell(v, loader)
+}
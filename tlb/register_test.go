@@ -0,0 +1,70 @@
+package tlb
+
+import (
+	"testing"
+
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+type testRegistryOp struct {
+	_       Magic  `tlb:"#7362d09c"`
+	QueryID uint64 `tlb:"## 64"`
+	Amount  Coins  `tlb:"."`
+}
+
+type testRegistryOpShort struct {
+	_    Magic  `tlb:"#73"`
+	Data uint32 `tlb:"## 24"`
+}
+
+func TestRegistry(t *testing.T) {
+	RegisterWithName("test_registry_op", testRegistryOp{})
+	RegisterWithName("test_registry_op_short", testRegistryOpShort{})
+
+	c, ok := LookupConstructor("test_registry_op")
+	if !ok {
+		t.Fatal("constructor not found")
+	}
+	if c.Magic != 0x7362d09c || c.MagicBits != 32 || len(c.Fields) != 2 ||
+		c.Fields[0].Name != "QueryID" || c.Fields[0].GoType != "uint64" || c.Fields[1].Tag != "." {
+		t.Fatal("incorrect constructor", c)
+	}
+
+	if _, ok = LookupConstructor("test_registry_unknown"); ok {
+		t.Fatal("should not be found")
+	}
+
+	found := false
+	for _, con := range Constructors() {
+		if con.Name == "InternalMessage" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("InternalMessage not in list")
+	}
+
+	body := cell.BeginCell().MustStoreUInt(0x7362d09c, 32).MustStoreUInt(5, 64).MustStoreCoins(7).EndCell()
+
+	cons := FindConstructors(body.BeginParse())
+	if len(cons) < 2 || cons[0].Name != "test_registry_op" {
+		t.Fatal("incorrect constructors found", cons)
+	}
+
+	res := TryParse(body)
+	if len(res) < 2 {
+		t.Fatal("incorrect parse results num", len(res))
+	}
+	if res[0].Constructor.Name != "test_registry_op" || !res[0].Complete {
+		t.Fatal("incorrect first result", res[0])
+	}
+	if v := res[0].Value.(*testRegistryOp); v.QueryID != 5 || v.Amount.Nano().Uint64() != 7 {
+		t.Fatal("incorrect value")
+	}
+
+	for _, r := range res[1:] {
+		if r.Complete {
+			t.Fatal("only first result should be complete", r.Constructor.Name)
+		}
+	}
+}
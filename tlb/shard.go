@@ -2,6 +2,7 @@ package tlb
 
 import (
 	"encoding/binary"
+	"fmt"
 
 	"github.com/alan890104/tonutils-go/address"
 	"github.com/alan890104/tonutils-go/tvm/cell"
@@ -14,6 +15,9 @@ func init() {
 
 	Register(ShardStateSplit{})
 	Register(ShardStateUnsplit{})
+
+	Register(BlockCreateStatsOrdinary{})
+	Register(BlockCreateStatsExt{})
 }
 
 type ShardID uint64
@@ -37,11 +41,11 @@ type ShardStateUnsplit struct {
 }
 
 type McStateExtra struct {
-	_             Magic              `tlb:"#cc26"`
-	ShardHashes   *cell.Dictionary   `tlb:"dict 32"`
-	ConfigParams  ConfigParams       `tlb:"."`
-	Info          *cell.Cell         `tlb:"^"`
-	GlobalBalance CurrencyCollection `tlb:"."`
+	_             Magic                 `tlb:"#cc26"`
+	ShardHashes   *cell.Dictionary      `tlb:"dict 32"`
+	ConfigParams  ConfigParams          `tlb:"."`
+	Info          McStateExtraBlockInfo `tlb:"^"`
+	GlobalBalance CurrencyCollection    `tlb:"."`
 }
 
 type KeyExtBlkRef struct {
@@ -61,11 +65,58 @@ type ValidatorInfo struct {
 }
 
 type McStateExtraBlockInfo struct {
-	Flags            uint16           `tlb:"## 16"`
-	ValidatorInfo    ValidatorInfo    `tlb:"."`
-	PrevBlocks       *cell.Dictionary `tlb:"dict 32"`
-	LastKeyBlock     *ExtBlkRef       `tlb:"maybe ."`
-	BlockCreateStats *cell.Cell       `tlb:"."`
+	Flags            uint16
+	ValidatorInfo    ValidatorInfo
+	PrevBlocks       OldMcBlocksInfo
+	AfterKeyBlock    bool
+	LastKeyBlock     *ExtBlkRef
+	BlockCreateStats *BlockCreateStats // has value when flags bit 0 is set
+}
+
+// OldMcBlocksInfo - HashmapAugE 32 KeyExtBlkRef KeyMaxLt, previous masterchain blocks by seqno
+type OldMcBlocksInfo struct {
+	Blocks *cell.Dictionary `tlb:"dict 32"`
+	Extra  KeyMaxLt         `tlb:"."`
+}
+
+type OldMcBlock struct {
+	SeqNo uint32
+	MaxLt KeyMaxLt
+	Block KeyExtBlkRef
+}
+
+type BlockCreateStats struct {
+	Stats any `tlb:"[BlockCreateStatsOrdinary,BlockCreateStatsExt]"`
+}
+
+type BlockCreateStatsOrdinary struct {
+	_        Magic            `tlb:"#17"`
+	Counters *cell.Dictionary `tlb:"dict 256"`
+}
+
+// BlockCreateStatsExt - counters are HashmapAugE 256 CreatorStats uint32
+type BlockCreateStatsExt struct {
+	_        Magic            `tlb:"#34"`
+	Counters *cell.Dictionary `tlb:"dict 256"`
+	Count    uint32           `tlb:"## 32"`
+}
+
+type Counters struct {
+	LastUpdated uint32 `tlb:"## 32"`
+	Total       uint64 `tlb:"## 64"`
+	Cnt2048     uint64 `tlb:"## 64"`
+	Cnt65536    uint64 `tlb:"## 64"`
+}
+
+type CreatorStats struct {
+	_           Magic    `tlb:"#4"`
+	McBlocks    Counters `tlb:"."`
+	ShardBlocks Counters `tlb:"."`
+}
+
+type ValidatorCreateStats struct {
+	PublicKey []byte
+	Stats     CreatorStats
 }
 
 type ConfigParams struct {
@@ -152,6 +203,241 @@ type ShardDescB struct {
 	FundsCreated       CurrencyCollection `tlb:"."`
 }
 
+func (s *ShardStateUnsplit) LoadMcStateExtra() (*McStateExtra, error) {
+	if s.McStateExtra == nil {
+		return nil, fmt.Errorf("not a masterchain state")
+	}
+
+	var extra McStateExtra
+	if err := LoadFromCellAsProof(&extra, s.McStateExtra.BeginParse()); err != nil {
+		return nil, fmt.Errorf("failed to load masterchain state extra: %w", err)
+	}
+	return &extra, nil
+}
+
+func (m *McStateExtraBlockInfo) LoadFromCell(loader *cell.Slice) error {
+	flags, err := loader.LoadUInt(16)
+	if err != nil {
+		return fmt.Errorf("failed to load flags: %w", err)
+	}
+	if flags > 1 {
+		return fmt.Errorf("unknown flags %d", flags)
+	}
+
+	var info McStateExtraBlockInfo
+	info.Flags = uint16(flags)
+
+	if err = LoadFromCell(&info.ValidatorInfo, loader); err != nil {
+		return fmt.Errorf("failed to load validator info: %w", err)
+	}
+
+	if err = LoadFromCell(&info.PrevBlocks, loader); err != nil {
+		return fmt.Errorf("failed to load prev blocks: %w", err)
+	}
+
+	if info.AfterKeyBlock, err = loader.LoadBoolBit(); err != nil {
+		return fmt.Errorf("failed to load after key block flag: %w", err)
+	}
+
+	hasLastKey, err := loader.LoadBoolBit()
+	if err != nil {
+		return fmt.Errorf("failed to load last key block flag: %w", err)
+	}
+
+	if hasLastKey {
+		var ref ExtBlkRef
+		if err = LoadFromCell(&ref, loader); err != nil {
+			return fmt.Errorf("failed to load last key block: %w", err)
+		}
+		info.LastKeyBlock = &ref
+	}
+
+	if info.Flags&1 != 0 {
+		var stats BlockCreateStats
+		if err = LoadFromCell(&stats, loader); err != nil {
+			return fmt.Errorf("failed to load block create stats: %w", err)
+		}
+		info.BlockCreateStats = &stats
+	}
+
+	*m = info
+	return nil
+}
+
+func (m McStateExtraBlockInfo) ToCell() (*cell.Cell, error) {
+	if (m.Flags&1 != 0) != (m.BlockCreateStats != nil) {
+		return nil, fmt.Errorf("flags bit 0 should be set only when block create stats are presented")
+	}
+
+	b := cell.BeginCell().MustStoreUInt(uint64(m.Flags), 16)
+
+	vi, err := ToCell(m.ValidatorInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize validator info: %w", err)
+	}
+
+	pb, err := ToCell(m.PrevBlocks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize prev blocks: %w", err)
+	}
+
+	if err = b.StoreBuilder(vi.ToBuilder()); err != nil {
+		return nil, fmt.Errorf("failed to store validator info: %w", err)
+	}
+	if err = b.StoreBuilder(pb.ToBuilder()); err != nil {
+		return nil, fmt.Errorf("failed to store prev blocks: %w", err)
+	}
+	if err = b.StoreBoolBit(m.AfterKeyBlock); err != nil {
+		return nil, fmt.Errorf("failed to store after key block flag: %w", err)
+	}
+	if err = b.StoreBoolBit(m.LastKeyBlock != nil); err != nil {
+		return nil, fmt.Errorf("failed to store last key block flag: %w", err)
+	}
+
+	if m.LastKeyBlock != nil {
+		lk, err := ToCell(m.LastKeyBlock)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize last key block: %w", err)
+		}
+		if err = b.StoreBuilder(lk.ToBuilder()); err != nil {
+			return nil, fmt.Errorf("failed to store last key block: %w", err)
+		}
+	}
+
+	if m.BlockCreateStats != nil {
+		st, err := ToCell(m.BlockCreateStats)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize block create stats: %w", err)
+		}
+		if err = b.StoreBuilder(st.ToBuilder()); err != nil {
+			return nil, fmt.Errorf("failed to store block create stats: %w", err)
+		}
+	}
+
+	return b.EndCell(), nil
+}
+
+// Get - returns reference to the previous masterchain block with the given seqno
+func (o *OldMcBlocksInfo) Get(seqno uint32) (*OldMcBlock, error) {
+	if o.Blocks == nil {
+		return nil, cell.ErrNoSuchKeyInDict
+	}
+
+	val, err := o.Blocks.LoadValue(cell.BeginCell().MustStoreUInt(uint64(seqno), 32).EndCell())
+	if err != nil {
+		return nil, err
+	}
+
+	blk, err := loadOldMcBlock(val)
+	if err != nil {
+		return nil, err
+	}
+	blk.SeqNo = seqno
+	return blk, nil
+}
+
+// LoadAll - returns all previous masterchain blocks presented in dictionary,
+// pruned branches are skipped, so it can be used on proofs
+func (o *OldMcBlocksInfo) LoadAll() ([]*OldMcBlock, error) {
+	if o.Blocks == nil {
+		return []*OldMcBlock{}, nil
+	}
+
+	kvs, err := o.Blocks.LoadAll(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load prev blocks dict: %w", err)
+	}
+
+	res := make([]*OldMcBlock, 0, len(kvs))
+	for _, kv := range kvs {
+		seqno, err := kv.Key.LoadUInt(32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load block seqno: %w", err)
+		}
+
+		blk, err := loadOldMcBlock(kv.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load block %d: %w", seqno, err)
+		}
+		blk.SeqNo = uint32(seqno)
+		res = append(res, blk)
+	}
+	return res, nil
+}
+
+// KeyBlocks - returns only key blocks from previous masterchain blocks list
+func (o *OldMcBlocksInfo) KeyBlocks() ([]*OldMcBlock, error) {
+	all, err := o.LoadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*OldMcBlock
+	for _, blk := range all {
+		if blk.Block.IsKey {
+			res = append(res, blk)
+		}
+	}
+	return res, nil
+}
+
+func loadOldMcBlock(loader *cell.Slice) (*OldMcBlock, error) {
+	var blk OldMcBlock
+	if err := LoadFromCell(&blk.MaxLt, loader); err != nil {
+		return nil, fmt.Errorf("failed to load KeyMaxLt: %w", err)
+	}
+	if err := LoadFromCell(&blk.Block, loader); err != nil {
+		return nil, fmt.Errorf("failed to load KeyExtBlkRef: %w", err)
+	}
+	return &blk, nil
+}
+
+// LoadAll - returns block creation counters of each validator, key is validator's public key
+func (b *BlockCreateStats) LoadAll() ([]*ValidatorCreateStats, error) {
+	var dict *cell.Dictionary
+	var withExtra bool
+
+	switch t := b.Stats.(type) {
+	case BlockCreateStatsOrdinary:
+		dict = t.Counters
+	case BlockCreateStatsExt:
+		dict, withExtra = t.Counters, true
+	default:
+		return nil, fmt.Errorf("unknown block create stats type")
+	}
+
+	if dict == nil {
+		return []*ValidatorCreateStats{}, nil
+	}
+
+	kvs, err := dict.LoadAll(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load counters dict: %w", err)
+	}
+
+	res := make([]*ValidatorCreateStats, 0, len(kvs))
+	for _, kv := range kvs {
+		key, err := kv.Key.LoadSlice(256)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load validator key: %w", err)
+		}
+
+		if withExtra {
+			// skip augmentation value
+			if _, err = kv.Value.LoadUInt(32); err != nil {
+				return nil, fmt.Errorf("failed to load counters extra: %w", err)
+			}
+		}
+
+		st := &ValidatorCreateStats{PublicKey: key}
+		if err = LoadFromCell(&st.Stats, kv.Value); err != nil {
+			return nil, fmt.Errorf("failed to load creator stats: %w", err)
+		}
+		res = append(res, st)
+	}
+	return res, nil
+}
+
 func (s ShardID) IsSibling(with ShardID) bool {
 	return (s^with) != 0 && ((s ^ with) == ((s & ShardID(bitsNegate64(uint64(s)))) << 1))
 }
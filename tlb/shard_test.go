@@ -427,3 +427,105 @@ func TestShardID_ContainsAddress(t *testing.T) {
 		})
 	}
 }
+
+func TestMcStateExtraBlockInfo_LoadFromCell(t *testing.T) {
+	prev := cell.NewDict(32)
+	for _, blk := range []struct {
+		seqno uint32
+		isKey bool
+	}{{100, false}, {101, true}, {102, false}} {
+		v := cell.BeginCell().
+			MustStoreBoolBit(blk.isKey).MustStoreUInt(uint64(blk.seqno)*1000, 64).
+			MustStoreBoolBit(blk.isKey).MustStoreUInt(uint64(blk.seqno)*1000, 64).MustStoreUInt(uint64(blk.seqno), 32).
+			MustStoreSlice(make([]byte, 32), 256).MustStoreSlice(make([]byte, 32), 256).EndCell()
+		if err := prev.Set(cell.BeginCell().MustStoreUInt(uint64(blk.seqno), 32).EndCell(), v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	key := make([]byte, 32)
+	key[0] = 0xAA
+
+	counters := cell.NewDict(256)
+	stats := cell.BeginCell().MustStoreUInt(7, 32).MustStoreUInt(0x4, 4).
+		MustStoreUInt(1, 32).MustStoreUInt(10, 64).MustStoreUInt(20, 64).MustStoreUInt(30, 64).
+		MustStoreUInt(1, 32).MustStoreUInt(40, 64).MustStoreUInt(50, 64).MustStoreUInt(60, 64).EndCell()
+	if err := counters.Set(cell.BeginCell().MustStoreSlice(key, 256).EndCell(), stats); err != nil {
+		t.Fatal(err)
+	}
+
+	info := McStateExtraBlockInfo{
+		Flags: 1,
+		ValidatorInfo: ValidatorInfo{
+			ValidatorListHashShort: 123,
+			CatchainSeqno:          456,
+		},
+		PrevBlocks: OldMcBlocksInfo{
+			Blocks: prev,
+			Extra:  KeyMaxLt{IsKey: true, MaxEndLT: 102000},
+		},
+		AfterKeyBlock: true,
+		LastKeyBlock: &ExtBlkRef{
+			EndLt:    101000,
+			SeqNo:    101,
+			RootHash: make([]byte, 32),
+			FileHash: make([]byte, 32),
+		},
+		BlockCreateStats: &BlockCreateStats{Stats: BlockCreateStatsExt{Counters: counters, Count: 1}},
+	}
+
+	c, err := ToCell(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var loaded McStateExtraBlockInfo
+	if err = LoadFromCell(&loaded, c.BeginParse()); err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.ValidatorInfo.CatchainSeqno != 456 || !loaded.AfterKeyBlock || loaded.LastKeyBlock == nil || loaded.LastKeyBlock.SeqNo != 101 {
+		t.Fatal("incorrect info fields")
+	}
+
+	if !loaded.PrevBlocks.Extra.IsKey || loaded.PrevBlocks.Extra.MaxEndLT != 102000 {
+		t.Fatal("incorrect prev blocks extra")
+	}
+
+	all, err := loaded.PrevBlocks.LoadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Fatal("incorrect prev blocks num", len(all))
+	}
+
+	keyBlocks, err := loaded.PrevBlocks.KeyBlocks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keyBlocks) != 1 || keyBlocks[0].SeqNo != 101 || keyBlocks[0].Block.BlkRef.EndLt != 101000 {
+		t.Fatal("incorrect key blocks")
+	}
+
+	blk, err := loaded.PrevBlocks.Get(102)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blk.Block.IsKey || blk.Block.BlkRef.SeqNo != 102 {
+		t.Fatal("incorrect block")
+	}
+
+	if _, err = loaded.PrevBlocks.Get(103); err == nil {
+		t.Fatal("should be not found")
+	}
+
+	creators, err := loaded.BlockCreateStats.LoadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(creators) != 1 || creators[0].PublicKey[0] != 0xAA ||
+		creators[0].Stats.McBlocks.Total != 10 || creators[0].Stats.ShardBlocks.Cnt65536 != 60 {
+		t.Fatal("incorrect creator stats")
+	}
+}
@@ -11,6 +11,9 @@ import (
 
 var ErrStackEmpty = errors.New("stack is empty")
 
+// MaxTupleSize is a max number of elements in tuple, allowed by TVM
+const MaxTupleSize = 255
+
 type Stack struct {
 	top *StackElement
 }
@@ -151,22 +154,25 @@ func SerializeStackValue(b *cell.Builder, val any) error {
 		b.MustStoreRef(v)
 	case *cell.Slice:
 		b.MustStoreUInt(0x04, 8)
-
-		// start data offset
-		b.MustStoreUInt(0, 10)
-		// end data offset
-		b.MustStoreUInt(uint64(v.BitsLeft()), 10)
-
-		// start refs offset
-		b.MustStoreUInt(0, 3)
-		// end refs offset
-		b.MustStoreUInt(uint64(v.RefsNum()), 3)
-
-		b.MustStoreRef(v.MustToCell())
+		storeVmCellSlice(b, v)
 	case *cell.Builder:
 		b.MustStoreUInt(0x05, 8)
 		b.MustStoreRef(v.EndCell())
+	case VmCont:
+		b.MustStoreUInt(0x06, 8)
+
+		c, err := v.ToCell()
+		if err != nil {
+			return fmt.Errorf("failed to serialize continuation: %w", err)
+		}
+		if err = b.StoreBuilder(c.ToBuilder()); err != nil {
+			return fmt.Errorf("failed to store continuation: %w", err)
+		}
 	case []any:
+		if len(v) > MaxTupleSize {
+			return fmt.Errorf("too big tuple size %d, max %d", len(v), MaxTupleSize)
+		}
+
 		b.MustStoreUInt(0x07, 8)
 		b.MustStoreUInt(uint64(len(v)), 16)
 
@@ -252,87 +258,27 @@ func ParseStackValue(slice *cell.Slice) (any, error) {
 		}
 		return val.MustToCell(), nil
 	case 0x04:
-		start, err := slice.LoadUInt(10)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load slice stack value's start, err: %w", err)
-		}
-		end, err := slice.LoadUInt(10)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load slice stack value's end, err: %w", err)
-		}
-		if start > end {
-			return nil, fmt.Errorf("start index > end index")
-		}
-
-		startRef, err := slice.LoadUInt(3)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load slice stack value's start ref, err: %w", err)
-		}
-		endRef, err := slice.LoadUInt(3)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load slice stack value's end ref, err: %w", err)
-		}
-		if startRef > endRef {
-			return nil, fmt.Errorf("start ref index > end ref index")
-		}
-
-		val, err := slice.LoadRef()
-		if err != nil {
-			return nil, fmt.Errorf("failed to load cell stack value, err: %w", err)
-		}
-
-		cl := cell.BeginCell()
-
-		if start > 0 {
-			_, err = val.LoadSlice(uint(start))
-			if err != nil {
-				return nil, fmt.Errorf("load prefix err: %w", err)
-			}
-		}
-
-		if end > 0 {
-			sz := uint(end - start)
-			data, err := val.LoadSlice(sz)
-			if err != nil {
-				return nil, fmt.Errorf("load prefix err: %w", err)
-			}
-
-			err = cl.StoreSlice(data, sz)
-			if err != nil {
-				return nil, fmt.Errorf("store slice err: %w", err)
-			}
-		}
-
-		for x := uint64(0); x < startRef; x++ {
-			_, err := val.LoadRef()
-			if err != nil {
-				return nil, fmt.Errorf("failed to load slice stack value's ref, err: %w", err)
-			}
-		}
-
-		for x := uint64(0); x < endRef-startRef; x++ {
-			sliceRef, err := val.LoadRef()
-			if err != nil {
-				return nil, fmt.Errorf("failed to load slice stack value's ref, err: %w", err)
-			}
-
-			err = cl.StoreRef(sliceRef.MustToCell())
-			if err != nil {
-				return nil, fmt.Errorf("failed to store slice stack value's ref, err: %w", err)
-			}
-		}
-		return cl.EndCell().BeginParse(), nil
+		return loadVmCellSlice(slice)
 	case 0x05:
 		val, err := slice.LoadRef()
 		if err != nil {
 			return nil, fmt.Errorf("failed to load cell stack value, err: %w", err)
 		}
 		return val.MustToCell().ToBuilder(), nil
+	case 0x06:
+		val, err := ParseVmCont(slice)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load continuation stack value, err: %w", err)
+		}
+		return val, nil
 	case 0x07:
 		ln, err := slice.LoadUInt(16)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load tuple stack value's len, err: %w", err)
 		}
+		if ln > MaxTupleSize {
+			return nil, fmt.Errorf("too big tuple size %d, max %d", ln, MaxTupleSize)
+		}
 
 		var tuple []any
 
@@ -388,3 +334,93 @@ func ParseStackValue(slice *cell.Slice) (any, error) {
 
 	return nil, errors.New("unknown value type")
 }
+
+// storeVmCellSlice serializes slice as VmCellSlice
+//
+//	_ cell:^Cell st_bits:(## 10) end_bits:(## 10) st_ref:(#<= 4) end_ref:(#<= 4) = VmCellSlice;
+func storeVmCellSlice(b *cell.Builder, v *cell.Slice) {
+	// start data offset
+	b.MustStoreUInt(0, 10)
+	// end data offset
+	b.MustStoreUInt(uint64(v.BitsLeft()), 10)
+
+	// start refs offset
+	b.MustStoreUInt(0, 3)
+	// end refs offset
+	b.MustStoreUInt(uint64(v.RefsNum()), 3)
+
+	b.MustStoreRef(v.MustToCell())
+}
+
+func loadVmCellSlice(slice *cell.Slice) (*cell.Slice, error) {
+	start, err := slice.LoadUInt(10)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load slice stack value's start, err: %w", err)
+	}
+	end, err := slice.LoadUInt(10)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load slice stack value's end, err: %w", err)
+	}
+	if start > end {
+		return nil, fmt.Errorf("start index > end index")
+	}
+
+	startRef, err := slice.LoadUInt(3)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load slice stack value's start ref, err: %w", err)
+	}
+	endRef, err := slice.LoadUInt(3)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load slice stack value's end ref, err: %w", err)
+	}
+	if startRef > endRef {
+		return nil, fmt.Errorf("start ref index > end ref index")
+	}
+
+	val, err := slice.LoadRef()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cell stack value, err: %w", err)
+	}
+
+	cl := cell.BeginCell()
+
+	if start > 0 {
+		_, err = val.LoadSlice(uint(start))
+		if err != nil {
+			return nil, fmt.Errorf("load prefix err: %w", err)
+		}
+	}
+
+	if end > 0 {
+		sz := uint(end - start)
+		data, err := val.LoadSlice(sz)
+		if err != nil {
+			return nil, fmt.Errorf("load prefix err: %w", err)
+		}
+
+		err = cl.StoreSlice(data, sz)
+		if err != nil {
+			return nil, fmt.Errorf("store slice err: %w", err)
+		}
+	}
+
+	for x := uint64(0); x < startRef; x++ {
+		_, err := val.LoadRef()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load slice stack value's ref, err: %w", err)
+		}
+	}
+
+	for x := uint64(0); x < endRef-startRef; x++ {
+		sliceRef, err := val.LoadRef()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load slice stack value's ref, err: %w", err)
+		}
+
+		err = cl.StoreRef(sliceRef.MustToCell())
+		if err != nil {
+			return nil, fmt.Errorf("failed to store slice stack value's ref, err: %w", err)
+		}
+	}
+	return cl.EndCell().BeginParse(), nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"math/big"
+	"reflect"
 	"testing"
 
 	"github.com/alan890104/tonutils-go/tvm/cell"
@@ -131,3 +132,97 @@ func TestParseStackValue(t *testing.T) {
 		t.Fatal("rebuild not same", err)
 	}
 }
+
+func TestParseStackValue_Continuation(t *testing.T) {
+	nargs := uint16(2)
+	cp := int16(0)
+
+	st := NewStack()
+	st.Push(int64(5))
+	st.Push(cell.BeginCell().EndCell())
+
+	quit := &VmContQuit{ExitCode: 0}
+	std := &VmContStd{
+		Data: VmControlData{
+			NumArgs: &nargs,
+			Stack:   st,
+			Save: VmSaveList{
+				0: quit,
+				4: cell.BeginCell().MustStoreUInt(1, 8).EndCell(),
+				7: []any{int64(1), nil},
+			},
+			CP: &cp,
+		},
+		Code: cell.BeginCell().MustStoreUInt(0xABCD, 16).MustStoreRef(cell.BeginCell().EndCell()).EndCell().BeginParse(),
+	}
+
+	conts := []VmCont{
+		std,
+		&VmContEnvelope{Next: std},
+		quit,
+		&VmContQuitExc{},
+		&VmContRepeat{Count: 10, Body: std, After: quit},
+		&VmContUntil{Body: std, After: quit},
+		&VmContAgain{Body: std},
+		&VmContWhile{Cond: std, Body: std, After: quit},
+		&VmContWhile{InBody: true, Cond: std, Body: std, After: quit},
+		&VmContPushInt{Value: -7, Next: quit},
+	}
+
+	for i, cont := range conts {
+		b := cell.BeginCell()
+		if err := SerializeStackValue(b, cont); err != nil {
+			t.Fatal(i, err)
+		}
+		c := b.EndCell()
+
+		val, err := ParseStackValue(c.BeginParse())
+		if err != nil {
+			t.Fatal(i, err)
+		}
+
+		if reflect.TypeOf(val) != reflect.TypeOf(cont) {
+			t.Fatalf("%d: incorrect type %T", i, val)
+		}
+
+		b = cell.BeginCell()
+		if err = SerializeStackValue(b, val); err != nil {
+			t.Fatal(i, err)
+		}
+
+		if !bytes.Equal(b.EndCell().Hash(), c.Hash()) {
+			t.Fatal(i, "rebuild not same")
+		}
+	}
+
+	stdCell, err := std.ToCell()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseVmCont(stdCell.BeginParse())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := parsed.(*VmContStd)
+	if *p.Data.NumArgs != 2 || p.Data.Stack.Depth() != 2 || len(p.Data.Save) != 3 || p.Code.BitsLeft() != 16 {
+		t.Fatal("incorrect std continuation")
+	}
+	if _, ok := p.Data.Save[0].(*VmContQuit); !ok {
+		t.Fatal("incorrect c0")
+	}
+	if w := conts[8].(*VmContWhile); !w.InBody {
+		t.Fatal("incorrect while")
+	}
+}
+
+func TestSerializeStackValue_TupleLimit(t *testing.T) {
+	if err := SerializeStackValue(cell.BeginCell(), make([]any, MaxTupleSize+1)); err == nil {
+		t.Fatal("should be error for too big tuple")
+	}
+
+	c := cell.BeginCell().MustStoreUInt(0x07, 8).MustStoreUInt(MaxTupleSize+1, 16).EndCell()
+	if _, err := ParseStackValue(c.BeginParse()); err == nil {
+		t.Fatal("should be error for too big tuple")
+	}
+}
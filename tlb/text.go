@@ -2,14 +2,22 @@ package tlb
 
 import (
 	"fmt"
+	"unicode/utf8"
 
 	"github.com/alan890104/tonutils-go/tvm/cell"
 )
 
 const MaxTextChunkSize = 127 - 2
 
+// Text is a chunked utf8 string
+//
+//	text$_ chunks:(## 8) rest:(TextChunks chunks) = Text;
+//
+// Chunks are split only on runes boundaries, MaxChunks limits the number of chunks on load and store,
+// when it is 0 the only limit is 255.
 type Text struct {
 	MaxFirstChunkSize uint8
+	MaxChunks         uint8
 	Value             string
 }
 
@@ -19,8 +27,12 @@ func (t *Text) LoadFromCell(loader *cell.Slice) error {
 		return fmt.Errorf("failed to load chunks num: %w", err)
 	}
 
+	if t.MaxChunks > 0 && num > uint64(t.MaxChunks) {
+		return fmt.Errorf("too many chunks: %d, max %d", num, t.MaxChunks)
+	}
+
 	firstSz := uint8(0)
-	var res string
+	var res []byte
 	for i := 0; i < int(num); i++ {
 		ln, err := loader.LoadUInt(8)
 		if err != nil {
@@ -35,7 +47,7 @@ func (t *Text) LoadFromCell(loader *cell.Slice) error {
 		if err != nil {
 			return fmt.Errorf("failed to load data of chunk %d: %w", i, err)
 		}
-		res += string(data)
+		res = append(res, data...)
 
 		if i < int(num)-1 {
 			loader, err = loader.LoadRef()
@@ -45,7 +57,11 @@ func (t *Text) LoadFromCell(loader *cell.Slice) error {
 		}
 	}
 
-	t.Value = res
+	if !utf8.Valid(res) {
+		return cell.ErrInvalidUTF8
+	}
+
+	t.Value = string(res)
 	t.MaxFirstChunkSize = firstSz
 	return nil
 }
@@ -61,44 +77,48 @@ func (t Text) ToCell() (*cell.Cell, error) {
 	if t.MaxFirstChunkSize == 0 {
 		return nil, fmt.Errorf("first chunk size should be > 0")
 	}
-
-	val := []byte(t.Value)
-	leftSz := len(val) - int(t.MaxFirstChunkSize)
-	chunksNum := 1
-	if leftSz > 0 {
-		chunksNum += leftSz / MaxTextChunkSize
-		if leftSz%MaxTextChunkSize > 0 {
-			chunksNum++
-		}
+	if !utf8.ValidString(t.Value) {
+		return nil, cell.ErrInvalidUTF8
 	}
 
-	if chunksNum > 255 {
-		return nil, fmt.Errorf("too big data")
+	maxChunks := 255
+	if t.MaxChunks > 0 {
+		maxChunks = int(t.MaxChunks)
 	}
 
-	var f func(depth int) *cell.Builder
-	f = func(depth int) *cell.Builder {
-		c := cell.BeginCell()
-		sz := uint8(MaxTextChunkSize)
-		if depth == 0 {
-			sz = t.MaxFirstChunkSize
+	var chunks [][]byte
+	val := []byte(t.Value)
+	for sz := int(t.MaxFirstChunkSize); len(val) > 0; sz = MaxTextChunkSize {
+		if sz > len(val) {
+			sz = len(val)
 		}
-		if int(sz) > len(val) {
-			sz = uint8(len(val))
+
+		// do not split runes between chunks
+		for sz > 0 && sz < len(val) && !utf8.RuneStart(val[sz]) {
+			sz--
 		}
 
-		c.MustStoreUInt(uint64(sz), 8)
-		c.MustStoreSlice(val[:sz], uint(sz)*8)
+		chunks = append(chunks, val[:sz])
 		val = val[sz:]
 
-		if depth != chunksNum-1 {
-			c.MustStoreRef(f(depth + 1).EndCell())
+		if len(chunks) > maxChunks {
+			return nil, fmt.Errorf("too big data")
 		}
-		return c
 	}
 
-	return cell.BeginCell().
-		MustStoreUInt(uint64(chunksNum), 8).
-		MustStoreBuilder(f(0)).
-		EndCell(), nil
+	var next *cell.Cell
+	for i := len(chunks) - 1; i >= 0; i-- {
+		c := cell.BeginCell()
+		if i == 0 {
+			c.MustStoreUInt(uint64(len(chunks)), 8)
+		}
+
+		c.MustStoreUInt(uint64(len(chunks[i])), 8)
+		c.MustStoreSlice(chunks[i], uint(len(chunks[i]))*8)
+		if next != nil {
+			c.MustStoreRef(next)
+		}
+		next = c.EndCell()
+	}
+	return next, nil
 }
@@ -1,7 +1,11 @@
 package tlb
 
 import (
+	"strings"
 	"testing"
+	"unicode/utf8"
+
+	"github.com/alan890104/tonutils-go/tvm/cell"
 )
 
 func TestText(t *testing.T) {
@@ -35,3 +39,47 @@ func TestText(t *testing.T) {
 		}
 	}
 }
+
+func TestText_UTF8(t *testing.T) {
+	str := strings.Repeat("дом 🏠 ", 40)
+
+	cl, err := ToCell(Text{MaxFirstChunkSize: 31, Value: str})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sl := cl.BeginParse()
+	num := sl.MustLoadUInt(8)
+	for i := 0; i < int(num); i++ {
+		ln := sl.MustLoadUInt(8)
+		if !utf8.Valid(sl.MustLoadSlice(uint(ln) * 8)) {
+			t.Fatal("chunk", i, "is not valid utf8")
+		}
+		if i < int(num)-1 {
+			sl = sl.MustLoadRef()
+		}
+	}
+
+	txt := Text{}
+	if err = LoadFromCell(&txt, cl.BeginParse()); err != nil || txt.Value != str {
+		t.Fatal("incorrect value", err)
+	}
+
+	limited := Text{MaxChunks: 2}
+	if err = LoadFromCell(&limited, cl.BeginParse()); err == nil {
+		t.Fatal("chunks limit should be checked on load")
+	}
+
+	if _, err = ToCell(Text{MaxFirstChunkSize: 31, MaxChunks: 2, Value: str}); err == nil {
+		t.Fatal("chunks limit should be checked on store")
+	}
+
+	if _, err = ToCell(Text{MaxFirstChunkSize: 31, Value: "\xff"}); err == nil {
+		t.Fatal("invalid utf8 should fail on store")
+	}
+
+	bad := cell.BeginCell().MustStoreUInt(1, 8).MustStoreUInt(1, 8).MustStoreUInt(0xff, 8).EndCell()
+	if err = LoadFromCell(&txt, bad.BeginParse()); err == nil {
+		t.Fatal("invalid utf8 should fail on load")
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/alan890104/tonutils-go/tlb/tlbgen"
+)
+
+func main() {
+	in := flag.String("in", "", "comma separated list of .tlb schema files")
+	out := flag.String("out", "", "output go file, stdout if not specified")
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "package name of generated file")
+	flag.Parse()
+
+	if *in == "" {
+		log.Fatal("schema files are not specified, use -in flag")
+	}
+
+	var src strings.Builder
+	for _, file := range strings.Split(*in, ",") {
+		data, err := os.ReadFile(strings.TrimSpace(file))
+		if err != nil {
+			log.Fatalf("failed to read schema: %s", err.Error())
+		}
+		src.Write(data)
+		src.WriteByte('\n')
+	}
+
+	schema, err := tlbgen.Parse(src.String())
+	if err != nil {
+		log.Fatalf("failed to parse schema: %s", err.Error())
+	}
+
+	code, err := tlbgen.Generate(schema, tlbgen.Options{
+		Package: *pkg,
+		Header:  "Source: " + *in,
+		Warn: func(msg string) {
+			log.Println("[WARNING]", msg)
+		},
+	})
+	if err != nil {
+		log.Fatalf("failed to generate code: %s", err.Error())
+	}
+
+	if *out == "" {
+		_, _ = os.Stdout.Write(code)
+		return
+	}
+
+	if err = os.WriteFile(*out, code, 0644); err != nil {
+		log.Fatalf("failed to write result: %s", err.Error())
+	}
+}
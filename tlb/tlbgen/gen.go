@@ -0,0 +1,978 @@
+package tlbgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+const (
+	tlbPkg     = "github.com/alan890104/tonutils-go/tlb"
+	cellPkg    = "github.com/alan890104/tonutils-go/tvm/cell"
+	addressPkg = "github.com/alan890104/tonutils-go/address"
+)
+
+// Options of code generation
+type Options struct {
+	// Package is a name of package for generated file
+	Package string
+	// Header is an optional comment added on top of file
+	Header string
+	// Warn is called for each skipped parameterized constructor, they are also listed in generated file
+	Warn func(msg string)
+}
+
+type kind int
+
+const (
+	kindUint kind = iota
+	kindInt
+	kindBigUint
+	kindBigInt
+	kindBits
+	kindBool
+	kindRestCell
+	kindRefCell
+	kindRef
+	kindMaybe
+	kindEither
+	kindEitherAny
+	kindDict
+	kindAddr
+	kindCoins
+	kindVarUint
+	kindVarInt
+	kindStruct
+	kindReflect
+	kindEmpty
+)
+
+type goType struct {
+	kind kind
+	// name of go type for named kinds
+	name string
+	// size in bits, for dynamic sizes it is a go expression
+	size string
+	// go integer type for uints and ints
+	intType string
+	// inline dictionary (Hashmap, not HashmapE)
+	inline bool
+	inner  *goType
+	second *goType
+}
+
+func (t *goType) goName() string {
+	switch t.kind {
+	case kindUint, kindInt:
+		return t.intType
+	case kindBigUint, kindBigInt, kindVarUint, kindVarInt:
+		return "*big.Int"
+	case kindBits:
+		return "[]byte"
+	case kindBool:
+		return "bool"
+	case kindRestCell, kindRefCell:
+		return "*cell.Cell"
+	case kindRef:
+		return t.inner.goName()
+	case kindMaybe:
+		if t.inner.nilable() {
+			return t.inner.goName()
+		}
+		return "*" + t.inner.goName()
+	case kindEither:
+		return t.inner.goName()
+	case kindEitherAny:
+		return "any"
+	case kindDict:
+		return "*cell.Dictionary"
+	case kindAddr:
+		return "*address.Address"
+	case kindCoins:
+		return "tlb.Coins"
+	case kindStruct, kindReflect:
+		return t.name
+	}
+	return "struct{}"
+}
+
+func (t *goType) nilable() bool {
+	switch t.kind {
+	case kindBigUint, kindBigInt, kindVarUint, kindVarInt, kindBits, kindRestCell, kindRefCell, kindEitherAny, kindDict, kindAddr:
+		return true
+	case kindRef, kindMaybe, kindEither:
+		return t.inner.nilable()
+	}
+	return false
+}
+
+type structDef struct {
+	name   string
+	ctor   *Constructor
+	fields []*Field
+	// names of go fields, same order as fields
+	goFields []string
+	types    []*goType
+	// anon is set for structs of anonymous cells, they have no tag
+	anon bool
+}
+
+type unionDef struct {
+	name  string
+	ctors []*structDef
+}
+
+type generator struct {
+	types   map[string][]*Constructor
+	defs    map[string]*structDef
+	ordered []*structDef
+	unions  []*unionDef
+	// go names of generated types, by tl-b type name
+	typeNames map[string]string
+	// parameterized types of schema, they can be used only by reference and kept as cells
+	paramTypes map[string]bool
+	buf        *bytes.Buffer
+	tmp        int
+}
+
+// Generate produces go source with types and reflection-free LoadFromCell and ToCell methods for all
+// non-parameterized constructors of schema. Types with single constructor become structs named as TL-B type,
+// types with multiple constructors become a struct with Value field, which holds one of constructor structs,
+// like union fields of reflective loader do.
+func Generate(s *Schema, opts Options) ([]byte, error) {
+	if opts.Package == "" {
+		return nil, fmt.Errorf("package name is not specified")
+	}
+
+	g := &generator{
+		types:      map[string][]*Constructor{},
+		defs:       map[string]*structDef{},
+		typeNames:  map[string]string{},
+		paramTypes: map[string]bool{},
+		buf:        &bytes.Buffer{},
+	}
+
+	var typeOrder []string
+	var skipped []string
+	for _, c := range s.Constructors {
+		if builtinTypes[c.TypeName] {
+			continue
+		}
+		if c.Parameterized {
+			g.paramTypes[c.TypeName] = true
+			skipped = append(skipped, c.String())
+			if opts.Warn != nil {
+				opts.Warn(fmt.Sprintf("constructor '%s' of parameterized type '%s' is skipped, fields of this type are kept as cells", c.Name, c.TypeName))
+			}
+			continue
+		}
+		if _, ok := g.types[c.TypeName]; !ok {
+			typeOrder = append(typeOrder, c.TypeName)
+		}
+		g.types[c.TypeName] = append(g.types[c.TypeName], c)
+	}
+
+	usedNames := map[string]bool{}
+	for _, name := range typeOrder {
+		g.typeNames[name] = goName(name)
+		usedNames[goName(name)] = true
+	}
+
+	for _, name := range typeOrder {
+		ctors := g.types[name]
+		if len(ctors) == 1 {
+			g.addStruct(g.typeNames[name], ctors[0], ctors[0].Fields)
+			continue
+		}
+
+		u := &unionDef{name: g.typeNames[name]}
+		for i, c := range ctors {
+			ctorName := goName(c.Name)
+			if c.Name == "_" || usedNames[ctorName] {
+				ctorName = u.name + ctorName
+			}
+			if c.Name == "_" || usedNames[ctorName] {
+				ctorName = u.name + strconv.Itoa(i)
+			}
+			usedNames[ctorName] = true
+
+			u.ctors = append(u.ctors, g.addStruct(ctorName, c, c.Fields))
+		}
+		g.unions = append(g.unions, u)
+	}
+
+	for i := 0; i < len(g.ordered); i++ {
+		// anonymous cells are appended to the list during resolve
+		if err := g.resolve(g.ordered[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, d := range g.ordered {
+		if err := g.writeStruct(d); err != nil {
+			return nil, err
+		}
+	}
+	for _, u := range g.unions {
+		g.writeUnion(u)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by tlbgen. DO NOT EDIT.\n")
+	if opts.Header != "" {
+		for _, l := range strings.Split(strings.TrimSpace(opts.Header), "\n") {
+			out.WriteString("// " + l + "\n")
+		}
+	}
+	out.WriteString("\npackage " + opts.Package + "\n\nimport (\n")
+
+	for _, imp := range []struct {
+		path, selector string
+	}{
+		{"fmt", "fmt."},
+		{"math/big", "big."},
+		{"", ""},
+		{addressPkg, "address."},
+		{tlbPkg, "tlb."},
+		{cellPkg, "cell."},
+	} {
+		if imp.path == "" {
+			// separate std imports
+			out.WriteString("\n")
+			continue
+		}
+		if bytes.Contains(g.buf.Bytes(), []byte(imp.selector)) {
+			out.WriteString(strconv.Quote(imp.path) + "\n")
+		}
+	}
+	out.WriteString(")\n\n")
+	if len(skipped) > 0 {
+		out.WriteString("// Parameterized constructors are not generated, fields of their types are kept as cells:\n")
+		for _, c := range skipped {
+			out.WriteString("//\t" + c + "\n")
+		}
+		out.WriteString("\n")
+	}
+	out.Write(g.buf.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return src, nil
+}
+
+var builtinTypes = map[string]bool{
+	"Unit": true, "True": true, "Bool": true, "Bit": true, "Maybe": true, "Either": true, "Both": true,
+	"Hashmap": true, "HashmapE": true, "HashmapNode": true, "HmLabel": true, "Unary": true,
+	"HashmapAug": true, "HashmapAugE": true, "HashmapAugNode": true, "VarHashmap": true,
+	"VarUInteger": true, "VarInteger": true, "Grams": true, "Coins": true,
+	"MsgAddress": true, "MsgAddressInt": true, "MsgAddressExt": true, "Anycast": true,
+	"CurrencyCollection": true, "ExtraCurrencyCollection": true, "StateInit": true, "Message": true,
+}
+
+func (g *generator) addStruct(name string, c *Constructor, fields []*Field) *structDef {
+	d := &structDef{name: name, ctor: c, fields: fields}
+	g.defs[name] = d
+	g.ordered = append(g.ordered, d)
+	return d
+}
+
+func (g *generator) resolve(d *structDef) error {
+	for i, f := range d.fields {
+		name := goName(f.Name)
+		if f.Name == "" {
+			name = "Field" + strconv.Itoa(i+1)
+		}
+
+		typ, err := g.resolveType(d, name, f.Type)
+		if err != nil {
+			return fmt.Errorf("constructor '%s', field '%s': %w", d.ctor.Name, f.String(), err)
+		}
+
+		if f.CondField != "" {
+			if g.fieldIndex(d, f.CondField, i) < 0 {
+				return fmt.Errorf("constructor '%s', field '%s': condition field '%s' is not declared before",
+					d.ctor.Name, f.String(), f.CondField)
+			}
+			typ = &goType{kind: kindMaybe, inner: typ}
+		}
+
+		d.goFields = append(d.goFields, name)
+		d.types = append(d.types, typ)
+	}
+	return nil
+}
+
+func (g *generator) fieldIndex(d *structDef, name string, before int) int {
+	for i := 0; i < before && i < len(d.fields); i++ {
+		if d.fields[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// sizeExpr converts nat expression to go expression, fields of struct can be referenced
+func (g *generator) sizeExpr(d *structDef, e *TypeExpr) (string, uint64, error) {
+	if e.IsNum {
+		return strconv.FormatUint(e.Num, 10), e.Num, nil
+	}
+
+	if e.Name == "*" || e.Name == "+" {
+		l, lv, err := g.sizeExpr(d, e.Args[0])
+		if err != nil {
+			return "", 0, err
+		}
+		r, rv, err := g.sizeExpr(d, e.Args[1])
+		if err != nil {
+			return "", 0, err
+		}
+
+		var v uint64
+		if lv > 0 && rv > 0 {
+			if e.Name == "*" {
+				v = lv * rv
+			} else {
+				v = lv + rv
+			}
+		}
+		if v > 0 {
+			return strconv.FormatUint(v, 10), v, nil
+		}
+		return "(" + l + " " + e.Name + " " + r + ")", 0, nil
+	}
+
+	if e.Ref || len(e.Args) > 0 || e.Anon != nil {
+		return "", 0, fmt.Errorf("unsupported nat expression '%s'", e.String())
+	}
+
+	idx := g.fieldIndex(d, e.Name, len(d.goFields))
+	if idx < 0 {
+		return "", 0, fmt.Errorf("nat field '%s' is not declared before", e.Name)
+	}
+	return "uint(v." + d.goFields[idx] + ")", 0, nil
+}
+
+func intType(sz uint64, signed bool) string {
+	var t string
+	switch {
+	case sz <= 8:
+		t = "int8"
+	case sz <= 16:
+		t = "int16"
+	case sz <= 32:
+		t = "int32"
+	default:
+		t = "int64"
+	}
+	if !signed {
+		t = "u" + t
+	}
+	return t
+}
+
+func (g *generator) uintType(d *structDef, e *TypeExpr, signed bool) (*goType, error) {
+	expr, sz, err := g.sizeExpr(d, e)
+	if err != nil {
+		return nil, err
+	}
+
+	if sz == 0 && e.IsNum {
+		return &goType{kind: kindEmpty}, nil
+	}
+	if sz == 0 {
+		// dynamic size, limited by 64 bits
+		if signed {
+			return &goType{kind: kindInt, size: expr, intType: "int64"}, nil
+		}
+		return &goType{kind: kindUint, size: expr, intType: "uint64"}, nil
+	}
+
+	if sz > 64 {
+		if signed {
+			return &goType{kind: kindBigInt, size: expr}, nil
+		}
+		return &goType{kind: kindBigUint, size: expr}, nil
+	}
+
+	if signed {
+		return &goType{kind: kindInt, size: expr, intType: intType(sz, true)}, nil
+	}
+	return &goType{kind: kindUint, size: expr, intType: intType(sz, false)}, nil
+}
+
+func (g *generator) resolveType(d *structDef, field string, e *TypeExpr) (*goType, error) {
+	if e.Ref {
+		if g.paramTypes[e.Args[0].Name] {
+			// layout depends on arguments, so it is not generated
+			return &goType{kind: kindRefCell}, nil
+		}
+
+		inner, err := g.resolveType(d, field, e.Args[0])
+		if err != nil {
+			return nil, err
+		}
+		if inner.kind == kindRestCell {
+			return &goType{kind: kindRefCell}, nil
+		}
+		return &goType{kind: kindRef, inner: inner}, nil
+	}
+
+	if e.Anon != nil {
+		name := d.name + field
+		if _, ok := g.defs[name]; ok {
+			return nil, fmt.Errorf("name conflict for anonymous cell type '%s'", name)
+		}
+		def := g.addStruct(name, d.ctor, e.Anon)
+		def.anon = true
+		return &goType{kind: kindStruct, name: def.name}, nil
+	}
+
+	if e.IsNum {
+		return nil, fmt.Errorf("number cannot be used as a type")
+	}
+
+	args := e.Args
+	argc := func(n int) error {
+		if len(args) != n {
+			return fmt.Errorf("type '%s' should have %d arguments", e.Name, n)
+		}
+		return nil
+	}
+
+	switch e.Name {
+	case "#":
+		return &goType{kind: kindUint, size: "32", intType: "uint32"}, argc(0)
+	case "##":
+		if err := argc(1); err != nil {
+			return nil, err
+		}
+		return g.uintType(d, args[0], false)
+	case "#<=", "#<":
+		if err := argc(1); err != nil {
+			return nil, err
+		}
+		if !args[0].IsNum {
+			return nil, fmt.Errorf("only constant bounds are supported for '%s'", e.Name)
+		}
+		n := args[0].Num
+		if e.Name == "#<" {
+			if n == 0 {
+				return nil, fmt.Errorf("invalid bound for '#<'")
+			}
+			n--
+		}
+		return g.uintType(d, &TypeExpr{IsNum: true, Num: uint64(bits.Len64(n))}, false)
+	case "uint", "int", "bits":
+		if err := argc(1); err != nil {
+			return nil, err
+		}
+		if e.Name == "bits" {
+			expr, _, err := g.sizeExpr(d, args[0])
+			if err != nil {
+				return nil, err
+			}
+			return &goType{kind: kindBits, size: expr}, nil
+		}
+		return g.uintType(d, args[0], e.Name == "int")
+	case "Bool", "Bit":
+		return &goType{kind: kindBool}, argc(0)
+	case "Cell", "Any":
+		return &goType{kind: kindRestCell}, argc(0)
+	case "Unit", "True":
+		return &goType{kind: kindEmpty}, argc(0)
+	case "Maybe":
+		if err := argc(1); err != nil {
+			return nil, err
+		}
+		inner, err := g.resolveType(d, field, args[0])
+		if err != nil {
+			return nil, err
+		}
+		return &goType{kind: kindMaybe, inner: inner}, nil
+	case "Either":
+		if err := argc(2); err != nil {
+			return nil, err
+		}
+		left, err := g.resolveType(d, field+"Left", args[0])
+		if err != nil {
+			return nil, err
+		}
+		right, err := g.resolveType(d, field+"Right", args[1])
+		if err != nil {
+			return nil, err
+		}
+		if left.goName() == right.goName() {
+			return &goType{kind: kindEither, inner: left, second: right}, nil
+		}
+		return &goType{kind: kindEitherAny, inner: left, second: right}, nil
+	case "HashmapE", "Hashmap":
+		if err := argc(2); err != nil {
+			return nil, err
+		}
+		expr, sz, err := g.sizeExpr(d, args[0])
+		if err != nil {
+			return nil, err
+		}
+		if sz == 0 {
+			return nil, fmt.Errorf("dictionary key size should be constant")
+		}
+		return &goType{kind: kindDict, size: expr, inline: e.Name == "Hashmap"}, nil
+	case "VarUInteger", "VarInteger":
+		if err := argc(1); err != nil {
+			return nil, err
+		}
+		expr, _, err := g.sizeExpr(d, args[0])
+		if err != nil {
+			return nil, err
+		}
+		if e.Name == "VarInteger" {
+			return &goType{kind: kindVarInt, size: expr}, nil
+		}
+		return &goType{kind: kindVarUint, size: expr}, nil
+	case "Grams", "Coins":
+		return &goType{kind: kindCoins}, argc(0)
+	case "MsgAddress", "MsgAddressInt", "MsgAddressExt":
+		return &goType{kind: kindAddr}, argc(0)
+	case "CurrencyCollection", "StateInit":
+		return &goType{kind: kindReflect, name: "tlb." + e.Name}, argc(0)
+	case "Message":
+		return &goType{kind: kindReflect, name: "tlb.Message"}, nil
+	}
+
+	for _, pfx := range []string{"uint", "int", "bits"} {
+		if !strings.HasPrefix(e.Name, pfx) {
+			continue
+		}
+		n, err := strconv.ParseUint(e.Name[len(pfx):], 10, 64)
+		if err != nil {
+			continue
+		}
+		if err = argc(0); err != nil {
+			return nil, err
+		}
+		if pfx == "bits" {
+			return &goType{kind: kindBits, size: strconv.FormatUint(n, 10)}, nil
+		}
+		return g.uintType(d, &TypeExpr{IsNum: true, Num: n}, pfx == "int")
+	}
+
+	if g.paramTypes[e.Name] {
+		return nil, fmt.Errorf("parameterized type '%s' can be used only by reference", e.String())
+	}
+
+	if name, ok := g.typeNames[e.Name]; ok {
+		if err := argc(0); err != nil {
+			return nil, err
+		}
+		return &goType{kind: kindStruct, name: name}, nil
+	}
+
+	return nil, fmt.Errorf("unknown or parameterized type '%s'", e.String())
+}
+
+func (g *generator) p(format string, args ...any) {
+	fmt.Fprintf(g.buf, format, args...)
+	g.buf.WriteByte('\n')
+}
+
+func (g *generator) tmpVar(name string) string {
+	g.tmp++
+	return name + strconv.Itoa(g.tmp)
+}
+
+func (g *generator) writeStruct(d *structDef) error {
+	if d.anon {
+		g.p("// %s - anonymous cell of %s", d.name, d.ctor.Name)
+	} else {
+		g.p("// %s - %s", d.name, d.ctor.String())
+	}
+	g.p("type %s struct {", d.name)
+	for i, t := range d.types {
+		if t.kind == kindEmpty {
+			continue
+		}
+		g.p("%s %s", d.goFields[i], t.goName())
+	}
+	g.p("}\n")
+
+	g.tmp = 0
+	g.p("func (v *%s) LoadFromCell(loader *cell.Slice) error {", d.name)
+	if d.ctor.TagBits > 0 && !d.anon {
+		g.p("tag, err := loader.LoadUInt(%d)", d.ctor.TagBits)
+		g.p("if err != nil {")
+		g.p("return fmt.Errorf(\"failed to load tag: %%w\", err)")
+		g.p("}")
+		g.p("if tag != 0x%x {", d.ctor.TagValue)
+		g.p("return fmt.Errorf(\"incorrect tag %%x for %s, expected %x\", tag)", d.ctor.Name, d.ctor.TagValue)
+		g.p("}")
+	}
+	for i, t := range d.types {
+		if t.kind == kindEmpty {
+			continue
+		}
+		f := d.fields[i]
+		dst := "v." + d.goFields[i]
+		if f.CondField != "" {
+			g.p("if %s {", g.condExpr(d, f))
+			g.load(t.inner, dst, "loader", d.goFields[i], true)
+			g.p("}")
+			continue
+		}
+		g.load(t, dst, "loader", d.goFields[i], false)
+	}
+	g.p("return nil")
+	g.p("}\n")
+
+	g.tmp = 0
+	g.p("func (v %s) ToCell() (*cell.Cell, error) {", d.name)
+	g.p("b := cell.BeginCell()")
+	if d.ctor.TagBits > 0 && !d.anon {
+		g.p("if err := b.StoreUInt(0x%x, %d); err != nil {", d.ctor.TagValue, d.ctor.TagBits)
+		g.p("return nil, fmt.Errorf(\"failed to store tag: %%w\", err)")
+		g.p("}")
+	}
+	for i, t := range d.types {
+		if t.kind == kindEmpty {
+			continue
+		}
+		f := d.fields[i]
+		src := "v." + d.goFields[i]
+		if f.CondField != "" {
+			g.p("if %s {", g.condExpr(d, f))
+			g.p("if %s == nil {", src)
+			g.p("return nil, fmt.Errorf(\"field %s is required by condition\")", d.goFields[i])
+			g.p("}")
+			g.store(t.inner, g.deref(t, src), "b", d.goFields[i])
+			g.p("}")
+			continue
+		}
+		g.store(t, src, "b", d.goFields[i])
+	}
+	g.p("return b.EndCell(), nil")
+	g.p("}\n")
+	return nil
+}
+
+func (g *generator) condExpr(d *structDef, f *Field) string {
+	idx := g.fieldIndex(d, f.CondField, len(d.fields))
+	if f.CondBit < 0 {
+		if d.types[idx].kind == kindBool {
+			return "v." + d.goFields[idx]
+		}
+		// nat field, like not_master:(## 1)
+		return "v." + d.goFields[idx] + " != 0"
+	}
+	return fmt.Sprintf("(v.%s>>%d)&1 == 1", d.goFields[idx], f.CondBit)
+}
+
+// deref returns expression of maybe type's inner value
+func (g *generator) deref(t *goType, src string) string {
+	if t.inner.nilable() {
+		return src
+	}
+	return "*" + src
+}
+
+// load writes statements which load value of type t from loader to dst,
+// when ptr is set dst is a pointer and loaded value should be allocated.
+func (g *generator) load(t *goType, dst, loader, field string, ptr bool) {
+	if ptr && !t.nilable() {
+		val := g.tmpVar("val")
+		g.p("var %s %s", val, t.goName())
+		g.load(t, val, loader, field, false)
+		g.p("%s = &%s", dst, val)
+		return
+	}
+
+	errRet := func(what string) {
+		g.p("if err != nil {")
+		g.p("return fmt.Errorf(\"failed to load %s of %s: %%w\", err)", what, field)
+		g.p("}")
+	}
+
+	switch t.kind {
+	case kindUint, kindInt:
+		fn := "LoadUInt"
+		if t.kind == kindInt {
+			fn = "LoadInt"
+		}
+		val := g.tmpVar("val")
+		g.p("%s, err := %s.%s(%s)", val, loader, fn, t.size)
+		errRet("integer")
+		if t.intType == "uint64" || t.intType == "int64" {
+			g.p("%s = %s", dst, val)
+		} else {
+			g.p("%s = %s(%s)", dst, t.intType, val)
+		}
+	case kindBigUint, kindBigInt, kindVarUint, kindVarInt, kindBits, kindBool, kindAddr, kindRestCell, kindRefCell, kindDict:
+		var call, what string
+		switch t.kind {
+		case kindBigUint:
+			call, what = "LoadBigUInt("+t.size+")", "integer"
+		case kindBigInt:
+			call, what = "LoadBigInt("+t.size+")", "integer"
+		case kindVarUint:
+			call, what = "LoadVarUInt("+t.size+")", "var integer"
+		case kindVarInt:
+			call, what = "LoadVarInt("+t.size+")", "var integer"
+		case kindBits:
+			call, what = "LoadSlice("+t.size+")", "bits"
+		case kindBool:
+			call, what = "LoadBoolBit()", "bool"
+		case kindAddr:
+			call, what = "LoadAddr()", "address"
+		case kindRestCell:
+			call, what = "ToCell()", "cell"
+		case kindRefCell:
+			call, what = "LoadRefCell()", "ref"
+		case kindDict:
+			call, what = "LoadDict("+t.size+")", "dict"
+			if t.inline {
+				call = "ToDict(" + t.size + ")"
+			}
+		}
+		val := g.tmpVar("val")
+		g.p("%s, err := %s.%s", val, loader, call)
+		errRet(what)
+		g.p("%s = %s", dst, val)
+	case kindRef:
+		ref := g.tmpVar("ref")
+		g.p("%s, err := %s.LoadRef()", ref, loader)
+		errRet("ref")
+		g.load(t.inner, dst, ref, field, false)
+	case kindMaybe:
+		has := g.tmpVar("has")
+		g.p("%s, err := %s.LoadBoolBit()", has, loader)
+		errRet("maybe bit")
+		g.p("if %s {", has)
+		g.load(t.inner, dst, loader, field, true)
+		g.p("}")
+	case kindEither:
+		isRight := g.tmpVar("isRight")
+		g.p("%s, err := %s.LoadBoolBit()", isRight, loader)
+		errRet("either bit")
+		g.p("if %s {", isRight)
+		g.load(t.second, dst, loader, field, false)
+		g.p("} else {")
+		g.load(t.inner, dst, loader, field, false)
+		g.p("}")
+	case kindEitherAny:
+		isRight := g.tmpVar("isRight")
+		g.p("%s, err := %s.LoadBoolBit()", isRight, loader)
+		errRet("either bit")
+		g.p("if %s {", isRight)
+		g.load(t.second, dst, loader, field, true)
+		g.p("} else {")
+		g.load(t.inner, dst, loader, field, true)
+		g.p("}")
+	case kindStruct, kindCoins:
+		g.p("if err := %s.LoadFromCell(%s); err != nil {", dst, loader)
+		g.p("return fmt.Errorf(\"failed to load %s: %%w\", err)", field)
+		g.p("}")
+	case kindReflect:
+		g.p("if err := tlb.LoadFromCell(&%s, %s); err != nil {", dst, loader)
+		g.p("return fmt.Errorf(\"failed to load %s: %%w\", err)", field)
+		g.p("}")
+	}
+}
+
+// store writes statements which store src of type t to builder
+func (g *generator) store(t *goType, src, builder, field string) {
+	errRet := func(what string) {
+		g.p("return nil, fmt.Errorf(\"failed to store %s of %s: %%w\", err)", what, field)
+	}
+
+	switch t.kind {
+	case kindUint, kindInt, kindBigUint, kindBigInt, kindVarUint, kindVarInt, kindBits, kindBool, kindAddr, kindRefCell, kindDict:
+		var call, what string
+		switch t.kind {
+		case kindUint:
+			if t.intType != "uint64" {
+				src = "uint64(" + src + ")"
+			}
+			call, what = "StoreUInt("+src+", "+t.size+")", "integer"
+		case kindInt:
+			if t.intType != "int64" {
+				src = "int64(" + src + ")"
+			}
+			call, what = "StoreInt("+src+", "+t.size+")", "integer"
+		case kindBigUint:
+			call, what = "StoreBigUInt("+src+", "+t.size+")", "integer"
+		case kindBigInt:
+			call, what = "StoreBigInt("+src+", "+t.size+")", "integer"
+		case kindVarUint:
+			call, what = "StoreBigVarUInt("+src+", "+t.size+")", "var integer"
+		case kindVarInt:
+			call, what = "StoreBigVarInt("+src+", "+t.size+")", "var integer"
+		case kindBits:
+			call, what = "StoreSlice("+src+", "+t.size+")", "bits"
+		case kindBool:
+			call, what = "StoreBoolBit("+src+")", "bool"
+		case kindAddr:
+			call, what = "StoreAddr("+src+")", "address"
+		case kindRefCell:
+			call, what = "StoreRef("+src+")", "ref"
+		case kindDict:
+			call, what = "StoreDict("+src+")", "dict"
+			if t.inline {
+				g.p("if %s == nil {", src)
+				g.p("return nil, fmt.Errorf(\"dict %s should not be empty\")", field)
+				g.p("}")
+				val := g.tmpVar("dict")
+				g.p("%s, err := %s.ToCell()", val, src)
+				g.p("if err != nil {")
+				errRet(what)
+				g.p("}")
+				call = "StoreBuilder(" + val + ".ToBuilder())"
+			}
+		}
+		g.p("if err := %s.%s; err != nil {", builder, call)
+		errRet(what)
+		g.p("}")
+	case kindRestCell:
+		g.p("if %s != nil {", src)
+		g.p("if err := %s.StoreBuilder(%s.ToBuilder()); err != nil {", builder, src)
+		errRet("cell")
+		g.p("}")
+		g.p("}")
+	case kindRef:
+		ref := g.tmpVar("ref")
+		g.p("%s := cell.BeginCell()", ref)
+		g.store(t.inner, src, ref, field)
+		g.p("if err := %s.StoreRef(%s.EndCell()); err != nil {", builder, ref)
+		errRet("ref")
+		g.p("}")
+	case kindMaybe:
+		g.p("if %s != nil {", src)
+		g.p("if err := %s.StoreBoolBit(true); err != nil {", builder)
+		errRet("maybe bit")
+		g.p("}")
+		g.store(t.inner, g.deref(t, src), builder, field)
+		g.p("} else if err := %s.StoreBoolBit(false); err != nil {", builder)
+		errRet("maybe bit")
+		g.p("}")
+	case kindEither:
+		// same as reflective loader, try to store first option, and second if there is no space
+		left, right := g.tmpVar("left"), g.tmpVar("right")
+		g.p("%s := cell.BeginCell()", left)
+		g.store(t.inner, src, left, field)
+		g.p("if %s.BitsLeft() > %s.BitsUsed() && %s.RefsLeft() >= uint(%s.RefsUsed()) {", builder, left, builder, left)
+		g.p("if err := %s.StoreBoolBit(false); err != nil {", builder)
+		errRet("either bit")
+		g.p("}")
+		g.p("if err := %s.StoreBuilder(%s); err != nil {", builder, left)
+		errRet("either value")
+		g.p("}")
+		g.p("} else {")
+		g.p("%s := cell.BeginCell()", right)
+		g.store(t.second, src, right, field)
+		g.p("if err := %s.StoreBoolBit(true); err != nil {", builder)
+		errRet("either bit")
+		g.p("}")
+		g.p("if err := %s.StoreBuilder(%s); err != nil {", builder, right)
+		errRet("either value")
+		g.p("}")
+		g.p("}")
+	case kindEitherAny:
+		val := g.tmpVar("val")
+		g.p("switch %s := %s.(type) {", val, src)
+		for i, opt := range []*goType{t.inner, t.second} {
+			ptr := ""
+			if !opt.nilable() {
+				ptr = "*"
+			}
+			g.p("case %s%s:", ptr, opt.goName())
+			g.p("if err := %s.StoreBoolBit(%v); err != nil {", builder, i == 1)
+			errRet("either bit")
+			g.p("}")
+			g.store(opt, ptr+val, builder, field)
+		}
+		g.p("default:")
+		g.p("return nil, fmt.Errorf(\"unexpected type %%T of %s\", %s)", field, src)
+		g.p("}")
+	case kindStruct, kindCoins, kindReflect:
+		c := g.tmpVar("c")
+		if t.kind == kindReflect {
+			g.p("%s, err := tlb.ToCell(%s)", c, src)
+		} else {
+			// value receiver method can be called on pointer too
+			g.p("%s, err := %s.ToCell()", c, strings.TrimPrefix(src, "*"))
+		}
+		g.p("if err != nil {")
+		errRet("value")
+		g.p("}")
+		g.p("if err = %s.StoreBuilder(%s.ToBuilder()); err != nil {", builder, c)
+		errRet("value")
+		g.p("}")
+	}
+}
+
+func (g *generator) writeUnion(u *unionDef) {
+	var names []string
+	for _, c := range u.ctors {
+		names = append(names, c.name)
+	}
+
+	g.p("// %s - one of: %s", u.name, strings.Join(names, ", "))
+	g.p("type %s struct {", u.name)
+	g.p("Value any")
+	g.p("}\n")
+
+	g.p("func (v *%s) LoadFromCell(loader *cell.Slice) error {", u.name)
+	for _, c := range u.ctors {
+		if c.ctor.TagBits > 0 {
+			g.p("if tag, err := loader.PreloadUInt(%d); err == nil && tag == 0x%x {", c.ctor.TagBits, c.ctor.TagValue)
+		} else {
+			g.p("{")
+		}
+		g.p("var val %s", c.name)
+		g.p("if err := val.LoadFromCell(loader); err != nil {")
+		g.p("return fmt.Errorf(\"failed to load %s: %%w\", err)", c.name)
+		g.p("}")
+		g.p("v.Value = val")
+		g.p("return nil")
+		g.p("}")
+		if c.ctor.TagBits == 0 {
+			// constructor without tag matches everything, rest are unreachable
+			g.p("}\n")
+			return
+		}
+	}
+	g.p("return fmt.Errorf(\"unknown constructor of %s\")", u.name)
+	g.p("}\n")
+
+	g.p("func (v %s) ToCell() (*cell.Cell, error) {", u.name)
+	g.p("switch val := v.Value.(type) {")
+	for _, c := range u.ctors {
+		g.p("case %s:", c.name)
+		g.p("return val.ToCell()")
+		g.p("case *%s:", c.name)
+		g.p("return val.ToCell()")
+	}
+	g.p("}")
+	g.p("return nil, fmt.Errorf(\"unexpected type %%T of %s value\", v.Value)", u.name)
+	g.p("}\n")
+}
+
+// goName converts tl-b name to exported go name: msg_envelope_v2 -> MsgEnvelopeV2
+func goName(name string) string {
+	var sb strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '.'
+	}) {
+		sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	if sb.Len() == 0 {
+		return "X"
+	}
+	res := sb.String()
+	if res[0] >= '0' && res[0] <= '9' {
+		res = "X" + res
+	}
+	return res
+}
@@ -0,0 +1,224 @@
+package tlbgen
+
+import (
+	"bytes"
+	"go/ast"
+	"go/importer"
+	goparser "go/parser"
+	gotoken "go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSchema = `
+// builtins are skipped
+bool_false$0 = Bool;
+hm_edge#_ {n:#} {X:Type} {l:#} {m:#} label:(HmLabel ~l n) {n = (~m) + l} node:(HashmapNode m X) = Hashmap n X;
+
+/* jetton transfer */
+transfer#0f8a7ea5 query_id:uint64 amount:(VarUInteger 16) destination:MsgAddress
+           response_destination:MsgAddress custom_payload:(Maybe ^Cell)
+           forward_ton_amount:(VarUInteger 16) forward_payload:(Either Cell ^Cell)
+           = InternalMsgBody;
+burn#595f07bc query_id:uint64 amount:Grams flags:(## 8) extra:flags.0?uint32 = InternalMsgBody;
+
+point$_ x:(## 8) y:(#<= 60) data:(bits (x * 8)) = Point;
+holder#12 p:^Point opt:(Maybe Point) either:(Either Point ^Point) d:(HashmapE 32 Point)
+  inf:^[ a:uint32 b:Bool c:b?^Cell ] { a <= 5 } = Holder;
+`
+
+func TestParse(t *testing.T) {
+	s, err := Parse(testSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s.Constructors) != 6 {
+		t.Fatal("incorrect constructors num", len(s.Constructors))
+	}
+
+	if !s.Constructors[1].Parameterized || s.Constructors[0].Parameterized {
+		t.Fatal("incorrect parameterized flag")
+	}
+
+	tr := s.Constructors[2]
+	if tr.Name != "transfer" || tr.TagBits != 32 || tr.TagValue != 0x0f8a7ea5 || tr.TypeName != "InternalMsgBody" || len(tr.Fields) != 7 {
+		t.Fatal("incorrect transfer constructor")
+	}
+	if tr.Fields[6].Type.String() != "(Either Cell ^Cell)" {
+		t.Fatal("incorrect either type", tr.Fields[6].Type.String())
+	}
+
+	burn := s.Constructors[3]
+	if burn.Fields[3].CondField != "flags" || burn.Fields[3].CondBit != 0 {
+		t.Fatal("incorrect condition")
+	}
+
+	if s.Constructors[0].TagBits != 1 || s.Constructors[0].TagValue != 0 {
+		t.Fatal("incorrect binary tag")
+	}
+
+	holder := s.Constructors[5]
+	if holder.String() != "holder#12 p:^Point opt:(Maybe Point) either:(Either Point ^Point) d:(HashmapE 32 Point) "+
+		"inf:^[ a:uint32 b:Bool c:b?^Cell ] = Holder;" {
+		t.Fatal("incorrect holder", holder.String())
+	}
+
+	s, err = Parse(`info#9bc7a987 flags:(## 8) { flags <= 1 } seq_no:# { prev_seq_no:# } { ~prev_seq_no + 1 = seq_no }
+  gen_software:flags . 0?GlobalVersion prev_ref:^(BlkPrevInfo 0) = BlockInfo;
+prev_blks_info$_ prev1:^ExtBlkRef prev2:^ExtBlkRef = BlkPrevInfo 1;`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := s.Constructors[0]
+	if info.Parameterized || len(info.Fields) != 4 {
+		t.Fatal("implicit fields should not make constructor parameterized")
+	}
+	if info.Fields[2].CondField != "flags" || info.Fields[2].CondBit != 0 {
+		t.Fatal("incorrect spaced condition")
+	}
+	if !s.Constructors[1].Parameterized || s.Constructors[1].String() != "prev_blks_info$_ prev1:^ExtBlkRef prev2:^ExtBlkRef = BlkPrevInfo 1;" {
+		t.Fatal("incorrect parameterized constructor", s.Constructors[1].String())
+	}
+
+	for _, bad := range []string{
+		"a#12 x:uint32 = A",
+		"a#12 x:(## 32 = A;",
+		"a#zz x:uint32 = A;",
+		"a#12 x:uint32;",
+	} {
+		if _, err = Parse(bad); err == nil {
+			t.Fatal("should be error for", bad)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	s, err := Parse(testSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, err := Generate(s, Options{Package: "example"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(code)
+
+	for _, exp := range []string{
+		"package example",
+		"type InternalMsgBody struct {\n\tValue any\n}",
+		"func (v *Transfer) LoadFromCell(loader *cell.Slice) error {",
+		"func (v Transfer) ToCell() (*cell.Cell, error) {",
+		"CustomPayload       *cell.Cell",
+		"Extra   *uint32",
+		"if (v.Flags>>0)&1 == 1 {",
+		"loader.LoadSlice((uint(v.X) * 8))",
+		"Y    uint8",
+		"Opt    *Point",
+		"Inf    HolderInf",
+		"type HolderInf struct {",
+		"loader.PreloadUInt(32); err == nil && tag == 0x595f07bc",
+		"\"github.com/alan890104/tonutils-go/tlb\"",
+	} {
+		if !strings.Contains(src, exp) {
+			t.Fatal("generated code has no:", exp)
+		}
+	}
+
+	if strings.Contains(src, "type Bool ") || strings.Contains(src, "type Hashmap ") {
+		t.Fatal("builtins should not be generated")
+	}
+
+	var warns []string
+	s, err = Parse("prev$_ x:uint32 = Prev 0;\na#1 p:^(Prev 0) = A;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err = Generate(s, Options{Package: "example", Warn: func(msg string) {
+		warns = append(warns, msg)
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warns) != 1 || !strings.Contains(warns[0], "'prev'") {
+		t.Fatal("skipped constructor is not reported", warns)
+	}
+	if !strings.Contains(string(code), "//\tprev$_ x:uint32 = Prev 0;") || !strings.Contains(string(code), "P *cell.Cell") {
+		t.Fatal("skipped constructor is not listed or field is not kept as cell")
+	}
+
+	s, err = Parse("a#1 p:(Prev 0) = A;\nprev$_ x:uint32 = Prev 0;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Generate(s, Options{Package: "example"}); err == nil {
+		t.Fatal("should be error for inline parameterized type")
+	}
+
+	s, err = Parse("a#1 x:(SomeType 5) = A;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Generate(s, Options{Package: "example"}); err == nil {
+		t.Fatal("should be error for unknown type")
+	}
+}
+
+func TestGenerate_Golden(t *testing.T) {
+	for _, pkg := range []string{"gentest", "blocktest"} {
+		dir := filepath.Join("internal", pkg)
+
+		schema, err := os.ReadFile(filepath.Join(dir, "schema.tlb"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, err := Parse(string(schema))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		code, err := Generate(s, Options{Package: pkg, Header: "Source: schema.tlb"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// golden packages are compiled and tested against real data, so they should be up to date
+		golden, err := os.ReadFile(filepath.Join(dir, "gen.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(code, golden) {
+			t.Fatal("generated code differs from golden file, run go generate in", dir)
+		}
+	}
+}
+
+func TestGenerate_TypeCheck(t *testing.T) {
+	s, err := Parse(testSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, err := Generate(s, Options{Package: "example"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fset := gotoken.NewFileSet()
+	f, err := goparser.ParseFile(fset, "gen.go", code, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// dependencies are type checked from source, so no build cache is needed
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err = conf.Check("example", fset, []*ast.File{f}, nil); err != nil {
+		t.Fatal("generated code is not compilable:", err)
+	}
+}
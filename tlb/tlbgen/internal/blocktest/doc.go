@@ -0,0 +1,5 @@
+// Package blocktest contains code generated by tlbgen from schema.tlb, which is an unmodified excerpt of block.tlb,
+// it is used to check that generator understands upstream schema syntax.
+package blocktest
+
+//go:generate go run ../../cmd/tlbgen -in schema.tlb -out gen.go -pkg blocktest
@@ -0,0 +1,456 @@
+// Code generated by tlbgen. DO NOT EDIT.
+// Source: schema.tlb
+
+package blocktest
+
+import (
+	"fmt"
+
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+// Parameterized constructors are not generated, fields of their types are kept as cells:
+//	prev_blk_info$_ prev:ExtBlkRef = BlkPrevInfo 0;
+//	prev_blks_info$_ prev1:^ExtBlkRef prev2:^ExtBlkRef = BlkPrevInfo 1;
+
+// ExtBlkRef - ext_blk_ref$_ end_lt:uint64 seq_no:uint32 root_hash:bits256 file_hash:bits256 = ExtBlkRef;
+type ExtBlkRef struct {
+	EndLt    uint64
+	SeqNo    uint32
+	RootHash []byte
+	FileHash []byte
+}
+
+func (v *ExtBlkRef) LoadFromCell(loader *cell.Slice) error {
+	val1, err := loader.LoadUInt(64)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of EndLt: %w", err)
+	}
+	v.EndLt = val1
+	val2, err := loader.LoadUInt(32)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of SeqNo: %w", err)
+	}
+	v.SeqNo = uint32(val2)
+	val3, err := loader.LoadSlice(256)
+	if err != nil {
+		return fmt.Errorf("failed to load bits of RootHash: %w", err)
+	}
+	v.RootHash = val3
+	val4, err := loader.LoadSlice(256)
+	if err != nil {
+		return fmt.Errorf("failed to load bits of FileHash: %w", err)
+	}
+	v.FileHash = val4
+	return nil
+}
+
+func (v ExtBlkRef) ToCell() (*cell.Cell, error) {
+	b := cell.BeginCell()
+	if err := b.StoreUInt(v.EndLt, 64); err != nil {
+		return nil, fmt.Errorf("failed to store integer of EndLt: %w", err)
+	}
+	if err := b.StoreUInt(uint64(v.SeqNo), 32); err != nil {
+		return nil, fmt.Errorf("failed to store integer of SeqNo: %w", err)
+	}
+	if err := b.StoreSlice(v.RootHash, 256); err != nil {
+		return nil, fmt.Errorf("failed to store bits of RootHash: %w", err)
+	}
+	if err := b.StoreSlice(v.FileHash, 256); err != nil {
+		return nil, fmt.Errorf("failed to store bits of FileHash: %w", err)
+	}
+	return b.EndCell(), nil
+}
+
+// ShardIdent - shard_ident$00 shard_pfx_bits:(#<= 60) workchain_id:int32 shard_prefix:uint64 = ShardIdent;
+type ShardIdent struct {
+	ShardPfxBits uint8
+	WorkchainId  int32
+	ShardPrefix  uint64
+}
+
+func (v *ShardIdent) LoadFromCell(loader *cell.Slice) error {
+	tag, err := loader.LoadUInt(2)
+	if err != nil {
+		return fmt.Errorf("failed to load tag: %w", err)
+	}
+	if tag != 0x0 {
+		return fmt.Errorf("incorrect tag %x for shard_ident, expected 0", tag)
+	}
+	val1, err := loader.LoadUInt(6)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of ShardPfxBits: %w", err)
+	}
+	v.ShardPfxBits = uint8(val1)
+	val2, err := loader.LoadInt(32)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of WorkchainId: %w", err)
+	}
+	v.WorkchainId = int32(val2)
+	val3, err := loader.LoadUInt(64)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of ShardPrefix: %w", err)
+	}
+	v.ShardPrefix = val3
+	return nil
+}
+
+func (v ShardIdent) ToCell() (*cell.Cell, error) {
+	b := cell.BeginCell()
+	if err := b.StoreUInt(0x0, 2); err != nil {
+		return nil, fmt.Errorf("failed to store tag: %w", err)
+	}
+	if err := b.StoreUInt(uint64(v.ShardPfxBits), 6); err != nil {
+		return nil, fmt.Errorf("failed to store integer of ShardPfxBits: %w", err)
+	}
+	if err := b.StoreInt(int64(v.WorkchainId), 32); err != nil {
+		return nil, fmt.Errorf("failed to store integer of WorkchainId: %w", err)
+	}
+	if err := b.StoreUInt(v.ShardPrefix, 64); err != nil {
+		return nil, fmt.Errorf("failed to store integer of ShardPrefix: %w", err)
+	}
+	return b.EndCell(), nil
+}
+
+// BlockInfo - block_info#9bc7a987 version:uint32 not_master:(## 1) after_merge:(## 1) before_split:(## 1) after_split:(## 1) want_split:Bool want_merge:Bool key_block:Bool vert_seqno_incr:(## 1) flags:(## 8) seq_no:# vert_seq_no:# shard:ShardIdent gen_utime:uint32 start_lt:uint64 end_lt:uint64 gen_validator_list_hash_short:uint32 gen_catchain_seqno:uint32 min_ref_mc_seqno:uint32 prev_key_block_seqno:uint32 gen_software:flags.0?GlobalVersion master_ref:not_master?^BlkMasterInfo prev_ref:^(BlkPrevInfo after_merge) prev_vert_ref:vert_seqno_incr?^(BlkPrevInfo 0) = BlockInfo;
+type BlockInfo struct {
+	Version                   uint32
+	NotMaster                 uint8
+	AfterMerge                uint8
+	BeforeSplit               uint8
+	AfterSplit                uint8
+	WantSplit                 bool
+	WantMerge                 bool
+	KeyBlock                  bool
+	VertSeqnoIncr             uint8
+	Flags                     uint8
+	SeqNo                     uint32
+	VertSeqNo                 uint32
+	Shard                     ShardIdent
+	GenUtime                  uint32
+	StartLt                   uint64
+	EndLt                     uint64
+	GenValidatorListHashShort uint32
+	GenCatchainSeqno          uint32
+	MinRefMcSeqno             uint32
+	PrevKeyBlockSeqno         uint32
+	GenSoftware               *GlobalVersion
+	MasterRef                 *BlkMasterInfo
+	PrevRef                   *cell.Cell
+	PrevVertRef               *cell.Cell
+}
+
+func (v *BlockInfo) LoadFromCell(loader *cell.Slice) error {
+	tag, err := loader.LoadUInt(32)
+	if err != nil {
+		return fmt.Errorf("failed to load tag: %w", err)
+	}
+	if tag != 0x9bc7a987 {
+		return fmt.Errorf("incorrect tag %x for block_info, expected 9bc7a987", tag)
+	}
+	val1, err := loader.LoadUInt(32)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of Version: %w", err)
+	}
+	v.Version = uint32(val1)
+	val2, err := loader.LoadUInt(1)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of NotMaster: %w", err)
+	}
+	v.NotMaster = uint8(val2)
+	val3, err := loader.LoadUInt(1)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of AfterMerge: %w", err)
+	}
+	v.AfterMerge = uint8(val3)
+	val4, err := loader.LoadUInt(1)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of BeforeSplit: %w", err)
+	}
+	v.BeforeSplit = uint8(val4)
+	val5, err := loader.LoadUInt(1)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of AfterSplit: %w", err)
+	}
+	v.AfterSplit = uint8(val5)
+	val6, err := loader.LoadBoolBit()
+	if err != nil {
+		return fmt.Errorf("failed to load bool of WantSplit: %w", err)
+	}
+	v.WantSplit = val6
+	val7, err := loader.LoadBoolBit()
+	if err != nil {
+		return fmt.Errorf("failed to load bool of WantMerge: %w", err)
+	}
+	v.WantMerge = val7
+	val8, err := loader.LoadBoolBit()
+	if err != nil {
+		return fmt.Errorf("failed to load bool of KeyBlock: %w", err)
+	}
+	v.KeyBlock = val8
+	val9, err := loader.LoadUInt(1)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of VertSeqnoIncr: %w", err)
+	}
+	v.VertSeqnoIncr = uint8(val9)
+	val10, err := loader.LoadUInt(8)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of Flags: %w", err)
+	}
+	v.Flags = uint8(val10)
+	val11, err := loader.LoadUInt(32)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of SeqNo: %w", err)
+	}
+	v.SeqNo = uint32(val11)
+	val12, err := loader.LoadUInt(32)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of VertSeqNo: %w", err)
+	}
+	v.VertSeqNo = uint32(val12)
+	if err := v.Shard.LoadFromCell(loader); err != nil {
+		return fmt.Errorf("failed to load Shard: %w", err)
+	}
+	val13, err := loader.LoadUInt(32)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of GenUtime: %w", err)
+	}
+	v.GenUtime = uint32(val13)
+	val14, err := loader.LoadUInt(64)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of StartLt: %w", err)
+	}
+	v.StartLt = val14
+	val15, err := loader.LoadUInt(64)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of EndLt: %w", err)
+	}
+	v.EndLt = val15
+	val16, err := loader.LoadUInt(32)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of GenValidatorListHashShort: %w", err)
+	}
+	v.GenValidatorListHashShort = uint32(val16)
+	val17, err := loader.LoadUInt(32)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of GenCatchainSeqno: %w", err)
+	}
+	v.GenCatchainSeqno = uint32(val17)
+	val18, err := loader.LoadUInt(32)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of MinRefMcSeqno: %w", err)
+	}
+	v.MinRefMcSeqno = uint32(val18)
+	val19, err := loader.LoadUInt(32)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of PrevKeyBlockSeqno: %w", err)
+	}
+	v.PrevKeyBlockSeqno = uint32(val19)
+	if (v.Flags>>0)&1 == 1 {
+		var val20 GlobalVersion
+		if err := val20.LoadFromCell(loader); err != nil {
+			return fmt.Errorf("failed to load GenSoftware: %w", err)
+		}
+		v.GenSoftware = &val20
+	}
+	if v.NotMaster != 0 {
+		var val21 BlkMasterInfo
+		ref22, err := loader.LoadRef()
+		if err != nil {
+			return fmt.Errorf("failed to load ref of MasterRef: %w", err)
+		}
+		if err := val21.LoadFromCell(ref22); err != nil {
+			return fmt.Errorf("failed to load MasterRef: %w", err)
+		}
+		v.MasterRef = &val21
+	}
+	val23, err := loader.LoadRefCell()
+	if err != nil {
+		return fmt.Errorf("failed to load ref of PrevRef: %w", err)
+	}
+	v.PrevRef = val23
+	if v.VertSeqnoIncr != 0 {
+		val24, err := loader.LoadRefCell()
+		if err != nil {
+			return fmt.Errorf("failed to load ref of PrevVertRef: %w", err)
+		}
+		v.PrevVertRef = val24
+	}
+	return nil
+}
+
+func (v BlockInfo) ToCell() (*cell.Cell, error) {
+	b := cell.BeginCell()
+	if err := b.StoreUInt(0x9bc7a987, 32); err != nil {
+		return nil, fmt.Errorf("failed to store tag: %w", err)
+	}
+	if err := b.StoreUInt(uint64(v.Version), 32); err != nil {
+		return nil, fmt.Errorf("failed to store integer of Version: %w", err)
+	}
+	if err := b.StoreUInt(uint64(v.NotMaster), 1); err != nil {
+		return nil, fmt.Errorf("failed to store integer of NotMaster: %w", err)
+	}
+	if err := b.StoreUInt(uint64(v.AfterMerge), 1); err != nil {
+		return nil, fmt.Errorf("failed to store integer of AfterMerge: %w", err)
+	}
+	if err := b.StoreUInt(uint64(v.BeforeSplit), 1); err != nil {
+		return nil, fmt.Errorf("failed to store integer of BeforeSplit: %w", err)
+	}
+	if err := b.StoreUInt(uint64(v.AfterSplit), 1); err != nil {
+		return nil, fmt.Errorf("failed to store integer of AfterSplit: %w", err)
+	}
+	if err := b.StoreBoolBit(v.WantSplit); err != nil {
+		return nil, fmt.Errorf("failed to store bool of WantSplit: %w", err)
+	}
+	if err := b.StoreBoolBit(v.WantMerge); err != nil {
+		return nil, fmt.Errorf("failed to store bool of WantMerge: %w", err)
+	}
+	if err := b.StoreBoolBit(v.KeyBlock); err != nil {
+		return nil, fmt.Errorf("failed to store bool of KeyBlock: %w", err)
+	}
+	if err := b.StoreUInt(uint64(v.VertSeqnoIncr), 1); err != nil {
+		return nil, fmt.Errorf("failed to store integer of VertSeqnoIncr: %w", err)
+	}
+	if err := b.StoreUInt(uint64(v.Flags), 8); err != nil {
+		return nil, fmt.Errorf("failed to store integer of Flags: %w", err)
+	}
+	if err := b.StoreUInt(uint64(v.SeqNo), 32); err != nil {
+		return nil, fmt.Errorf("failed to store integer of SeqNo: %w", err)
+	}
+	if err := b.StoreUInt(uint64(v.VertSeqNo), 32); err != nil {
+		return nil, fmt.Errorf("failed to store integer of VertSeqNo: %w", err)
+	}
+	c1, err := v.Shard.ToCell()
+	if err != nil {
+		return nil, fmt.Errorf("failed to store value of Shard: %w", err)
+	}
+	if err = b.StoreBuilder(c1.ToBuilder()); err != nil {
+		return nil, fmt.Errorf("failed to store value of Shard: %w", err)
+	}
+	if err := b.StoreUInt(uint64(v.GenUtime), 32); err != nil {
+		return nil, fmt.Errorf("failed to store integer of GenUtime: %w", err)
+	}
+	if err := b.StoreUInt(v.StartLt, 64); err != nil {
+		return nil, fmt.Errorf("failed to store integer of StartLt: %w", err)
+	}
+	if err := b.StoreUInt(v.EndLt, 64); err != nil {
+		return nil, fmt.Errorf("failed to store integer of EndLt: %w", err)
+	}
+	if err := b.StoreUInt(uint64(v.GenValidatorListHashShort), 32); err != nil {
+		return nil, fmt.Errorf("failed to store integer of GenValidatorListHashShort: %w", err)
+	}
+	if err := b.StoreUInt(uint64(v.GenCatchainSeqno), 32); err != nil {
+		return nil, fmt.Errorf("failed to store integer of GenCatchainSeqno: %w", err)
+	}
+	if err := b.StoreUInt(uint64(v.MinRefMcSeqno), 32); err != nil {
+		return nil, fmt.Errorf("failed to store integer of MinRefMcSeqno: %w", err)
+	}
+	if err := b.StoreUInt(uint64(v.PrevKeyBlockSeqno), 32); err != nil {
+		return nil, fmt.Errorf("failed to store integer of PrevKeyBlockSeqno: %w", err)
+	}
+	if (v.Flags>>0)&1 == 1 {
+		if v.GenSoftware == nil {
+			return nil, fmt.Errorf("field GenSoftware is required by condition")
+		}
+		c2, err := v.GenSoftware.ToCell()
+		if err != nil {
+			return nil, fmt.Errorf("failed to store value of GenSoftware: %w", err)
+		}
+		if err = b.StoreBuilder(c2.ToBuilder()); err != nil {
+			return nil, fmt.Errorf("failed to store value of GenSoftware: %w", err)
+		}
+	}
+	if v.NotMaster != 0 {
+		if v.MasterRef == nil {
+			return nil, fmt.Errorf("field MasterRef is required by condition")
+		}
+		ref3 := cell.BeginCell()
+		c4, err := v.MasterRef.ToCell()
+		if err != nil {
+			return nil, fmt.Errorf("failed to store value of MasterRef: %w", err)
+		}
+		if err = ref3.StoreBuilder(c4.ToBuilder()); err != nil {
+			return nil, fmt.Errorf("failed to store value of MasterRef: %w", err)
+		}
+		if err := b.StoreRef(ref3.EndCell()); err != nil {
+			return nil, fmt.Errorf("failed to store ref of MasterRef: %w", err)
+		}
+	}
+	if err := b.StoreRef(v.PrevRef); err != nil {
+		return nil, fmt.Errorf("failed to store ref of PrevRef: %w", err)
+	}
+	if v.VertSeqnoIncr != 0 {
+		if v.PrevVertRef == nil {
+			return nil, fmt.Errorf("field PrevVertRef is required by condition")
+		}
+		if err := b.StoreRef(v.PrevVertRef); err != nil {
+			return nil, fmt.Errorf("failed to store ref of PrevVertRef: %w", err)
+		}
+	}
+	return b.EndCell(), nil
+}
+
+// BlkMasterInfo - master_info$_ master:ExtBlkRef = BlkMasterInfo;
+type BlkMasterInfo struct {
+	Master ExtBlkRef
+}
+
+func (v *BlkMasterInfo) LoadFromCell(loader *cell.Slice) error {
+	if err := v.Master.LoadFromCell(loader); err != nil {
+		return fmt.Errorf("failed to load Master: %w", err)
+	}
+	return nil
+}
+
+func (v BlkMasterInfo) ToCell() (*cell.Cell, error) {
+	b := cell.BeginCell()
+	c1, err := v.Master.ToCell()
+	if err != nil {
+		return nil, fmt.Errorf("failed to store value of Master: %w", err)
+	}
+	if err = b.StoreBuilder(c1.ToBuilder()); err != nil {
+		return nil, fmt.Errorf("failed to store value of Master: %w", err)
+	}
+	return b.EndCell(), nil
+}
+
+// GlobalVersion - capabilities#c4 version:uint32 capabilities:uint64 = GlobalVersion;
+type GlobalVersion struct {
+	Version      uint32
+	Capabilities uint64
+}
+
+func (v *GlobalVersion) LoadFromCell(loader *cell.Slice) error {
+	tag, err := loader.LoadUInt(8)
+	if err != nil {
+		return fmt.Errorf("failed to load tag: %w", err)
+	}
+	if tag != 0xc4 {
+		return fmt.Errorf("incorrect tag %x for capabilities, expected c4", tag)
+	}
+	val1, err := loader.LoadUInt(32)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of Version: %w", err)
+	}
+	v.Version = uint32(val1)
+	val2, err := loader.LoadUInt(64)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of Capabilities: %w", err)
+	}
+	v.Capabilities = val2
+	return nil
+}
+
+func (v GlobalVersion) ToCell() (*cell.Cell, error) {
+	b := cell.BeginCell()
+	if err := b.StoreUInt(0xc4, 8); err != nil {
+		return nil, fmt.Errorf("failed to store tag: %w", err)
+	}
+	if err := b.StoreUInt(uint64(v.Version), 32); err != nil {
+		return nil, fmt.Errorf("failed to store integer of Version: %w", err)
+	}
+	if err := b.StoreUInt(v.Capabilities, 64); err != nil {
+		return nil, fmt.Errorf("failed to store integer of Capabilities: %w", err)
+	}
+	return b.EndCell(), nil
+}
@@ -0,0 +1,5 @@
+// Package gentest contains code generated by tlbgen from schema.tlb,
+// it is used to check that generated code compiles and is compatible with reflective tlb loader.
+package gentest
+
+//go:generate go run ../../cmd/tlbgen -in schema.tlb -out gen.go -pkg gentest
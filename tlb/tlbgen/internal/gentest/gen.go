@@ -0,0 +1,351 @@
+// Code generated by tlbgen. DO NOT EDIT.
+// Source: schema.tlb
+
+package gentest
+
+import (
+	"fmt"
+
+	"github.com/alan890104/tonutils-go/tlb"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+// Inner - inner$_ a:uint32 flag:Bool = Inner;
+type Inner struct {
+	A    uint32
+	Flag bool
+}
+
+func (v *Inner) LoadFromCell(loader *cell.Slice) error {
+	val1, err := loader.LoadUInt(32)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of A: %w", err)
+	}
+	v.A = uint32(val1)
+	val2, err := loader.LoadBoolBit()
+	if err != nil {
+		return fmt.Errorf("failed to load bool of Flag: %w", err)
+	}
+	v.Flag = val2
+	return nil
+}
+
+func (v Inner) ToCell() (*cell.Cell, error) {
+	b := cell.BeginCell()
+	if err := b.StoreUInt(uint64(v.A), 32); err != nil {
+		return nil, fmt.Errorf("failed to store integer of A: %w", err)
+	}
+	if err := b.StoreBoolBit(v.Flag); err != nil {
+		return nil, fmt.Errorf("failed to store bool of Flag: %w", err)
+	}
+	return b.EndCell(), nil
+}
+
+// Sample - sample#a1b2c3d4 opt:(Maybe uint16) none:(Maybe uint16) ref_opt:(Maybe ^Inner) inner:Inner d:(HashmapE 32 uint8) amount:Grams flags:(## 8) extra:flags.0?uint32 payload:(Either Cell ^Cell) = Sample;
+type Sample struct {
+	Opt     *uint16
+	None    *uint16
+	RefOpt  *Inner
+	Inner   Inner
+	D       *cell.Dictionary
+	Amount  tlb.Coins
+	Flags   uint8
+	Extra   *uint32
+	Payload *cell.Cell
+}
+
+func (v *Sample) LoadFromCell(loader *cell.Slice) error {
+	tag, err := loader.LoadUInt(32)
+	if err != nil {
+		return fmt.Errorf("failed to load tag: %w", err)
+	}
+	if tag != 0xa1b2c3d4 {
+		return fmt.Errorf("incorrect tag %x for sample, expected a1b2c3d4", tag)
+	}
+	has1, err := loader.LoadBoolBit()
+	if err != nil {
+		return fmt.Errorf("failed to load maybe bit of Opt: %w", err)
+	}
+	if has1 {
+		var val2 uint16
+		val3, err := loader.LoadUInt(16)
+		if err != nil {
+			return fmt.Errorf("failed to load integer of Opt: %w", err)
+		}
+		val2 = uint16(val3)
+		v.Opt = &val2
+	}
+	has4, err := loader.LoadBoolBit()
+	if err != nil {
+		return fmt.Errorf("failed to load maybe bit of None: %w", err)
+	}
+	if has4 {
+		var val5 uint16
+		val6, err := loader.LoadUInt(16)
+		if err != nil {
+			return fmt.Errorf("failed to load integer of None: %w", err)
+		}
+		val5 = uint16(val6)
+		v.None = &val5
+	}
+	has7, err := loader.LoadBoolBit()
+	if err != nil {
+		return fmt.Errorf("failed to load maybe bit of RefOpt: %w", err)
+	}
+	if has7 {
+		var val8 Inner
+		ref9, err := loader.LoadRef()
+		if err != nil {
+			return fmt.Errorf("failed to load ref of RefOpt: %w", err)
+		}
+		if err := val8.LoadFromCell(ref9); err != nil {
+			return fmt.Errorf("failed to load RefOpt: %w", err)
+		}
+		v.RefOpt = &val8
+	}
+	if err := v.Inner.LoadFromCell(loader); err != nil {
+		return fmt.Errorf("failed to load Inner: %w", err)
+	}
+	val10, err := loader.LoadDict(32)
+	if err != nil {
+		return fmt.Errorf("failed to load dict of D: %w", err)
+	}
+	v.D = val10
+	if err := v.Amount.LoadFromCell(loader); err != nil {
+		return fmt.Errorf("failed to load Amount: %w", err)
+	}
+	val11, err := loader.LoadUInt(8)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of Flags: %w", err)
+	}
+	v.Flags = uint8(val11)
+	if (v.Flags>>0)&1 == 1 {
+		var val12 uint32
+		val13, err := loader.LoadUInt(32)
+		if err != nil {
+			return fmt.Errorf("failed to load integer of Extra: %w", err)
+		}
+		val12 = uint32(val13)
+		v.Extra = &val12
+	}
+	isRight14, err := loader.LoadBoolBit()
+	if err != nil {
+		return fmt.Errorf("failed to load either bit of Payload: %w", err)
+	}
+	if isRight14 {
+		val15, err := loader.LoadRefCell()
+		if err != nil {
+			return fmt.Errorf("failed to load ref of Payload: %w", err)
+		}
+		v.Payload = val15
+	} else {
+		val16, err := loader.ToCell()
+		if err != nil {
+			return fmt.Errorf("failed to load cell of Payload: %w", err)
+		}
+		v.Payload = val16
+	}
+	return nil
+}
+
+func (v Sample) ToCell() (*cell.Cell, error) {
+	b := cell.BeginCell()
+	if err := b.StoreUInt(0xa1b2c3d4, 32); err != nil {
+		return nil, fmt.Errorf("failed to store tag: %w", err)
+	}
+	if v.Opt != nil {
+		if err := b.StoreBoolBit(true); err != nil {
+			return nil, fmt.Errorf("failed to store maybe bit of Opt: %w", err)
+		}
+		if err := b.StoreUInt(uint64(*v.Opt), 16); err != nil {
+			return nil, fmt.Errorf("failed to store integer of Opt: %w", err)
+		}
+	} else if err := b.StoreBoolBit(false); err != nil {
+		return nil, fmt.Errorf("failed to store maybe bit of Opt: %w", err)
+	}
+	if v.None != nil {
+		if err := b.StoreBoolBit(true); err != nil {
+			return nil, fmt.Errorf("failed to store maybe bit of None: %w", err)
+		}
+		if err := b.StoreUInt(uint64(*v.None), 16); err != nil {
+			return nil, fmt.Errorf("failed to store integer of None: %w", err)
+		}
+	} else if err := b.StoreBoolBit(false); err != nil {
+		return nil, fmt.Errorf("failed to store maybe bit of None: %w", err)
+	}
+	if v.RefOpt != nil {
+		if err := b.StoreBoolBit(true); err != nil {
+			return nil, fmt.Errorf("failed to store maybe bit of RefOpt: %w", err)
+		}
+		ref1 := cell.BeginCell()
+		c2, err := v.RefOpt.ToCell()
+		if err != nil {
+			return nil, fmt.Errorf("failed to store value of RefOpt: %w", err)
+		}
+		if err = ref1.StoreBuilder(c2.ToBuilder()); err != nil {
+			return nil, fmt.Errorf("failed to store value of RefOpt: %w", err)
+		}
+		if err := b.StoreRef(ref1.EndCell()); err != nil {
+			return nil, fmt.Errorf("failed to store ref of RefOpt: %w", err)
+		}
+	} else if err := b.StoreBoolBit(false); err != nil {
+		return nil, fmt.Errorf("failed to store maybe bit of RefOpt: %w", err)
+	}
+	c3, err := v.Inner.ToCell()
+	if err != nil {
+		return nil, fmt.Errorf("failed to store value of Inner: %w", err)
+	}
+	if err = b.StoreBuilder(c3.ToBuilder()); err != nil {
+		return nil, fmt.Errorf("failed to store value of Inner: %w", err)
+	}
+	if err := b.StoreDict(v.D); err != nil {
+		return nil, fmt.Errorf("failed to store dict of D: %w", err)
+	}
+	c4, err := v.Amount.ToCell()
+	if err != nil {
+		return nil, fmt.Errorf("failed to store value of Amount: %w", err)
+	}
+	if err = b.StoreBuilder(c4.ToBuilder()); err != nil {
+		return nil, fmt.Errorf("failed to store value of Amount: %w", err)
+	}
+	if err := b.StoreUInt(uint64(v.Flags), 8); err != nil {
+		return nil, fmt.Errorf("failed to store integer of Flags: %w", err)
+	}
+	if (v.Flags>>0)&1 == 1 {
+		if v.Extra == nil {
+			return nil, fmt.Errorf("field Extra is required by condition")
+		}
+		if err := b.StoreUInt(uint64(*v.Extra), 32); err != nil {
+			return nil, fmt.Errorf("failed to store integer of Extra: %w", err)
+		}
+	}
+	left5 := cell.BeginCell()
+	if v.Payload != nil {
+		if err := left5.StoreBuilder(v.Payload.ToBuilder()); err != nil {
+			return nil, fmt.Errorf("failed to store cell of Payload: %w", err)
+		}
+	}
+	if b.BitsLeft() > left5.BitsUsed() && b.RefsLeft() >= uint(left5.RefsUsed()) {
+		if err := b.StoreBoolBit(false); err != nil {
+			return nil, fmt.Errorf("failed to store either bit of Payload: %w", err)
+		}
+		if err := b.StoreBuilder(left5); err != nil {
+			return nil, fmt.Errorf("failed to store either value of Payload: %w", err)
+		}
+	} else {
+		right6 := cell.BeginCell()
+		if err := right6.StoreRef(v.Payload); err != nil {
+			return nil, fmt.Errorf("failed to store ref of Payload: %w", err)
+		}
+		if err := b.StoreBoolBit(true); err != nil {
+			return nil, fmt.Errorf("failed to store either bit of Payload: %w", err)
+		}
+		if err := b.StoreBuilder(right6); err != nil {
+			return nil, fmt.Errorf("failed to store either value of Payload: %w", err)
+		}
+	}
+	return b.EndCell(), nil
+}
+
+// OpA - op_a#01 x:uint8 = Op;
+type OpA struct {
+	X uint8
+}
+
+func (v *OpA) LoadFromCell(loader *cell.Slice) error {
+	tag, err := loader.LoadUInt(8)
+	if err != nil {
+		return fmt.Errorf("failed to load tag: %w", err)
+	}
+	if tag != 0x1 {
+		return fmt.Errorf("incorrect tag %x for op_a, expected 1", tag)
+	}
+	val1, err := loader.LoadUInt(8)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of X: %w", err)
+	}
+	v.X = uint8(val1)
+	return nil
+}
+
+func (v OpA) ToCell() (*cell.Cell, error) {
+	b := cell.BeginCell()
+	if err := b.StoreUInt(0x1, 8); err != nil {
+		return nil, fmt.Errorf("failed to store tag: %w", err)
+	}
+	if err := b.StoreUInt(uint64(v.X), 8); err != nil {
+		return nil, fmt.Errorf("failed to store integer of X: %w", err)
+	}
+	return b.EndCell(), nil
+}
+
+// OpB - op_b#02 y:uint16 = Op;
+type OpB struct {
+	Y uint16
+}
+
+func (v *OpB) LoadFromCell(loader *cell.Slice) error {
+	tag, err := loader.LoadUInt(8)
+	if err != nil {
+		return fmt.Errorf("failed to load tag: %w", err)
+	}
+	if tag != 0x2 {
+		return fmt.Errorf("incorrect tag %x for op_b, expected 2", tag)
+	}
+	val1, err := loader.LoadUInt(16)
+	if err != nil {
+		return fmt.Errorf("failed to load integer of Y: %w", err)
+	}
+	v.Y = uint16(val1)
+	return nil
+}
+
+func (v OpB) ToCell() (*cell.Cell, error) {
+	b := cell.BeginCell()
+	if err := b.StoreUInt(0x2, 8); err != nil {
+		return nil, fmt.Errorf("failed to store tag: %w", err)
+	}
+	if err := b.StoreUInt(uint64(v.Y), 16); err != nil {
+		return nil, fmt.Errorf("failed to store integer of Y: %w", err)
+	}
+	return b.EndCell(), nil
+}
+
+// Op - one of: OpA, OpB
+type Op struct {
+	Value any
+}
+
+func (v *Op) LoadFromCell(loader *cell.Slice) error {
+	if tag, err := loader.PreloadUInt(8); err == nil && tag == 0x1 {
+		var val OpA
+		if err := val.LoadFromCell(loader); err != nil {
+			return fmt.Errorf("failed to load OpA: %w", err)
+		}
+		v.Value = val
+		return nil
+	}
+	if tag, err := loader.PreloadUInt(8); err == nil && tag == 0x2 {
+		var val OpB
+		if err := val.LoadFromCell(loader); err != nil {
+			return fmt.Errorf("failed to load OpB: %w", err)
+		}
+		v.Value = val
+		return nil
+	}
+	return fmt.Errorf("unknown constructor of Op")
+}
+
+func (v Op) ToCell() (*cell.Cell, error) {
+	switch val := v.Value.(type) {
+	case OpA:
+		return val.ToCell()
+	case *OpA:
+		return val.ToCell()
+	case OpB:
+		return val.ToCell()
+	case *OpB:
+		return val.ToCell()
+	}
+	return nil, fmt.Errorf("unexpected type %T of Op value", v.Value)
+}
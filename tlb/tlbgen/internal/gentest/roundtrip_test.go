@@ -0,0 +1,186 @@
+package gentest
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/alan890104/tonutils-go/tlb"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+// reflective versions of generated types, both should produce the same cells
+
+type reflInner struct {
+	A    uint32 `tlb:"## 32"`
+	Flag bool   `tlb:"bool"`
+}
+
+type reflSample struct {
+	_      tlb.Magic        `tlb:"#a1b2c3d4"`
+	Opt    *uint16          `tlb:"maybe ## 16"`
+	None   *uint16          `tlb:"maybe ## 16"`
+	RefOpt *reflInner       `tlb:"maybe ^"`
+	Inner  reflInner        `tlb:"."`
+	D      *cell.Dictionary `tlb:"dict 32"`
+	Amount tlb.Coins        `tlb:"."`
+	// conditional field is not set in tests, reflective loader supports only bool conditions
+	Flags   uint8      `tlb:"## 8"`
+	Payload *cell.Cell `tlb:"either . ^"`
+}
+
+type reflOpA struct {
+	_ tlb.Magic `tlb:"#01"`
+	X uint8     `tlb:"## 8"`
+}
+
+type reflOpB struct {
+	_ tlb.Magic `tlb:"#02"`
+	Y uint16    `tlb:"## 16"`
+}
+
+type reflOp struct {
+	Value any `tlb:"[reflOpA,reflOpB]"`
+}
+
+func init() {
+	tlb.Register(reflOpA{})
+	tlb.Register(reflOpB{})
+}
+
+func testDict(t *testing.T) *cell.Dictionary {
+	d := cell.NewDict(32)
+	for i := int64(1); i <= 3; i++ {
+		if err := d.SetIntKey(big.NewInt(i), cell.BeginCell().MustStoreUInt(uint64(i*10), 8).EndCell()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return d
+}
+
+func checkSame(t *testing.T, name string, c1, c2 *cell.Cell) {
+	t.Helper()
+	if !bytes.Equal(c1.Hash(), c2.Hash()) {
+		t.Fatalf("%s: generated and reflective cells are different:\n%s\n%s", name, c1.Dump(), c2.Dump())
+	}
+}
+
+func TestSample_RoundTrip(t *testing.T) {
+	opt := uint16(777)
+	small := cell.BeginCell().MustStoreUInt(0xAB, 8).EndCell()
+	large := cell.BeginCell().MustStoreSlice(bytes.Repeat([]byte{0xCC}, 127), 1016).EndCell()
+
+	for name, v := range map[string]Sample{
+		"empty":      {Inner: Inner{A: 1}, Amount: tlb.MustFromTON("0")},
+		"all fields": {Opt: &opt, RefOpt: &Inner{A: 5, Flag: true}, Payload: small, Inner: Inner{A: 7}, D: testDict(t), Amount: tlb.MustFromTON("1.5"), Flags: 2},
+		"either ref": {Payload: large, Inner: Inner{A: 9, Flag: true}, Amount: tlb.MustFromNano(big.NewInt(100), 9)},
+	} {
+		gen, err := v.ToCell()
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		var refl reflSample
+		if err = tlb.LoadFromCell(&refl, gen.BeginParse()); err != nil {
+			t.Fatal(name, err)
+		}
+
+		reflCell, err := tlb.ToCell(refl)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		checkSame(t, name, gen, reflCell)
+
+		var loaded Sample
+		if err = loaded.LoadFromCell(reflCell.BeginParse()); err != nil {
+			t.Fatal(name, err)
+		}
+
+		if (loaded.Opt == nil) != (v.Opt == nil) || loaded.None != nil || (loaded.RefOpt == nil) != (v.RefOpt == nil) {
+			t.Fatal(name, "incorrect maybe fields")
+		}
+		if v.Payload != nil && !bytes.Equal(loaded.Payload.Hash(), v.Payload.Hash()) {
+			t.Fatal(name, "incorrect either field")
+		}
+		if v.D != nil && len(loaded.D.All()) != 3 {
+			t.Fatal(name, "incorrect dict field")
+		}
+
+		again, err := loaded.ToCell()
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		checkSame(t, name, gen, again)
+	}
+
+	wrong := cell.BeginCell().MustStoreUInt(0xa1b2c3d5, 32).EndCell()
+	var s Sample
+	if err := s.LoadFromCell(wrong.BeginParse()); err == nil {
+		t.Fatal("should be error for incorrect magic")
+	}
+}
+
+func TestSample_Condition(t *testing.T) {
+	extra := uint32(0xDEAD)
+	v := Sample{Flags: 1, Extra: &extra}
+
+	c, err := v.ToCell()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var loaded Sample
+	if err = loaded.LoadFromCell(c.BeginParse()); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Extra == nil || *loaded.Extra != extra {
+		t.Fatal("incorrect conditional field")
+	}
+
+	v.Extra = nil
+	if _, err = v.ToCell(); err == nil {
+		t.Fatal("should be error for missing conditional field")
+	}
+}
+
+func TestOp_RoundTrip(t *testing.T) {
+	for _, v := range []Op{{Value: OpA{X: 3}}, {Value: &OpB{Y: 500}}} {
+		gen, err := v.ToCell()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var refl reflOp
+		if err = tlb.LoadFromCell(&refl, gen.BeginParse()); err != nil {
+			t.Fatal(err)
+		}
+
+		reflCell, err := tlb.ToCell(refl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkSame(t, "op", gen, reflCell)
+
+		var loaded Op
+		if err = loaded.LoadFromCell(reflCell.BeginParse()); err != nil {
+			t.Fatal(err)
+		}
+		switch val := loaded.Value.(type) {
+		case OpA:
+			if val.X != 3 {
+				t.Fatal("incorrect op a")
+			}
+		case OpB:
+			if val.Y != 500 {
+				t.Fatal("incorrect op b")
+			}
+		default:
+			t.Fatalf("unexpected type %T", loaded.Value)
+		}
+	}
+
+	var op Op
+	if err := op.LoadFromCell(cell.BeginCell().MustStoreUInt(3, 8).EndCell().BeginParse()); err == nil {
+		t.Fatal("should be error for unknown constructor")
+	}
+}
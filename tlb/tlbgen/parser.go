@@ -0,0 +1,478 @@
+package tlbgen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Schema is a parsed set of TL-B declarations
+type Schema struct {
+	Constructors []*Constructor
+}
+
+// Constructor is a single TL-B declaration, like: `name#tag field:Type ... = TypeName;`
+type Constructor struct {
+	Name     string
+	TagBits  uint
+	TagValue uint64
+	Fields   []*Field
+	TypeName string
+
+	// Parameterized is set when result type takes arguments, like `= Hashmap n X`,
+	// such declarations are skipped by generator, builtins or raw cells are used instead.
+	// Implicit fields and constraints in braces, like { prev_seq_no:# }, do not make constructor parameterized.
+	Parameterized bool
+
+	// tag as it was written in schema, like #0f8a7ea5 or $10
+	tag string
+	// arguments of result type as they were written in schema
+	typeArgs []string
+}
+
+// Field of constructor, unnamed fields have empty name
+type Field struct {
+	Name string
+	Type *TypeExpr
+
+	// CondField is set for conditional fields: `name:flags.0?Type` or `name:has?Type`
+	CondField string
+	// CondBit is a bit number of CondField to check, -1 when CondField is Bool
+	CondBit int
+}
+
+// TypeExpr is a type of field or type argument
+type TypeExpr struct {
+	// Ref is set for ^X, inner type is in Args[0]
+	Ref bool
+	// Name of type or number when IsNum is set, for arithmetic it is an operator
+	Name  string
+	Args  []*TypeExpr
+	IsNum bool
+	Num   uint64
+	// Anon is set for anonymous cells: ^[ a:X b:Y ]
+	Anon []*Field
+}
+
+func (t *TypeExpr) String() string {
+	switch {
+	case t.Ref:
+		return "^" + t.Args[0].String()
+	case t.IsNum:
+		return strconv.FormatUint(t.Num, 10)
+	case t.Anon != nil:
+		var parts []string
+		for _, f := range t.Anon {
+			parts = append(parts, f.String())
+		}
+		return "[ " + strings.Join(parts, " ") + " ]"
+	case t.Name == "*" || t.Name == "+":
+		return "(" + t.Args[0].String() + " " + t.Name + " " + t.Args[1].String() + ")"
+	case len(t.Args) > 0:
+		parts := []string{t.Name}
+		for _, a := range t.Args {
+			parts = append(parts, a.String())
+		}
+		return "(" + strings.Join(parts, " ") + ")"
+	}
+	return t.Name
+}
+
+func (c *Constructor) String() string {
+	parts := []string{c.Name + c.tag}
+	for _, f := range c.Fields {
+		parts = append(parts, f.String())
+	}
+	parts = append(parts, "=", c.TypeName)
+	return strings.Join(append(parts, c.typeArgs...), " ") + ";"
+}
+
+func (f *Field) String() string {
+	s := f.Type.String()
+	if f.CondField != "" {
+		cond := f.CondField
+		if f.CondBit >= 0 {
+			cond += "." + strconv.Itoa(f.CondBit)
+		}
+		s = cond + "?" + s
+	}
+	if f.Name != "" {
+		s = f.Name + ":" + s
+	}
+	return s
+}
+
+// Parse parses TL-B schema text, comments are skipped
+func Parse(src string) (*Schema, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Schema{}
+	for len(tokens) > 0 {
+		end := -1
+		for i, t := range tokens {
+			if t.val == ";" {
+				end = i
+				break
+			}
+		}
+		if end == -1 {
+			return nil, fmt.Errorf("line %d: declaration is not terminated with ';'", tokens[0].line)
+		}
+
+		decl := tokens[:end]
+		tokens = tokens[end+1:]
+		if len(decl) == 0 {
+			continue
+		}
+
+		c, err := parseConstructor(decl)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", decl[0].line, err)
+		}
+		s.Constructors = append(s.Constructors, c)
+	}
+	return s, nil
+}
+
+type token struct {
+	val  string
+	line int
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end == -1 {
+				return nil, fmt.Errorf("line %d: comment is not closed", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case c == '#':
+			switch {
+			case strings.HasPrefix(src[i:], "##"):
+				tokens = append(tokens, token{"##", line})
+				i += 2
+			case strings.HasPrefix(src[i:], "#<="):
+				tokens = append(tokens, token{"#<=", line})
+				i += 3
+			case strings.HasPrefix(src[i:], "#<"):
+				tokens = append(tokens, token{"#<", line})
+				i += 2
+			default:
+				tokens = append(tokens, token{"#", line})
+				i++
+			}
+		case strings.ContainsRune("()[]{}^:;=?~*+.", rune(c)):
+			tokens = append(tokens, token{string(c), line})
+			i++
+		case c == '<' || c == '>' || c == '!':
+			if i+1 < len(src) && src[i+1] == '=' {
+				tokens = append(tokens, token{src[i : i+2], line})
+				i += 2
+			} else {
+				tokens = append(tokens, token{string(c), line})
+				i++
+			}
+		case isIdentChar(c):
+			start := i
+			for i < len(src) && isIdentChar(src[i]) {
+				i++
+			}
+			// constructor tag is written right after name: name#hex or name$bin
+			if i < len(src) && (src[i] == '#' || src[i] == '$') {
+				i++
+				for i < len(src) && isIdentChar(src[i]) {
+					i++
+				}
+			}
+			tokens = append(tokens, token{src[start:i], line})
+		default:
+			return nil, fmt.Errorf("line %d: unexpected character '%c'", line, c)
+		}
+	}
+	return tokens, nil
+}
+
+func parseConstructor(decl []token) (*Constructor, error) {
+	c := &Constructor{}
+
+	name := decl[0].val
+	if idx := strings.IndexAny(name, "#$"); idx >= 0 {
+		c.tag = name[idx:]
+		tag := name[idx+1:]
+		if tag != "_" && tag != "" {
+			var err error
+			if name[idx] == '#' {
+				c.TagBits = uint(len(tag) * 4)
+				c.TagValue, err = strconv.ParseUint(tag, 16, 64)
+			} else {
+				c.TagBits = uint(len(tag))
+				c.TagValue, err = strconv.ParseUint(tag, 2, 64)
+			}
+			if err != nil || c.TagBits > 64 {
+				return nil, fmt.Errorf("invalid tag '%s' of constructor '%s'", tag, name[:idx])
+			}
+		}
+		name = name[:idx]
+	}
+	c.Name = name
+
+	p := &parser{tokens: decl[1:]}
+	for {
+		t, ok := p.peek()
+		if !ok {
+			return nil, fmt.Errorf("type name is not specified for constructor '%s'", c.Name)
+		}
+
+		if t == "=" {
+			p.next()
+			break
+		}
+
+		if t == "{" {
+			// implicit fields and constraints are not serialized
+			if err := p.skipBraces(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		f, err := p.parseField()
+		if err != nil {
+			return nil, fmt.Errorf("constructor '%s': %w", c.Name, err)
+		}
+		c.Fields = append(c.Fields, f)
+	}
+
+	typeName, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("type name is not specified for constructor '%s'", c.Name)
+	}
+	c.TypeName = typeName
+	for {
+		arg, ok := p.next()
+		if !ok {
+			break
+		}
+		// result type has arguments
+		c.Parameterized = true
+		c.typeArgs = append(c.typeArgs, arg)
+	}
+	return c, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() (string, bool) {
+	if p.pos >= len(p.tokens) {
+		return "", false
+	}
+	return p.tokens[p.pos].val, true
+}
+
+func (p *parser) peekAt(offset int) string {
+	if p.pos+offset >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos+offset].val
+}
+
+func (p *parser) next() (string, bool) {
+	v, ok := p.peek()
+	if ok {
+		p.pos++
+	}
+	return v, ok
+}
+
+func (p *parser) expect(v string) error {
+	t, ok := p.next()
+	if !ok {
+		return fmt.Errorf("expected '%s', got end of declaration", v)
+	}
+	if t != v {
+		return fmt.Errorf("expected '%s', got '%s'", v, t)
+	}
+	return nil
+}
+
+// skipBraces skips {...} block of implicit field, parameter or constraint
+func (p *parser) skipBraces() error {
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+
+	for {
+		t, ok := p.next()
+		if !ok {
+			return fmt.Errorf("'{' is not closed")
+		}
+		if t == "}" {
+			return nil
+		}
+	}
+}
+
+func (p *parser) parseField() (*Field, error) {
+	f := &Field{CondBit: -1}
+
+	if p.peekAt(1) == ":" {
+		name, _ := p.next()
+		p.next()
+		if name != "_" {
+			f.Name = name
+		}
+	}
+
+	switch {
+	case p.peekAt(1) == "?":
+		f.CondField, _ = p.next()
+		p.next()
+	case p.peekAt(1) == "." && p.peekAt(3) == "?":
+		// flags.0?X, spaces around dot are allowed: flags . 0?X
+		f.CondField, _ = p.next()
+		p.next()
+		num, _ := p.next()
+		p.next()
+
+		bit, err := strconv.Atoi(num)
+		if err != nil || bit < 0 {
+			return nil, fmt.Errorf("invalid condition '%s.%s'", f.CondField, num)
+		}
+		f.CondBit = bit
+	}
+
+	typ, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	f.Type = typ
+	return f, nil
+}
+
+func (p *parser) parseTerm() (*TypeExpr, error) {
+	t, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("type expected, got end of declaration")
+	}
+
+	switch t {
+	case "^":
+		inner, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		return &TypeExpr{Ref: true, Args: []*TypeExpr{inner}}, nil
+	case "~":
+		// negative (output) nat params are used only in parameterized types
+		return p.parseTerm()
+	case "[":
+		anon := []*Field{}
+		for {
+			v, ok := p.peek()
+			if !ok {
+				return nil, fmt.Errorf("'[' is not closed")
+			}
+			if v == "]" {
+				p.next()
+				return &TypeExpr{Anon: anon}, nil
+			}
+			if v == "{" {
+				if err := p.skipBraces(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			f, err := p.parseField()
+			if err != nil {
+				return nil, err
+			}
+			anon = append(anon, f)
+		}
+	case "(":
+		head, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+
+		var args []*TypeExpr
+		for {
+			v, ok := p.peek()
+			if !ok {
+				return nil, fmt.Errorf("'(' is not closed")
+			}
+			if v == ")" {
+				p.next()
+				break
+			}
+
+			if v == "*" || v == "+" {
+				p.next()
+				right, err := p.parseTerm()
+				if err != nil {
+					return nil, err
+				}
+
+				if len(args) == 0 {
+					head = &TypeExpr{Name: v, Args: []*TypeExpr{head, right}}
+				} else {
+					args[len(args)-1] = &TypeExpr{Name: v, Args: []*TypeExpr{args[len(args)-1], right}}
+				}
+				continue
+			}
+
+			arg, err := p.parseTerm()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+		}
+
+		if len(args) == 0 {
+			return head, nil
+		}
+		if head.Ref || head.IsNum || head.Anon != nil || len(head.Args) > 0 {
+			return nil, fmt.Errorf("type '%s' cannot be applied to arguments", head.String())
+		}
+		head.Args = args
+		return head, nil
+	}
+
+	if t == "" || strings.ContainsAny(t[:1], ")]}:;=?*+.") {
+		return nil, fmt.Errorf("unexpected '%s'", t)
+	}
+
+	if t[0] >= '0' && t[0] <= '9' {
+		n, err := strconv.ParseUint(t, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number '%s'", t)
+		}
+		return &TypeExpr{IsNum: true, Num: n, Name: t}, nil
+	}
+	return &TypeExpr{Name: t}, nil
+}
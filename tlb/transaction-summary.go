@@ -0,0 +1,280 @@
+package tlb
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+type TransactionKind string
+
+const (
+	TransactionKindOrdinary     TransactionKind = "ORDINARY"
+	TransactionKindStorage      TransactionKind = "STORAGE"
+	TransactionKindTick         TransactionKind = "TICK"
+	TransactionKindTock         TransactionKind = "TOCK"
+	TransactionKindSplitPrepare TransactionKind = "SPLIT_PREPARE"
+	TransactionKindSplitInstall TransactionKind = "SPLIT_INSTALL"
+	TransactionKindMergePrepare TransactionKind = "MERGE_PREPARE"
+	TransactionKindMergeInstall TransactionKind = "MERGE_INSTALL"
+)
+
+type BounceOutcome string
+
+const (
+	BounceOutcomeNone     BounceOutcome = "NONE"
+	BounceOutcomeOk       BounceOutcome = "OK"
+	BounceOutcomeNegFunds BounceOutcome = "NEG_FUNDS"
+	BounceOutcomeNoFunds  BounceOutcome = "NO_FUNDS"
+)
+
+// TransactionSummary is a flattened view of transaction description and messages,
+// it is the same for all description types, phases which are not present are nil.
+type TransactionSummary struct {
+	Kind TransactionKind
+	// Success is true when transaction was not aborted, and compute and action phases (if present) succeeded
+	Success   bool
+	Aborted   bool
+	Destroyed bool
+
+	Storage *StoragePhase
+	Credit  *CreditPhase
+	Compute *ComputeSummary
+	Action  *ActionSummary
+
+	// Bounce is an outcome of bounce phase, BounceOutcomeNone when there was no bounce phase
+	Bounce BounceOutcome
+	// InBounced is true when incoming message is a bounced message, returned to us
+	InBounced bool
+
+	Fees FeesSummary
+
+	// ValueIn is an amount of incoming internal message
+	ValueIn Coins
+	// ValueOut is a sum of amounts of outgoing internal messages, including bounce message
+	ValueOut Coins
+	// ValueDelta is a change of account balance in nanoton (can be negative):
+	// ValueIn - total fees - (amount + forward and ihr fees) of each outgoing internal message.
+	// Extra currencies are not counted.
+	ValueDelta *big.Int
+}
+
+type ComputeSummary struct {
+	Skipped    bool
+	SkipReason ComputeSkipReasonType
+
+	Success  bool
+	ExitCode int32
+	ExitArg  *int32
+	GasUsed  *big.Int
+	GasLimit *big.Int
+	VMSteps  uint32
+}
+
+type ActionSummary struct {
+	Success         bool
+	Valid           bool
+	NoFunds         bool
+	ResultCode      int32
+	ResultArg       *int32
+	TotalActions    uint16
+	SkippedActions  uint16
+	MessagesCreated uint16
+}
+
+// FeesSummary is a breakdown of fees by phase
+type FeesSummary struct {
+	// Total is a total fees of transaction, as it is in transaction header
+	Total Coins
+	// Storage is storage fees collected in storage phase
+	Storage Coins
+	// StorageDue is fees which account has not paid due to lack of funds
+	StorageDue Coins
+	// Import is a fee for importing external message
+	Import Coins
+	// Compute is gas fees
+	Compute Coins
+	// Action is a part of forward fees taken by validators in action phase
+	Action Coins
+	// Forward is a total forward fees of out messages created in action phase
+	Forward Coins
+	// Bounce is fees for bounce message, both for validators and forwarding
+	Bounce Coins
+}
+
+// Summary analyzes transaction phases, fees and value flow
+func (t *Transaction) Summary() (*TransactionSummary, error) {
+	s := &TransactionSummary{
+		Bounce:     BounceOutcomeNone,
+		ValueIn:    ZeroCoins,
+		ValueOut:   ZeroCoins,
+		ValueDelta: new(big.Int),
+		Fees: FeesSummary{
+			Total:      t.TotalFees.Coins,
+			Storage:    ZeroCoins,
+			StorageDue: ZeroCoins,
+			Import:     ZeroCoins,
+			Compute:    ZeroCoins,
+			Action:     ZeroCoins,
+			Forward:    ZeroCoins,
+			Bounce:     ZeroCoins,
+		},
+	}
+
+	var compute *ComputePhase
+	var action *ActionPhase
+	var bounce *BouncePhase
+
+	desc := t.Description
+	if rv := reflect.ValueOf(desc); rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		desc = rv.Elem().Interface()
+	}
+
+	switch d := desc.(type) {
+	case TransactionDescriptionOrdinary:
+		s.Kind = TransactionKindOrdinary
+		s.Storage, s.Credit = d.StoragePhase, d.CreditPhase
+		compute, action, bounce = &d.ComputePhase, d.ActionPhase, d.BouncePhase
+		s.Aborted, s.Destroyed = d.Aborted, d.Destroyed
+	case TransactionDescriptionStorage:
+		s.Kind = TransactionKindStorage
+		s.Storage = &d.StoragePhase
+	case TransactionDescriptionTickTock:
+		s.Kind = TransactionKindTick
+		if d.IsTock {
+			s.Kind = TransactionKindTock
+		}
+		s.Storage = &d.StoragePhase
+		compute, action = &d.ComputePhase, d.ActionPhase
+		s.Aborted, s.Destroyed = d.Aborted, d.Destroyed
+	case TransactionDescriptionSplitPrepare:
+		s.Kind = TransactionKindSplitPrepare
+		s.Storage = d.StoragePhase
+		compute, action = &d.ComputePhase, d.ActionPhase
+		s.Aborted, s.Destroyed = d.Aborted, d.Destroyed
+	case TransactionDescriptionSplitInstall:
+		s.Kind = TransactionKindSplitInstall
+	case TransactionDescriptionMergePrepare:
+		s.Kind = TransactionKindMergePrepare
+		s.Storage = &d.StoragePhase
+		s.Aborted = d.Aborted
+	case TransactionDescriptionMergeInstall:
+		s.Kind = TransactionKindMergeInstall
+		s.Storage, s.Credit = d.StoragePhase, d.CreditPhase
+		compute, action = &d.ComputePhase, d.ActionPhase
+		s.Aborted, s.Destroyed = d.Aborted, d.Destroyed
+	default:
+		return nil, fmt.Errorf("unknown transaction description type %T", t.Description)
+	}
+
+	if s.Storage != nil {
+		s.Fees.Storage = s.Storage.StorageFeesCollected
+		if s.Storage.StorageFeesDue != nil {
+			s.Fees.StorageDue = *s.Storage.StorageFeesDue
+		}
+	}
+
+	if compute != nil {
+		cs := &ComputeSummary{}
+		phase := compute.Phase
+		if rv := reflect.ValueOf(phase); rv.Kind() == reflect.Pointer && !rv.IsNil() {
+			phase = rv.Elem().Interface()
+		}
+
+		switch p := phase.(type) {
+		case ComputePhaseVM:
+			cs.Success = p.Success
+			cs.ExitCode = p.Details.ExitCode
+			cs.ExitArg = p.Details.ExitArg
+			cs.GasUsed = p.Details.GasUsed
+			cs.GasLimit = p.Details.GasLimit
+			cs.VMSteps = p.Details.VMSteps
+			s.Fees.Compute = p.GasFees
+		case ComputePhaseSkipped:
+			cs.Skipped = true
+			cs.SkipReason = p.Reason.Type
+		default:
+			return nil, fmt.Errorf("unknown compute phase type %T", compute.Phase)
+		}
+		s.Compute = cs
+	}
+
+	if action != nil {
+		s.Action = &ActionSummary{
+			Success:         action.Success,
+			Valid:           action.Valid,
+			NoFunds:         action.NoFunds,
+			ResultCode:      action.ResultCode,
+			ResultArg:       action.ResultArg,
+			TotalActions:    action.TotalActions,
+			SkippedActions:  action.SkippedActions,
+			MessagesCreated: action.MessagesCreated,
+		}
+		if action.TotalActionFees != nil {
+			s.Fees.Action = *action.TotalActionFees
+		}
+		if action.TotalFwdFees != nil {
+			s.Fees.Forward = *action.TotalFwdFees
+		}
+	}
+
+	if bounce != nil {
+		phase := bounce.Phase
+		if rv := reflect.ValueOf(phase); rv.Kind() == reflect.Pointer && !rv.IsNil() {
+			phase = rv.Elem().Interface()
+		}
+
+		switch p := phase.(type) {
+		case BouncePhaseOk:
+			s.Bounce = BounceOutcomeOk
+			s.Fees.Bounce = MustFromNano(new(big.Int).Add(p.MsgFees.Nano(), p.FwdFees.Nano()), 9)
+		case BouncePhaseNegFunds:
+			s.Bounce = BounceOutcomeNegFunds
+		case BouncePhaseNoFunds:
+			s.Bounce = BounceOutcomeNoFunds
+		default:
+			return nil, fmt.Errorf("unknown bounce phase type %T", bounce.Phase)
+		}
+	}
+
+	s.Success = !s.Aborted &&
+		(s.Compute == nil || (!s.Compute.Skipped && s.Compute.Success)) &&
+		(s.Action == nil || s.Action.Success)
+
+	delta := new(big.Int).Neg(t.TotalFees.Coins.Nano())
+	if t.IO.In != nil {
+		switch t.IO.In.MsgType {
+		case MsgTypeInternal:
+			in := t.IO.In.AsInternal()
+			s.InBounced = in.Bounced
+			s.ValueIn = in.Amount
+			delta.Add(delta, in.Amount.Nano())
+		case MsgTypeExternalIn:
+			s.Fees.Import = t.IO.In.AsExternalIn().ImportFee
+		}
+	}
+
+	if t.IO.Out != nil {
+		list, err := t.IO.Out.ToSlice()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse out messages: %w", err)
+		}
+
+		out := new(big.Int)
+		for _, m := range list {
+			if m.MsgType != MsgTypeInternal {
+				continue
+			}
+
+			msg := m.AsInternal()
+			out.Add(out, msg.Amount.Nano())
+			delta.Sub(delta, msg.Amount.Nano())
+			delta.Sub(delta, msg.FwdFee.Nano())
+			delta.Sub(delta, msg.IHRFee.Nano())
+		}
+		s.ValueOut = MustFromNano(out, 9)
+	}
+	s.ValueDelta = delta
+
+	return s, nil
+}
@@ -0,0 +1,94 @@
+package tlb
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/alan890104/tonutils-go/address"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+func TestTransaction_Summary(t *testing.T) {
+	txData, _ := hex.DecodeString("b5ee9c724102060100013e0003af719dd9de25ac93578116413f89610061cf28f52daf1581373bd8671f7abddfd640000244d94d3f309d7cfcadc8e05ebbd460c2c420020d8e3bfd336da4b1c2d0b53f79127093409090000244d94d3f30164d081fd0001408020105008272d38ee1e2b7328b24e8e3836bb288aa9c96218b9a81e7a8fd290e1a4ccf0a65da9be924ff9d7f16b238a76ae47db9d2f56769c1b0c1ccb0fa95522820318401090101a00301ab680122f3d92b6fb36afc55adb8e4e8ef8e2101e4b488d540f31b1826eb15e121b92b000677677896b24d5e045904fe258401873ca3d4b6bc5604dcef619c7deaf77f590404061ed7e60000489b29a7e610c9a103fac00400687362d09c0000244d94d3f303601062ad47c00800731f1286645e6ced11b52e9a2c07cab0d6ea42390b5b969fd204a0e031294cd0001104084049a0187a12026ec7dc45")
+	txCell, err := cell.FromBOC(txData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tx Transaction
+	if err = LoadFromCell(&tx, txCell.BeginParse()); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := tx.Summary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Kind != TransactionKindOrdinary || s.Success || !s.Aborted || s.Action != nil ||
+		s.Compute == nil || !s.Compute.Skipped || s.Compute.SkipReason != ComputeSkipReasonNoGas ||
+		s.Bounce != BounceOutcomeNoFunds || s.ValueIn.Nano().Uint64() != 1 || s.ValueDelta.Int64() != 1 {
+		t.Fatalf("incorrect summary %+v", s)
+	}
+
+	addr := address.MustParseAddr("EQC9bWZd29foipyPOGWlVNVCQzpGAjvi1rGWF7EbNcSVClpA")
+	outMsg, err := ToCell(&InternalMessage{
+		SrcAddr: addr,
+		DstAddr: addr,
+		Amount:  MustFromTON("0.5"),
+		FwdFee:  MustFromNano(big.NewInt(2000), 9),
+		IHRFee:  ZeroCoins,
+		Body:    cell.BeginCell().EndCell(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outList := cell.NewDict(15)
+	if err = outList.SetIntKey(big.NewInt(0), cell.BeginCell().MustStoreRef(outMsg).EndCell()); err != nil {
+		t.Fatal(err)
+	}
+
+	fwd, action := MustFromNano(big.NewInt(3000), 9), MustFromNano(big.NewInt(1000), 9)
+	vm := ComputePhaseVM{Success: true, GasFees: MustFromNano(big.NewInt(5000), 9)}
+	vm.Details.ExitCode = 0
+	vm.Details.GasUsed = big.NewInt(500)
+
+	tx = Transaction{
+		TotalFees: CurrencyCollection{Coins: MustFromNano(big.NewInt(7000), 9)},
+		Description: TransactionDescriptionOrdinary{
+			StoragePhase: &StoragePhase{StorageFeesCollected: MustFromNano(big.NewInt(1000), 9)},
+			ComputePhase: ComputePhase{Phase: vm},
+			ActionPhase:  &ActionPhase{Success: true, Valid: true, TotalFwdFees: &fwd, TotalActionFees: &action, MessagesCreated: 1},
+		},
+	}
+	tx.IO.In = &Message{MsgType: MsgTypeInternal, Msg: &InternalMessage{SrcAddr: addr, DstAddr: addr, Amount: MustFromTON("1")}}
+	tx.IO.Out = &MessagesList{List: outList}
+
+	s, err = tx.Summary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !s.Success || s.Compute.Skipped || !s.Compute.Success || s.Compute.GasUsed.Uint64() != 500 ||
+		s.Action == nil || !s.Action.Success || s.Bounce != BounceOutcomeNone {
+		t.Fatalf("incorrect summary %+v", s)
+	}
+
+	if s.Fees.Compute.Nano().Uint64() != 5000 || s.Fees.Storage.Nano().Uint64() != 1000 ||
+		s.Fees.Forward.Nano().Uint64() != 3000 || s.Fees.Action.Nano().Uint64() != 1000 {
+		t.Fatalf("incorrect fees %+v", s.Fees)
+	}
+
+	// 1 TON in - 7000 fees - 0.5 TON out - 2000 fwd fee
+	if s.ValueOut.Nano().Uint64() != 500000000 || s.ValueDelta.Int64() != 500000000-9000 {
+		t.Fatal("incorrect value flow", s.ValueOut.String(), s.ValueDelta.String())
+	}
+
+	tx.Description = TransactionDescriptionTickTock{IsTock: true, ComputePhase: ComputePhase{Phase: ComputePhaseSkipped{}}}
+	tx.IO.In, tx.IO.Out = nil, nil
+	if s, err = tx.Summary(); err != nil || s.Kind != TransactionKindTock || s.Success {
+		t.Fatal("incorrect tick tock summary", err)
+	}
+}
@@ -54,27 +54,54 @@ type APIClientWrapped interface {
 	Client() LiteClient
 	GetTime(ctx context.Context) (uint32, error)
 	GetLibraries(ctx context.Context, list ...[]byte) ([]*cell.Cell, error)
+	GetLibrariesWithProof(ctx context.Context, block *BlockIDExt, hashes ...[]byte) ([]*cell.Cell, error)
 	LookupBlock(ctx context.Context, workchain int32, shard int64, seqno uint32) (*BlockIDExt, error)
+	LookupBlockByLT(ctx context.Context, workchain int32, shard int64, lt uint64) (*BlockIDExt, error)
+	LookupBlockByUtime(ctx context.Context, workchain int32, shard int64, utime uint32) (*BlockIDExt, error)
+	LookupBlockWithProof(ctx context.Context, master *BlockIDExt, workchain int32, shard int64, seqno uint32) (*BlockIDExt, error)
+	FindMasterBlockByUtime(ctx context.Context, utime uint32) (*BlockIDExt, error)
+	FindBlocksByUtime(ctx context.Context, utime uint32) (*BlockIDExt, []*BlockIDExt, error)
 	GetBlockData(ctx context.Context, block *BlockIDExt) (*tlb.Block, error)
 	GetBlockTransactionsV2(ctx context.Context, block *BlockIDExt, count uint32, after ...*TransactionID3) ([]TransactionShortInfo, bool, error)
+	GetBlockTransactionsExt(ctx context.Context, block *BlockIDExt, count uint32, after ...*TransactionID3) ([]*tlb.Transaction, bool, error)
 	GetBlockShardsInfo(ctx context.Context, master *BlockIDExt) ([]*BlockIDExt, error)
+	GetBlockHeader(ctx context.Context, block *BlockIDExt) (*tlb.BlockHeader, error)
+	GetShardInfo(ctx context.Context, master *BlockIDExt, workchain int32, shard int64, exact bool) (*BlockIDExt, error)
+	GetShardBlockProof(ctx context.Context, block *BlockIDExt) (*ShardBlockProof, error)
+	GetFullState(ctx context.Context, block *BlockIDExt) (*cell.Cell, error)
+	GetOutMsgQueueSizes(ctx context.Context) (*OutMsgQueueSizes, error)
+	GetShardOutMsgQueueSizes(ctx context.Context, workchain int32, shard int64) (*OutMsgQueueSizes, error)
+	GetBlockOutMsgQueueSize(ctx context.Context, block *BlockIDExt) (uint64, error)
+	GetDispatchQueueInfo(ctx context.Context, block *BlockIDExt, afterAddr []byte, maxAccounts int32) (*DispatchQueueInfo, error)
+	GetDispatchQueueMessages(ctx context.Context, block *BlockIDExt, addr *address.Address, afterLT uint64, maxMessages int32, oneAccount bool) (*DispatchQueueMessages, error)
+	GetValidatorStats(ctx context.Context, master *BlockIDExt, limit int32, startAfter []byte, modifiedAfter uint32) ([]*tlb.ValidatorCreateStats, bool, error)
+	GetNonfinalValidatorGroups(ctx context.Context, workchain *int32, shard *int64) ([]NonfinalValidatorGroupInfo, error)
+	GetNonfinalCandidate(ctx context.Context, id *NonfinalCandidateID) (*cell.Cell, error)
 	GetBlockchainConfig(ctx context.Context, block *BlockIDExt, onlyParams ...int32) (*BlockchainConfig, error)
 	GetMasterchainInfo(ctx context.Context) (*BlockIDExt, error)
+	GetMasterchainInfoExt(ctx context.Context) (*MasterchainInfoExt, error)
+	GetVersion(ctx context.Context) (*Version, error)
 	GetAccount(ctx context.Context, block *BlockIDExt, addr *address.Address) (*tlb.Account, error)
 	SendExternalMessage(ctx context.Context, msg *tlb.ExternalMessage) error
 	SendExternalMessageWaitTransaction(ctx context.Context, msg *tlb.ExternalMessage) (*tlb.Transaction, *BlockIDExt, []byte, error)
+	SendExternalMessageWaitConfirmation(ctx context.Context, msg *tlb.ExternalMessage, policy ConfirmationPolicy) (*ConfirmedTransaction, error)
 	RunGetMethod(ctx context.Context, blockInfo *BlockIDExt, addr *address.Address, method string, params ...interface{}) (*ExecutionResult, error)
+	RunGetMethodVerified(ctx context.Context, blockInfo *BlockIDExt, addr *address.Address, method string, params ...any) (*ExecutionResult, error)
+	SetTVMExecutor(executor TVMExecutor)
 	ListTransactions(ctx context.Context, addr *address.Address, num uint32, lt uint64, txHash []byte) ([]*tlb.Transaction, error)
+	ListTransactionsWithProof(ctx context.Context, master *BlockIDExt, addr *address.Address, num uint32, lt uint64, txHash []byte) ([]*tlb.Transaction, error)
 	GetTransaction(ctx context.Context, block *BlockIDExt, addr *address.Address, lt uint64) (*tlb.Transaction, error)
 	GetBlockProof(ctx context.Context, known, target *BlockIDExt) (*PartialBlockProof, error)
 	CurrentMasterchainInfo(ctx context.Context) (_ *BlockIDExt, err error)
 	SubscribeOnTransactions(workerCtx context.Context, addr *address.Address, lastProcessedLT uint64, channel chan<- *tlb.Transaction)
+	SubscribeOnBlocks(workerCtx context.Context, fromSeqno uint32, channel chan<- *BlockUpdate)
 	VerifyProofChain(ctx context.Context, from, to *BlockIDExt) error
 	WaitForBlock(seqno uint32) APIClientWrapped
 	WithRetry(maxRetries ...int) APIClientWrapped
 	WithTimeout(timeout time.Duration) APIClientWrapped
 	SetTrustedBlock(block *BlockIDExt)
 	SetTrustedBlockFromConfig(cfg *liteclient.GlobalConfig)
+	SetTrustStore(ctx context.Context, store TrustStore) error
 	FindLastTransactionByInMsgHash(ctx context.Context, addr *address.Address, msgHash []byte, maxTxNumToScan ...int) (*tlb.Transaction, error)
 	FindLastTransactionByOutMsgHash(ctx context.Context, addr *address.Address, msgHash []byte, maxTxNumToScan ...int) (*tlb.Transaction, error)
 }
@@ -84,11 +111,18 @@ type APIClient struct {
 	parent *APIClient
 
 	trustedBlock     *BlockIDExt
+	trustStore       TrustStore
+	trustStoreLock   sync.RWMutex
+	tvmExecutor      TVMExecutor
+	tvmExecutorLock  sync.RWMutex
 	curMasters       map[uint32]*masterInfo
 	curMastersLock   sync.RWMutex
 	proofCheckPolicy ProofCheckPolicy
 
 	trustedLock sync.RWMutex
+
+	verifiedBlocks map[string][]byte
+	verifiedLock   sync.RWMutex
 }
 
 type masterInfo struct {
@@ -121,8 +155,45 @@ func (c *APIClient) SetTrustedBlockFromConfig(cfg *liteclient.GlobalConfig) {
 	c.SetTrustedBlock(&b)
 }
 
+// SetTrustStore - set storage of verified key blocks. Latest checkpoint from it is used as trusted block,
+// if it is newer than the current one, and key blocks verified in proof chains are saved to it.
+// Store should contain checkpoints of the same network only.
+func (c *APIClient) SetTrustStore(ctx context.Context, store TrustStore) error {
+	cp, err := store.GetLatest(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get latest checkpoint: %w", err)
+	}
+
+	root := c.root()
+	root.trustedLock.Lock()
+	defer root.trustedLock.Unlock()
+
+	root.trustStoreLock.Lock()
+	root.trustStore = store
+	root.trustStoreLock.Unlock()
+
+	if cp != nil && (root.trustedBlock == nil || cp.Block.SeqNo > root.trustedBlock.SeqNo) {
+		root.trustedBlock = cp.Block.Copy()
+	}
+	return nil
+}
+
+// getTrustStore - returns trust store of root client, separate lock is used
+// because store is accessed during proof chain verification, when trusted lock is already taken
+func (c *APIClient) getTrustStore() TrustStore {
+	root := c.root()
+	root.trustStoreLock.RLock()
+	defer root.trustStoreLock.RUnlock()
+
+	return root.trustStore
+}
+
 // WaitForBlock - waits for the given master block seqno will be available on the requested node
 func (c *APIClient) WaitForBlock(seqno uint32) APIClientWrapped {
+	return c.waitForBlock(seqno)
+}
+
+func (c *APIClient) waitForBlock(seqno uint32) *APIClient {
 	return &APIClient{
 		parent:           c,
 		client:           &waiterClient{original: c.client, seqno: seqno},
@@ -3,6 +3,7 @@ package ton
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -72,14 +73,14 @@ type Version struct {
 }
 
 type GetState struct {
-	ID       *BlockIDExt `tl:"struct"`
-	RootHash []byte      `tl:"int256"`
-	FileHash []byte      `tl:"int256"`
-	Data     *cell.Cell  `tl:"cell"`
+	ID *BlockIDExt `tl:"struct"`
 }
 
 type BlockState struct {
-	ID *BlockIDExt `tl:"struct"`
+	ID       *BlockIDExt `tl:"struct"`
+	RootHash []byte      `tl:"int256"`
+	FileHash []byte      `tl:"int256"`
+	Data     []byte      `tl:"bytes"`
 }
 
 type GetShardBlockProof struct {
@@ -350,27 +351,8 @@ func (c *APIClient) GetMasterchainInfo(ctx context.Context) (*BlockIDExt, error)
 
 	switch t := resp.(type) {
 	case MasterchainInfo:
-		if c.proofCheckPolicy == ProofCheckPolicySecure {
-			root := c.root()
-			root.trustedLock.Lock()
-			defer root.trustedLock.Unlock()
-
-			if root.trustedBlock == nil {
-				if root.trustedBlock == nil {
-					// we have no block to trust, so trust first block we get
-					root.trustedBlock = t.Last.Copy()
-					log.Println("[WARNING] trusted block was not set on initialization, so first block we got was considered as trusted. " +
-						"For better security you should use SetTrustedBlock(block) method and pass there init block from config on start")
-				}
-			} else {
-				if err := c.VerifyProofChain(ctx, root.trustedBlock, t.Last); err != nil {
-					return nil, fmt.Errorf("failed to verify proof chain: %w", err)
-				}
-
-				if t.Last.SeqNo > root.trustedBlock.SeqNo {
-					root.trustedBlock = t.Last.Copy()
-				}
-			}
+		if err = c.checkLastMasterBlock(ctx, t.Last); err != nil {
+			return nil, err
 		}
 		return t.Last, nil
 	case LSError:
@@ -379,32 +361,42 @@ func (c *APIClient) GetMasterchainInfo(ctx context.Context) (*BlockIDExt, error)
 	return nil, errUnexpectedResponse(resp)
 }
 
-// LookupBlock - find block information by seqno, shard and chain
-func (c *APIClient) LookupBlock(ctx context.Context, workchain int32, shard int64, seqno uint32) (*BlockIDExt, error) {
-	var resp tl.Serializable
-	err := c.client.QueryLiteserver(ctx, LookupBlock{
-		Mode: 1,
-		ID: &BlockInfoShort{
-			Workchain: workchain,
-			Shard:     shard,
-			Seqno:     int32(seqno),
-		},
-	}, &resp)
-	if err != nil {
-		return nil, err
+// checkLastMasterBlock - in secure mode verifies proof chain from trusted block to the given one and moves trusted block forward
+func (c *APIClient) checkLastMasterBlock(ctx context.Context, last *BlockIDExt) error {
+	if c.proofCheckPolicy != ProofCheckPolicySecure {
+		return nil
 	}
 
-	switch t := resp.(type) {
-	case BlockHeader:
-		return t.ID, nil
-	case LSError:
-		// 651 = block not found code
-		if t.Code == 651 {
-			return nil, ErrBlockNotFound
-		}
-		return nil, t
+	root := c.root()
+	root.trustedLock.Lock()
+	defer root.trustedLock.Unlock()
+
+	if root.trustedBlock == nil {
+		// we have no block to trust, so trust first block we get
+		root.trustedBlock = last.Copy()
+		log.Println("[WARNING] trusted block was not set on initialization, so first block we got was considered as trusted. " +
+			"For better security you should use SetTrustedBlock(block) method and pass there init block from config on start")
+		return nil
 	}
-	return nil, errUnexpectedResponse(resp)
+
+	if err := c.VerifyProofChain(ctx, root.trustedBlock, last); err != nil {
+		return fmt.Errorf("failed to verify proof chain: %w", err)
+	}
+
+	if last.SeqNo > root.trustedBlock.SeqNo {
+		root.trustedBlock = last.Copy()
+	}
+	return nil
+}
+
+// LookupBlock - find block information by seqno, shard and chain
+func (c *APIClient) LookupBlock(ctx context.Context, workchain int32, shard int64, seqno uint32) (*BlockIDExt, error) {
+	id, _, err := c.lookupBlockHeader(ctx, lookupBySeqno, &BlockInfoShort{
+		Workchain: workchain,
+		Shard:     shard,
+		Seqno:     int32(seqno),
+	}, 0, 0)
+	return id, err
 }
 
 // GetBlockData - get block detailed information
@@ -426,6 +418,17 @@ func (c *APIClient) GetBlockData(ctx context.Context, block *BlockIDExt) (*tlb.B
 			return nil, fmt.Errorf("incorrect block")
 		}
 
+		if c.proofCheckPolicy != ProofCheckPolicyUnsafe {
+			fileHash := sha256.Sum256(t.Payload)
+			if !bytes.Equal(fileHash[:], block.FileHash) {
+				return nil, fmt.Errorf("incorrect block file hash")
+			}
+		}
+
+		if err = c.verifyBlockInMasterchain(ctx, block); err != nil {
+			return nil, fmt.Errorf("failed to verify block: %w", err)
+		}
+
 		var bData tlb.Block
 		if err = tlb.LoadFromCell(&bData, pl.BeginParse()); err != nil {
 			return nil, fmt.Errorf("failed to parse block data: %w", err)
@@ -480,6 +483,10 @@ func (c *APIClient) GetBlockTransactionsV2(ctx context.Context, block *BlockIDEx
 			if err = tlb.LoadFromCellAsProof(&shardAccounts, blockProof.Extra.ShardAccountBlocks.BeginParse()); err != nil {
 				return nil, false, fmt.Errorf("failed to load shard accounts from proof: %w", err)
 			}
+
+			if err = c.verifyBlockInMasterchain(ctx, block); err != nil {
+				return nil, false, fmt.Errorf("failed to verify block: %w", err)
+			}
 		}
 
 		txIds := make([]TransactionShortInfo, 0, len(t.TransactionIds))
@@ -507,6 +514,82 @@ func (c *APIClient) GetBlockTransactionsV2(ctx context.Context, block *BlockIDEx
 	return nil, false, errUnexpectedResponse(resp)
 }
 
+// GetBlockTransactionsExt - list of block transactions with full data, each transaction is verified with block proof
+func (c *APIClient) GetBlockTransactionsExt(ctx context.Context, block *BlockIDExt, count uint32, after ...*TransactionID3) ([]*tlb.Transaction, bool, error) {
+	withAfter := uint32(0)
+	var afterTx *TransactionID3
+	if len(after) > 0 && after[0] != nil {
+		afterTx = after[0]
+		withAfter = 1
+	}
+
+	mode := 0b111 | (withAfter << 7)
+	if c.proofCheckPolicy != ProofCheckPolicyUnsafe {
+		mode |= 1 << 5
+	}
+
+	var resp tl.Serializable
+	err := c.client.QueryLiteserver(ctx, ListBlockTransactionsExt{
+		Mode:      mode,
+		ID:        block,
+		Count:     count,
+		After:     afterTx,
+		WantProof: &True{},
+	}, &resp)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch t := resp.(type) {
+	case BlockTransactionsExt:
+		var shardAccounts tlb.ShardAccountBlocks
+
+		if c.proofCheckPolicy != ProofCheckPolicyUnsafe {
+			if len(t.Proof) == 0 {
+				return nil, false, fmt.Errorf("no proof passed by ls")
+			}
+
+			proof, err := cell.FromBOC(t.Proof)
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to parse proof boc: %w", err)
+			}
+
+			blockProof, err := CheckBlockProof(proof, block.RootHash)
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to check block proof: %w", err)
+			}
+
+			if err = tlb.LoadFromCellAsProof(&shardAccounts, blockProof.Extra.ShardAccountBlocks.BeginParse()); err != nil {
+				return nil, false, fmt.Errorf("failed to load shard accounts from proof: %w", err)
+			}
+
+			if err = c.verifyBlockInMasterchain(ctx, block); err != nil {
+				return nil, false, fmt.Errorf("failed to verify block: %w", err)
+			}
+		}
+
+		txList := make([]*tlb.Transaction, 0, len(t.Transactions))
+		for i, txCell := range t.Transactions {
+			var tx tlb.Transaction
+			if err = tlb.LoadFromCell(&tx, txCell.BeginParse()); err != nil {
+				return nil, false, fmt.Errorf("failed to parse transaction %d: %w", i, err)
+			}
+			tx.Hash = txCell.Hash()
+
+			if c.proofCheckPolicy != ProofCheckPolicyUnsafe {
+				if err = CheckTransactionProof(tx.Hash, tx.LT, tx.AccountAddr, &shardAccounts); err != nil {
+					return nil, false, fmt.Errorf("incorrect tx %s proof: %w", hex.EncodeToString(tx.Hash), err)
+				}
+			}
+			txList = append(txList, &tx)
+		}
+		return txList, t.Incomplete, nil
+	case LSError:
+		return nil, false, t
+	}
+	return nil, false, errUnexpectedResponse(resp)
+}
+
 // GetBlockShardsInfo - gets the information about workchains and its shards at given masterchain state
 func (c *APIClient) GetBlockShardsInfo(ctx context.Context, master *BlockIDExt) ([]*BlockIDExt, error) {
 	var resp tl.Serializable
@@ -528,6 +611,10 @@ func (c *APIClient) GetBlockShardsInfo(ctx context.Context, master *BlockIDExt)
 				return nil, fmt.Errorf("empty proof")
 			}
 
+			if err = c.verifyBlockInMasterchain(ctx, master); err != nil {
+				return nil, fmt.Errorf("failed to verify master block: %w", err)
+			}
+
 			switch len(t.Proof) {
 			case 1:
 				blockProof, err := CheckBlockProof(t.Proof[0], master.RootHash)
@@ -1,12 +1,79 @@
 package ton
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
 	"encoding/hex"
+	"math/big"
+	"math/bits"
 	"testing"
+	"time"
 
+	"github.com/alan890104/tonutils-go/address"
+	"github.com/alan890104/tonutils-go/tl"
+	"github.com/alan890104/tonutils-go/tlb"
 	"github.com/alan890104/tonutils-go/tvm/cell"
 )
 
+// testBlockID - block id for tests, hashes are filled with the given byte
+func testBlockID(wc int32, shard uint64, seqno uint32, fill byte) *BlockIDExt {
+	return &BlockIDExt{
+		Workchain: wc,
+		Shard:     int64(shard),
+		SeqNo:     seqno,
+		RootHash:  bytes.Repeat([]byte{fill}, 32),
+		FileHash:  bytes.Repeat([]byte{fill}, 32),
+	}
+}
+
+func testMasterBlockID(seqno uint32, fill byte) *BlockIDExt {
+	id := testBlockID(-1, 0, seqno, fill)
+	id.Shard = masterchainShard
+	return id
+}
+
+// testLiteClient - answers liteserver queries using handler, wait prefix of WaitForBlock is removed
+type testLiteClient struct {
+	handler func(req tl.Serializable) (tl.Serializable, error)
+}
+
+func (m *testLiteClient) QueryLiteserver(_ context.Context, payload tl.Serializable, result tl.Serializable) error {
+	if raw, ok := payload.(tl.Raw); ok {
+		var wait WaitMasterchainSeqno
+		rest, err := tl.Parse(&wait, raw, true)
+		if err != nil {
+			return err
+		}
+		if _, err = tl.Parse(&payload, rest, true); err != nil {
+			return err
+		}
+	}
+
+	resp, err := m.handler(payload)
+	if err != nil {
+		return err
+	}
+	*result.(*tl.Serializable) = resp
+	return nil
+}
+
+func (m *testLiteClient) StickyContext(ctx context.Context) context.Context {
+	return ctx
+}
+
+func (m *testLiteClient) StickyContextNextNode(ctx context.Context) (context.Context, error) {
+	return ctx, nil
+}
+
+func (m *testLiteClient) StickyContextNextNodeBalanced(ctx context.Context) (context.Context, error) {
+	return ctx, nil
+}
+
+func (m *testLiteClient) StickyNodeID(_ context.Context) uint32 {
+	return 0
+}
+
 func TestLoadShardsFromHashes(t *testing.T) {
 	data, err := hex.DecodeString("b5ee9c724102090100010b000103d040012201c002032201c00405284801012610bab489d8faa8c9dfaa65e8281895cfc66591881d1d5351574975ce386f2b00032201c00607284801013ca47d35fc14db1a5f2e33f74cf3e833974de0fea9f49759ea84d2522124d12b000201eb50134ea4181081ebe000013951e6cc660000013951e6cc6608c7a91c9653b122d1e49487ecc663e5bb59d8974d6ddd03a4c5cdd7c325498e92b103dc863224263143d3b59124e2a4bce36ddd4ce7f4c43ae0476430da34061280003e18b880000000000000001080fd6b2b80b3cecae02d12000000c90828480101c2256a5539b179d8831bcbfb692dc691ba4c604a72a60ff375306e2b29764a4900010013407735940203b9aca0202872d22f")
 	if err != nil {
@@ -36,3 +103,265 @@ func TestLoadShardsFromHashes(t *testing.T) {
 		t.Fatal("should be err")
 	}
 }
+
+func TestVersion_HasCapability(t *testing.T) {
+	v := Version{Capabilities: 7}
+	if !v.HasCapability(LSCapabilityProofChains | LSCapabilityRunSmcMethod) {
+		t.Fatal("should have capabilities")
+	}
+
+	v.Capabilities = 1
+	if v.HasCapability(LSCapabilityMasterchainInfoExt) {
+		t.Fatal("should not have masterchain info ext")
+	}
+}
+
+func TestCheckShardBlockProof(t *testing.T) {
+	target := testBlockID(0, 0x8000000000000000, 5, 0)
+
+	if err := CheckShardBlockProof(&ShardBlockProof{MasterchainID: target}, target); err == nil {
+		t.Fatal("proof from not master block should fail")
+	}
+
+	master := testMasterBlockID(10, 0)
+	if err := CheckShardBlockProof(&ShardBlockProof{MasterchainID: master}, target); err == nil {
+		t.Fatal("empty proof should fail for shard block")
+	}
+
+	if err := CheckShardBlockProof(&ShardBlockProof{MasterchainID: master}, master); err != nil {
+		t.Fatal("empty proof should be valid for master block itself:", err)
+	}
+
+	if err := CheckShardBlockProof(&ShardBlockProof{MasterchainID: master, Links: []ShardBlockLink{{ID: target, Proof: []byte{1, 2, 3}}}}, target); err == nil {
+		t.Fatal("invalid link proof should fail")
+	}
+}
+
+// testBlockInfoCell - minimal block_info of not key block, masterRef is required for shard blocks
+func testBlockInfoCell(t *testing.T, id *BlockIDExt, afterMerge bool, prev ...*BlockIDExt) *cell.Cell {
+	extRef := func(b *BlockIDExt) *cell.Cell {
+		return cell.BeginCell().MustStoreUInt(0, 64).MustStoreUInt(uint64(b.SeqNo), 32).
+			MustStoreSlice(b.RootHash, 256).MustStoreSlice(b.FileHash, 256).EndCell()
+	}
+
+	notMaster := id.Workchain != address.MasterchainID
+	pfxBits := uint64(0)
+	if shard := uint64(id.Shard); shard<<1 != 0 {
+		pfxBits = uint64(63 - bits.TrailingZeros64(shard))
+	}
+
+	b := cell.BeginCell().MustStoreUInt(0x9bc7a987, 32).MustStoreUInt(0, 32).
+		MustStoreBoolBit(notMaster).MustStoreBoolBit(afterMerge).
+		MustStoreUInt(0, 6). // before_split, after_split, want_split, want_merge, key_block, vert_seqno_incr
+		MustStoreUInt(0, 8).MustStoreUInt(uint64(id.SeqNo), 32).MustStoreUInt(0, 32).
+		MustStoreUInt(0, 2).MustStoreUInt(pfxBits, 6).MustStoreInt(int64(id.Workchain), 32).
+		MustStoreUInt(uint64(id.Shard)&^(uint64(1)<<(63-pfxBits)), 64).
+		MustStoreSlice(make([]byte, 36), 32+64+64+32+32+32+32)
+
+	if notMaster {
+		b.MustStoreRef(extRef(testMasterBlockID(1, 0)))
+	}
+
+	if afterMerge {
+		if len(prev) != 2 {
+			t.Fatal("merge block should have 2 parents")
+		}
+		b.MustStoreRef(cell.BeginCell().MustStoreRef(extRef(prev[0])).MustStoreRef(extRef(prev[1])).EndCell())
+	} else {
+		b.MustStoreRef(extRef(prev[0]))
+	}
+	return b.EndCell()
+}
+
+// testBlockCell - block with the given info and extra, other parts are empty
+func testBlockCell(info, extra *cell.Cell) *cell.Cell {
+	return cell.BeginCell().MustStoreUInt(0x11ef55aa, 32).MustStoreInt(-239, 32).
+		MustStoreRef(info).MustStoreRef(cell.BeginCell().EndCell()).
+		MustStoreRef(cell.BeginCell().EndCell()).MustStoreRef(extra).EndCell()
+}
+
+// testBlockProof - merkle proof of block with the given refs included, others are pruned
+func testBlockProof(t *testing.T, block *cell.Cell, refs ...int) []byte {
+	sk := cell.CreateProofSkeleton()
+	for _, ref := range refs {
+		sk.ProofRef(ref).SetRecursive()
+	}
+	proof, err := block.CreateProof(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proof.ToBOC()
+}
+
+// testMasterWithShard - master block which has the given shard block as top block of workchain 0
+func testMasterWithShard(t *testing.T, seqno uint32, shard *BlockIDExt) (*BlockIDExt, []byte) {
+	desc, err := tlb.ToCell(tlb.ShardDesc{
+		SeqNo:              shard.SeqNo,
+		RootHash:           shard.RootHash,
+		FileHash:           shard.FileHash,
+		NextValidatorShard: shard.Shard,
+		SplitMergeAt:       tlb.FutureSplitMergeNone{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hashes := cell.NewDict(32)
+	leaf := cell.BeginCell().MustStoreUInt(0, 1).MustStoreBuilder(desc.ToBuilder()).EndCell()
+	if err = hashes.SetIntKey(big.NewInt(int64(shard.Workchain)), cell.BeginCell().MustStoreRef(leaf).EndCell()); err != nil {
+		t.Fatal(err)
+	}
+
+	extra, err := tlb.ToCell(&tlb.BlockExtra{
+		InMsgDesc:          cell.BeginCell().EndCell(),
+		OutMsgDesc:         cell.BeginCell().EndCell(),
+		ShardAccountBlocks: cell.BeginCell().EndCell(),
+		RandSeed:           make([]byte, 32),
+		CreatedBy:          make([]byte, 32),
+		Custom:             &tlb.McBlockExtra{ShardHashes: hashes},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := testMasterBlockID(seqno, 0)
+	block := testBlockCell(testBlockInfoCell(t, id, false, testMasterBlockID(seqno-1, 0)), extra)
+	id.RootHash = block.Hash()
+	return id, testBlockProof(t, block, 0, 3)
+}
+
+func TestCheckShardBlockProof_AfterMerge(t *testing.T) {
+	left := testBlockID(0, 0x4000000000000000, 19, 1)
+	right := testBlockID(0, 0xC000000000000000, 18, 2)
+
+	merged := testBlockID(0, 0x8000000000000000, 20, 0)
+	// extra is pruned in proof, only cells with refs are pruned
+	extra := cell.BeginCell().MustStoreRef(cell.BeginCell().EndCell()).EndCell()
+	mergedBlock := testBlockCell(testBlockInfoCell(t, merged, true, left, right), extra)
+	merged.RootHash = mergedBlock.Hash()
+	mergedProof := testBlockProof(t, mergedBlock, 0)
+
+	master, masterProof := testMasterWithShard(t, 10, merged)
+
+	for _, parent := range []*BlockIDExt{left, right} {
+		proof := &ShardBlockProof{MasterchainID: master, Links: []ShardBlockLink{
+			{ID: merged, Proof: masterProof},
+			{ID: parent, Proof: mergedProof},
+		}}
+		if err := CheckShardBlockProof(proof, parent); err != nil {
+			t.Fatal("both parents of merged block should be accepted:", err)
+		}
+	}
+
+	other := testBlockID(0, 0xC000000000000000, 18, 3)
+	proof := &ShardBlockProof{MasterchainID: master, Links: []ShardBlockLink{
+		{ID: merged, Proof: masterProof},
+		{ID: other, Proof: mergedProof},
+	}}
+	if err := CheckShardBlockProof(proof, other); err == nil {
+		t.Fatal("not a parent should fail")
+	}
+}
+
+func TestAPIClient_VerifiedBlocksCache(t *testing.T) {
+	c := NewAPIClient(nil, ProofCheckPolicySecure)
+	w := c.WithTimeout(time.Second).(*APIClient)
+
+	blk := testBlockID(0, 0x8000000000000000, 1, 0)
+	if c.isBlockVerified(blk) {
+		t.Fatal("block should not be verified")
+	}
+
+	w.setBlockVerified(blk)
+	if !c.isBlockVerified(blk) {
+		t.Fatal("block should be verified in root")
+	}
+
+	// already verified block should not be requested again
+	if err := w.verifyBlockInMasterchain(context.Background(), blk); err != nil {
+		t.Fatal(err)
+	}
+
+	other := blk.Copy()
+	other.FileHash = bytes.Repeat([]byte{1}, 32)
+	if c.isBlockVerified(other) {
+		t.Fatal("block with other file hash should not be verified")
+	}
+
+	for i := 0; i < verifiedBlocksCacheSize; i++ {
+		b := blk.Copy()
+		b.RootHash = make([]byte, 32)
+		binary.BigEndian.PutUint32(b.RootHash[28:], uint32(i+1))
+		c.setBlockVerified(b)
+	}
+	if c.isBlockVerified(blk) || len(c.verifiedBlocks) > verifiedBlocksCacheSize {
+		t.Fatal("cache should be reset on overflow")
+	}
+
+	if err := NewAPIClient(nil, ProofCheckPolicyFast).verifyBlockInMasterchain(context.Background(), other); err != nil {
+		t.Fatal("should not verify in fast mode", err)
+	}
+}
+
+func TestAPIClient_VerifyMasterBlock_MovesTrusted(t *testing.T) {
+	ctx := context.Background()
+	c := NewAPIClient(nil, ProofCheckPolicySecure)
+	c.SetTrustedBlock(testMasterBlockID(10, 1))
+
+	// proof chain to the block is already verified in store, so no requests are needed
+	store := NewMemoryTrustStore()
+	cp := testCheckpoint(100, 5)
+	if err := store.Save(ctx, cp); err != nil {
+		t.Fatal(err)
+	}
+	c.trustStore = store
+
+	if err := c.WithTimeout(time.Second).(*APIClient).verifyBlockInMasterchain(ctx, cp.Block); err != nil {
+		t.Fatal(err)
+	}
+
+	if !c.trustedBlock.Equals(cp.Block) {
+		t.Fatal("trusted block should be moved to verified master block")
+	}
+}
+
+func TestAPIClient_CollectNewShardBlocks(t *testing.T) {
+	c := NewAPIClient(nil)
+	ctx := context.Background()
+
+	id := func(wc int32, shard uint64, seqno uint32, fill byte) *BlockIDExt {
+		return &BlockIDExt{Workchain: wc, Shard: int64(shard), SeqNo: seqno, RootHash: bytes.Repeat([]byte{fill}, 32), FileHash: make([]byte, 32)}
+	}
+
+	last := []*BlockIDExt{
+		id(0, 0x4000000000000000, 100, 1),
+		id(0, 0xc000000000000000, 50, 2),
+	}
+
+	// already known block, no requests should be done
+	res, err := c.collectNewShardBlocks(ctx, id(0, 0x4000000000000000, 100, 1), last, map[string]bool{})
+	if err != nil || len(res) != 0 {
+		t.Fatal("known block should be skipped", err)
+	}
+
+	// parent shard block before split is known by its children
+	res, err = c.collectNewShardBlocks(ctx, id(0, 0x8000000000000000, 49, 3), last, map[string]bool{})
+	if err != nil || len(res) != 0 {
+		t.Fatal("ancestor block should be skipped", err)
+	}
+
+	// no related shards known, block is taken as is
+	res, err = c.collectNewShardBlocks(ctx, id(1, 0x8000000000000000, 7, 4), last, map[string]bool{})
+	if err != nil || len(res) != 1 || res[0].SeqNo != 7 {
+		t.Fatal("block of new workchain should be returned", err)
+	}
+
+	// visited block should not be returned twice
+	visited := map[string]bool{}
+	if res, _ = c.collectNewShardBlocks(ctx, id(1, 0x8000000000000000, 7, 4), last, visited); len(res) != 1 {
+		t.Fatal("block should be returned")
+	}
+	if res, _ = c.collectNewShardBlocks(ctx, id(1, 0x8000000000000000, 7, 4), last, visited); len(res) != 0 {
+		t.Fatal("visited block should be skipped")
+	}
+}
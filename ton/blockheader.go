@@ -0,0 +1,196 @@
+package ton
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/alan890104/tonutils-go/tl"
+	"github.com/alan890104/tonutils-go/tlb"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+const (
+	blockHeaderWithStateUpdate = 1 << 0
+	blockHeaderWithExtra       = 1 << 4
+)
+
+// GetBlockHeader - gets block header, it is verified with merkle proof of the block
+func (c *APIClient) GetBlockHeader(ctx context.Context, block *BlockIDExt) (*tlb.BlockHeader, error) {
+	blk, err := c.getBlockHeaderProof(ctx, block, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &blk.BlockInfo, nil
+}
+
+func (c *APIClient) getBlockHeaderProof(ctx context.Context, block *BlockIDExt, mode uint32) (*tlb.Block, error) {
+	var resp tl.Serializable
+	err := c.client.QueryLiteserver(ctx, GetBlockHeader{ID: block, Mode: mode}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := resp.(type) {
+	case BlockHeader:
+		if !t.ID.Equals(block) {
+			return nil, fmt.Errorf("incorrect block in response")
+		}
+
+		proof, err := cell.FromBOC(t.HeaderProof)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse header proof boc: %w", err)
+		}
+
+		blk, err := CheckBlockProof(proof, block.RootHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check header proof: %w", err)
+		}
+		return blk, nil
+	case LSError:
+		return nil, t
+	}
+	return nil, errUnexpectedResponse(resp)
+}
+
+// GetShardInfo - gets shard block which is committed in the given master block.
+// When exact is false, shard which contains the requested one is returned, for example when shard was merged.
+func (c *APIClient) GetShardInfo(ctx context.Context, master *BlockIDExt, workchain int32, shard int64, exact bool) (*BlockIDExt, error) {
+	var resp tl.Serializable
+	err := c.client.QueryLiteserver(ctx, GetShardInfo{
+		ID:        master,
+		Workchain: workchain,
+		Shard:     shard,
+		Exact:     exact,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := resp.(type) {
+	case ShardInfo:
+		if t.ShardBlock == nil || t.ShardBlock.Workchain != workchain {
+			return nil, fmt.Errorf("incorrect shard block in response")
+		}
+
+		if exact && t.ShardBlock.Shard != shard {
+			return nil, fmt.Errorf("shard not matches requested")
+		}
+
+		if !exact && !tlb.ShardID(t.ShardBlock.Shard).IsAncestor(tlb.ShardID(shard)) {
+			return nil, fmt.Errorf("shard in response not contains requested")
+		}
+
+		if c.proofCheckPolicy != ProofCheckPolicyUnsafe {
+			if !t.ID.Equals(master) {
+				return nil, fmt.Errorf("incorrect master block in response")
+			}
+
+			stateExtra, err := CheckShardMcStateExtraProof(master, t.ShardProof)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check shard proof: %w", err)
+			}
+
+			shards, err := LoadShardsFromHashes(stateExtra.ShardHashes, true)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load shard hashes from proof: %w", err)
+			}
+
+			found := false
+			for _, s := range shards {
+				if s.Equals(t.ShardBlock) {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				return nil, fmt.Errorf("shard block not found in proof")
+			}
+		}
+		return t.ShardBlock, nil
+	case LSError:
+		return nil, t
+	}
+	return nil, errUnexpectedResponse(resp)
+}
+
+// GetShardBlockProof - gets proof that shard block is a part of masterchain,
+// proof links are verified from master block to the requested one.
+func (c *APIClient) GetShardBlockProof(ctx context.Context, block *BlockIDExt) (*ShardBlockProof, error) {
+	var resp tl.Serializable
+	err := c.client.QueryLiteserver(ctx, GetShardBlockProof{ID: block}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := resp.(type) {
+	case ShardBlockProof:
+		if c.proofCheckPolicy != ProofCheckPolicyUnsafe {
+			if err = CheckShardBlockProof(&t, block); err != nil {
+				return nil, fmt.Errorf("failed to check shard block proof: %w", err)
+			}
+		}
+		return &t, nil
+	case LSError:
+		return nil, t
+	}
+	return nil, errUnexpectedResponse(resp)
+}
+
+// GetFullState - downloads full state of the block, root cell can be parsed as tlb.ShardStateUnsplit.
+// Liteservers allow it only for small states, like zerostate.
+func (c *APIClient) GetFullState(ctx context.Context, block *BlockIDExt) (*cell.Cell, error) {
+	var resp tl.Serializable
+	err := c.client.QueryLiteserver(ctx, GetState{ID: block}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := resp.(type) {
+	case BlockState:
+		if !t.ID.Equals(block) {
+			return nil, fmt.Errorf("incorrect block in response")
+		}
+
+		fileHash := sha256.Sum256(t.Data)
+		if !bytes.Equal(fileHash[:], t.FileHash) {
+			return nil, fmt.Errorf("incorrect state file hash")
+		}
+
+		state, err := cell.FromBOC(t.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse state boc: %w", err)
+		}
+
+		if !bytes.Equal(state.Hash(), t.RootHash) {
+			return nil, fmt.Errorf("incorrect state root hash")
+		}
+
+		if block.SeqNo == 0 {
+			// zerostate has no block, its id is the state itself
+			if !bytes.Equal(block.RootHash, t.RootHash) || !bytes.Equal(block.FileHash, t.FileHash) {
+				return nil, fmt.Errorf("state not matches zerostate id")
+			}
+		} else if c.proofCheckPolicy != ProofCheckPolicyUnsafe {
+			blk, err := c.getBlockHeaderProof(ctx, block, blockHeaderWithStateUpdate)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get block header: %w", err)
+			}
+
+			upd, err := blk.StateUpdate.PeekRef(1)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load state update ref: %w", err)
+			}
+
+			if !bytes.Equal(upd.Hash(0), t.RootHash) {
+				return nil, fmt.Errorf("state not matches block state hash")
+			}
+		}
+		return state, nil
+	case LSError:
+		return nil, t
+	}
+	return nil, errUnexpectedResponse(resp)
+}
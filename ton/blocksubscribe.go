@@ -0,0 +1,167 @@
+package ton
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alan890104/tonutils-go/address"
+	"github.com/alan890104/tonutils-go/tlb"
+)
+
+const masterchainShard = int64(-0x8000000000000000)
+
+// BlockUpdate - master block with shard blocks which were committed in masterchain for the first time by it
+type BlockUpdate struct {
+	Master *BlockIDExt
+	// Shards - new shard blocks, including skipped ones which were not listed in master blocks.
+	// Parents always go before their children.
+	Shards []*BlockIDExt
+}
+
+// SubscribeOnBlocks - sends master blocks starting from fromSeqno, in order and each once, together with new shard blocks.
+// On errors the same block is requested again from another node, so nothing is skipped.
+// Channel is closed when context is done. To resume after restart, save seqno of the last processed
+// master block and subscribe from the next one. Shard blocks history is walked back to the shard blocks
+// of master block fromSeqno-1, so when starting from 0 only shard blocks listed in master blocks are sent.
+func (c *APIClient) SubscribeOnBlocks(workerCtx context.Context, fromSeqno uint32, channel chan<- *BlockUpdate) {
+	defer close(channel)
+
+	ctx := c.client.StickyContext(workerCtx)
+	seqno := fromSeqno
+
+	var lastShards []*BlockIDExt
+	wait := 0 * time.Second
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-workerCtx.Done():
+			return
+		case <-timer.C:
+		}
+		wait = 3 * time.Second
+
+		if lastShards == nil {
+			shards := []*BlockIDExt{}
+			if seqno > 0 {
+				qCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+				prev, err := c.WaitForBlock(seqno-1).LookupBlock(qCtx, address.MasterchainID, masterchainShard, seqno-1)
+				if err == nil {
+					shards, err = c.WaitForBlock(seqno-1).GetBlockShardsInfo(qCtx, prev)
+				}
+				cancel()
+
+				if err != nil {
+					ctx = c.nextSubscriptionNode(ctx)
+					timer.Reset(wait)
+					continue
+				}
+			}
+			lastShards = shards
+		}
+
+		qCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+		upd, shards, err := c.getBlockUpdate(qCtx, seqno, lastShards)
+		cancel()
+		if err != nil {
+			// block may be not yet created, or node is not available, try again
+			ctx = c.nextSubscriptionNode(ctx)
+			timer.Reset(wait)
+			continue
+		}
+
+		select {
+		case <-workerCtx.Done():
+			return
+		case channel <- upd:
+		}
+
+		lastShards = shards
+		seqno++
+		timer.Reset(0)
+	}
+}
+
+func (c *APIClient) nextSubscriptionNode(ctx context.Context) context.Context {
+	next, err := c.client.StickyContextNextNode(ctx)
+	if err != nil {
+		return ctx
+	}
+	return next
+}
+
+// getBlockUpdate - gets master block and shard blocks which are new comparing to lastShards
+func (c *APIClient) getBlockUpdate(ctx context.Context, seqno uint32, lastShards []*BlockIDExt) (*BlockUpdate, []*BlockIDExt, error) {
+	api := c.WaitForBlock(seqno)
+
+	master, err := api.LookupBlock(ctx, address.MasterchainID, masterchainShard, seqno)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to lookup master block %d: %w", seqno, err)
+	}
+
+	shards, err := api.GetBlockShardsInfo(ctx, master)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get shards of master block %d: %w", seqno, err)
+	}
+
+	upd := &BlockUpdate{Master: master}
+	visited := map[string]bool{}
+	for _, shard := range shards {
+		blocks, err := c.collectNewShardBlocks(ctx, shard, lastShards, visited)
+		if err != nil {
+			return nil, nil, err
+		}
+		upd.Shards = append(upd.Shards, blocks...)
+	}
+	return upd, shards, nil
+}
+
+// collectNewShardBlocks - walks back from the block through its parents, across splits and merges,
+// until blocks which are already known from the previous master block
+func (c *APIClient) collectNewShardBlocks(ctx context.Context, block *BlockIDExt, lastShards []*BlockIDExt, visited map[string]bool) ([]*BlockIDExt, error) {
+	if visited[string(block.RootHash)] {
+		return nil, nil
+	}
+	visited[string(block.RootHash)] = true
+
+	related := false
+	for _, s := range lastShards {
+		if s.Workchain != block.Workchain ||
+			(!tlb.ShardID(s.Shard).IsAncestor(tlb.ShardID(block.Shard)) && !tlb.ShardID(block.Shard).IsAncestor(tlb.ShardID(s.Shard))) {
+			continue
+		}
+		related = true
+
+		if block.SeqNo <= s.SeqNo {
+			// block of this shard chain is already known
+			return nil, nil
+		}
+	}
+
+	if !related {
+		// new workchain or no previous state, nothing to walk to
+		return []*BlockIDExt{block}, nil
+	}
+
+	hdr, err := c.GetBlockHeader(ctx, block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header of block %d:%x:%d: %w", block.Workchain, uint64(block.Shard), block.SeqNo, err)
+	}
+
+	parents, err := hdr.GetParentBlocks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parents of block %d:%x:%d: %w", block.Workchain, uint64(block.Shard), block.SeqNo, err)
+	}
+
+	var res []*BlockIDExt
+	for _, parent := range parents {
+		blocks, err := c.collectNewShardBlocks(ctx, parent, lastShards, visited)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, blocks...)
+	}
+	return append(res, block), nil
+}
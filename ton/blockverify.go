@@ -0,0 +1,100 @@
+package ton
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/alan890104/tonutils-go/address"
+)
+
+// max verified blocks to remember, cache is reset when it is reached
+const verifiedBlocksCacheSize = 4096
+
+// verifyBlockInMasterchain - in secure mode proves that block is a part of masterchain verified from trusted block.
+// Shard blocks are linked to master block using shard block proof. Verified blocks are cached.
+func (c *APIClient) verifyBlockInMasterchain(ctx context.Context, block *BlockIDExt) error {
+	if c.proofCheckPolicy != ProofCheckPolicySecure {
+		return nil
+	}
+
+	if c.isBlockVerified(block) {
+		return nil
+	}
+
+	master := block
+	if block.Workchain != address.MasterchainID {
+		proof, err := c.GetShardBlockProof(ctx, block)
+		if err != nil {
+			return fmt.Errorf("failed to get shard block proof: %w", err)
+		}
+		master = proof.MasterchainID
+
+		if !c.isBlockVerified(master) {
+			if err = c.verifyMasterBlock(ctx, master); err != nil {
+				return err
+			}
+			c.setBlockVerified(master)
+		}
+	} else if err := c.verifyMasterBlock(ctx, master); err != nil {
+		return err
+	}
+
+	c.setBlockVerified(block)
+	return nil
+}
+
+func (c *APIClient) verifyMasterBlock(ctx context.Context, master *BlockIDExt) error {
+	root := c.root()
+
+	root.trustedLock.RLock()
+	trusted := root.trustedBlock
+	root.trustedLock.RUnlock()
+
+	if trusted == nil {
+		// initializes trusted block
+		if _, err := c.GetMasterchainInfo(ctx); err != nil {
+			return fmt.Errorf("failed to get masterchain info: %w", err)
+		}
+
+		root.trustedLock.RLock()
+		trusted = root.trustedBlock
+		root.trustedLock.RUnlock()
+	}
+
+	if trusted.Equals(master) {
+		return nil
+	}
+
+	if err := c.VerifyProofChain(ctx, trusted.Copy(), master); err != nil {
+		return fmt.Errorf("failed to verify master block %d proof chain: %w", master.SeqNo, err)
+	}
+
+	// move trusted block forward, so next blocks are verified from the newer one
+	root.trustedLock.Lock()
+	if master.SeqNo > root.trustedBlock.SeqNo {
+		root.trustedBlock = master.Copy()
+	}
+	root.trustedLock.Unlock()
+	return nil
+}
+
+func (c *APIClient) isBlockVerified(block *BlockIDExt) bool {
+	root := c.root()
+	root.verifiedLock.RLock()
+	defer root.verifiedLock.RUnlock()
+
+	fileHash, ok := root.verifiedBlocks[string(block.RootHash)]
+	return ok && bytes.Equal(fileHash, block.FileHash)
+}
+
+func (c *APIClient) setBlockVerified(block *BlockIDExt) {
+	root := c.root()
+	root.verifiedLock.Lock()
+	defer root.verifiedLock.Unlock()
+
+	if root.verifiedBlocks == nil || len(root.verifiedBlocks) >= verifiedBlocksCacheSize {
+		root.verifiedBlocks = map[string][]byte{}
+	}
+	root.verifiedBlocks[string(block.RootHash)] = block.FileHash
+}
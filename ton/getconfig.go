@@ -3,10 +3,13 @@ package ton
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 
 	"github.com/alan890104/tonutils-go/tl"
+	"github.com/alan890104/tonutils-go/tlb"
 	"github.com/alan890104/tonutils-go/tvm/cell"
 )
 
@@ -18,6 +21,8 @@ func init() {
 	tl.Register(GetLibraries{}, "liteServer.getLibraries library_list:(vector int256) = liteServer.LibraryResult")
 	tl.Register(LibraryEntry{}, "liteServer.libraryEntry hash:int256 data:bytes = liteServer.LibraryEntry")
 	tl.Register(LibraryResult{}, "liteServer.libraryResult result:(vector liteServer.libraryEntry) = liteServer.LibraryResult")
+	tl.Register(GetLibrariesWithProof{}, "liteServer.getLibrariesWithProof id:tonNode.blockIdExt mode:# library_list:(vector int256) = liteServer.LibraryResultWithProof")
+	tl.Register(LibraryResultWithProof{}, "liteServer.libraryResultWithProof id:tonNode.blockIdExt mode:# result:(vector liteServer.libraryEntry) state_proof:bytes data_proof:bytes = liteServer.LibraryResultWithProof")
 }
 
 type GetLibraries struct {
@@ -33,6 +38,20 @@ type LibraryResult struct {
 	Result []*LibraryEntry `tl:"vector struct"`
 }
 
+type GetLibrariesWithProof struct {
+	ID          *BlockIDExt `tl:"struct"`
+	Mode        uint32      `tl:"flags"`
+	LibraryList [][]byte    `tl:"vector int256"`
+}
+
+type LibraryResultWithProof struct {
+	ID         *BlockIDExt     `tl:"struct"`
+	Mode       uint32          `tl:"flags"`
+	Result     []*LibraryEntry `tl:"vector struct"`
+	StateProof *cell.Cell      `tl:"cell"`
+	DataProof  *cell.Cell      `tl:"cell"`
+}
+
 type ConfigAll struct {
 	Mode        int         `tl:"int"`
 	ID          *BlockIDExt `tl:"struct"`
@@ -87,6 +106,90 @@ func (c *APIClient) GetLibraries(ctx context.Context, hashes ...[]byte) ([]*cell
 	return nil, errUnexpectedResponse(resp)
 }
 
+// GetLibrariesWithProof - same as GetLibraries, but libraries are taken from the state of the given master block,
+// and their presence or absence is verified with state proof
+func (c *APIClient) GetLibrariesWithProof(ctx context.Context, block *BlockIDExt, hashes ...[]byte) ([]*cell.Cell, error) {
+	var resp tl.Serializable
+	err := c.client.QueryLiteserver(ctx, GetLibrariesWithProof{
+		ID:          block,
+		Mode:        1, // with data
+		LibraryList: hashes,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := resp.(type) {
+	case LibraryResultWithProof:
+		if !t.ID.Equals(block) {
+			return nil, fmt.Errorf("incorrect block in response")
+		}
+
+		libList := make([]*cell.Cell, len(hashes))
+		for i := 0; i < len(hashes); i++ {
+			for _, e := range t.Result {
+				if e.Data != nil && bytes.Equal(hashes[i], e.Data.Hash()) {
+					libList[i] = e.Data
+				}
+			}
+		}
+
+		if c.proofCheckPolicy != ProofCheckPolicyUnsafe {
+			libs, err := checkLibrariesProof(block, t.StateProof, t.DataProof)
+			if err != nil {
+				return nil, err
+			}
+
+			for i, hash := range hashes {
+				_, err = libs.LoadValue(cell.BeginCell().MustStoreSlice(hash, 256).EndCell())
+				if err != nil && !errors.Is(err, cell.ErrNoSuchKeyInDict) {
+					return nil, fmt.Errorf("failed to load library %s from proof: %w", hex.EncodeToString(hash), err)
+				}
+
+				if (err == nil) != (libList[i] != nil) {
+					return nil, fmt.Errorf("library %s presence not matches proof", hex.EncodeToString(hash))
+				}
+			}
+		}
+		return libList, nil
+	case LSError:
+		return nil, t
+	}
+	return nil, errUnexpectedResponse(resp)
+}
+
+// checkLibrariesProof - verifies state proof and returns libraries dictionary from shard state
+func checkLibrariesProof(block *BlockIDExt, stateProof, dataProof *cell.Cell) (*cell.Dictionary, error) {
+	state, err := CheckBlockShardStateProof([]*cell.Cell{stateProof, dataProof}, block.RootHash)
+	if err != nil {
+		return nil, fmt.Errorf("incorrect proof: %w", err)
+	}
+
+	if state.Stats == nil {
+		return nil, fmt.Errorf("no state stats in proof")
+	}
+
+	// overload_history:uint64 underload_history:uint64 total_balance:CurrencyCollection
+	// total_validator_fees:CurrencyCollection libraries:(HashmapE 256 LibDescr) master_ref:(Maybe BlkMasterInfo)
+	loader := state.Stats.BeginParse()
+	if _, err = loader.LoadSlice(128); err != nil {
+		return nil, fmt.Errorf("failed to skip history: %w", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		var cc tlb.CurrencyCollection
+		if err = tlb.LoadFromCell(&cc, loader); err != nil {
+			return nil, fmt.Errorf("failed to skip balance: %w", err)
+		}
+	}
+
+	libs, err := loader.LoadDict(256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load libraries dict: %w", err)
+	}
+	return libs, nil
+}
+
 func (c *APIClient) GetBlockchainConfig(ctx context.Context, block *BlockIDExt, onlyParams ...int32) (*BlockchainConfig, error) {
 	var resp tl.Serializable
 	var err error
@@ -0,0 +1,40 @@
+package ton
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+func TestCheckLibrariesProof(t *testing.T) {
+	master, header := testMasterHeaderBlock(t)
+
+	proof, err := cell.FromBOC(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = checkLibrariesProof(master, proof, proof); err == nil {
+		t.Fatal("header proof without state should fail")
+	}
+
+	other := testMasterBlockID(master.SeqNo, 1)
+	if _, err = checkLibrariesProof(other, proof, proof); err == nil {
+		t.Fatal("proof of other block should fail")
+	}
+
+	data, _ := hex.DecodeString(testShardHeaderProof)
+	shardProof, err := cell.FromBOC(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = checkLibrariesProof(master, shardProof, proof); err == nil {
+		t.Fatal("proof of shard block should fail")
+	}
+
+	if _, err = checkLibrariesProof(master, cell.BeginCell().EndCell(), proof); err == nil {
+		t.Fatal("empty proof should fail")
+	}
+}
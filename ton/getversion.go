@@ -0,0 +1,64 @@
+package ton
+
+import (
+	"context"
+
+	"github.com/alan890104/tonutils-go/tl"
+)
+
+// LSCapability - feature flags reported by liteserver in version and masterchainInfoExt responses
+type LSCapability int64
+
+const (
+	LSCapabilityProofChains LSCapability = 1 << iota
+	LSCapabilityMasterchainInfoExt
+	LSCapabilityRunSmcMethod
+)
+
+// HasCapability - checks that liteserver supports all the given features
+func (v *Version) HasCapability(capability LSCapability) bool {
+	return LSCapability(v.Capabilities)&capability == capability
+}
+
+// HasCapability - checks that liteserver supports all the given features
+func (m *MasterchainInfoExt) HasCapability(capability LSCapability) bool {
+	return LSCapability(m.Capabilities)&capability == capability
+}
+
+// GetVersion - gets liteserver version, its capabilities and current time
+func (c *APIClient) GetVersion(ctx context.Context) (*Version, error) {
+	var resp tl.Serializable
+	err := c.client.QueryLiteserver(ctx, GetVersion{}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := resp.(type) {
+	case Version:
+		return &t, nil
+	case LSError:
+		return nil, t
+	}
+	return nil, errUnexpectedResponse(resp)
+}
+
+// GetMasterchainInfoExt - same as GetMasterchainInfo, but also returns liteserver version,
+// capabilities, last master block time and state root hash
+func (c *APIClient) GetMasterchainInfoExt(ctx context.Context) (*MasterchainInfoExt, error) {
+	var resp tl.Serializable
+	err := c.client.QueryLiteserver(ctx, GetMasterchainInfoExt{}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := resp.(type) {
+	case MasterchainInfoExt:
+		if err = c.checkLastMasterBlock(ctx, t.Last); err != nil {
+			return nil, err
+		}
+		return &t, nil
+	case LSError:
+		return nil, t
+	}
+	return nil, errUnexpectedResponse(resp)
+}
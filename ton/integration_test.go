@@ -121,6 +121,230 @@ func TestAPIClient_GetBlockData(t *testing.T) {
 	// TODO: data check
 }
 
+func TestAPIClient_GetBlockHeaderAndShardProof(t *testing.T) {
+	ctx := api.Client().StickyContext(context.Background())
+
+	b, err := api.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		t.Fatal("get block err:", err.Error())
+	}
+
+	hdr, err := api.WaitForBlock(b.SeqNo).GetBlockHeader(ctx, b)
+	if err != nil {
+		t.Fatal("get block header err:", err.Error())
+	}
+	if hdr.SeqNo != b.SeqNo {
+		t.Fatal("incorrect header seqno", hdr.SeqNo)
+	}
+
+	shard, err := api.WaitForBlock(b.SeqNo).GetShardInfo(ctx, b, 0, int64(-0x8000000000000000), false)
+	if err != nil {
+		t.Fatal("get shard info err:", err.Error())
+	}
+
+	exact, err := api.WaitForBlock(b.SeqNo).GetShardInfo(ctx, b, 0, shard.Shard, true)
+	if err != nil {
+		t.Fatal("get exact shard info err:", err.Error())
+	}
+	if !exact.Equals(shard) {
+		t.Fatal("exact shard not matches")
+	}
+
+	proof, err := api.WaitForBlock(b.SeqNo).GetShardBlockProof(ctx, shard)
+	if err != nil {
+		t.Fatal("get shard block proof err:", err.Error())
+	}
+
+	if err = CheckShardBlockProof(proof, shard); err != nil {
+		t.Fatal("check shard block proof err:", err.Error())
+	}
+
+	txs, _, err := api.WaitForBlock(b.SeqNo).GetBlockTransactionsExt(ctx, shard, 10)
+	if err != nil {
+		t.Fatal("get block transactions ext err:", err.Error())
+	}
+
+	for _, tx := range txs {
+		if tx.Hash == nil {
+			t.Fatal("tx hash should be set")
+		}
+	}
+}
+
+func TestAPIClient_GetVersion(t *testing.T) {
+	ctx := api.Client().StickyContext(context.Background())
+
+	ver, err := api.GetVersion(ctx)
+	if err != nil {
+		t.Fatal("get version err:", err.Error())
+	}
+
+	if !ver.HasCapability(LSCapabilityProofChains) {
+		t.Fatal("liteserver should support proof chains")
+	}
+
+	if ver.HasCapability(LSCapabilityMasterchainInfoExt) {
+		inf, err := api.GetMasterchainInfoExt(ctx)
+		if err != nil {
+			t.Fatal("get masterchain info ext err:", err.Error())
+		}
+
+		if inf.Last == nil || inf.LastUTime == 0 {
+			t.Fatal("incorrect masterchain info ext")
+		}
+	}
+}
+
+func TestAPIClient_LookupBlockWithProof(t *testing.T) {
+	ctx := api.Client().StickyContext(context.Background())
+
+	master, err := api.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		t.Fatal("get masterchain info err:", err.Error())
+	}
+
+	shards, err := api.GetBlockShardsInfo(ctx, master)
+	if err != nil {
+		t.Fatal("get shards err:", err.Error())
+	}
+
+	blk, err := api.LookupBlockWithProof(ctx, master, shards[0].Workchain, shards[0].Shard, shards[0].SeqNo-10)
+	if err != nil {
+		t.Fatal("lookup block with proof err:", err.Error())
+	}
+
+	if blk.SeqNo != shards[0].SeqNo-10 || blk.Shard != shards[0].Shard {
+		t.Fatal("incorrect block found")
+	}
+}
+
+func TestAPIClient_LookupBlockByTime(t *testing.T) {
+	ctx := api.Client().StickyContext(context.Background())
+
+	master, err := api.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		t.Fatal("get masterchain info err:", err.Error())
+	}
+
+	hdr, err := api.GetBlockHeader(ctx, master)
+	if err != nil {
+		t.Fatal("get block header err:", err.Error())
+	}
+
+	blk, err := api.LookupBlockByLT(ctx, master.Workchain, master.Shard, hdr.StartLt)
+	if err != nil {
+		t.Fatal("lookup by lt err:", err.Error())
+	}
+	if blk.SeqNo != master.SeqNo {
+		t.Fatal("incorrect block found by lt", blk.SeqNo, master.SeqNo)
+	}
+
+	blk, err = api.LookupBlockByUtime(ctx, master.Workchain, master.Shard, hdr.GenUtime-60)
+	if err != nil {
+		t.Fatal("lookup by utime err:", err.Error())
+	}
+	if blk.SeqNo >= master.SeqNo {
+		t.Fatal("incorrect block found by utime")
+	}
+
+	found, shards, err := api.FindBlocksByUtime(ctx, hdr.GenUtime-3600)
+	if err != nil {
+		t.Fatal("find blocks by utime err:", err.Error())
+	}
+
+	foundHdr, err := api.GetBlockHeader(ctx, found)
+	if err != nil {
+		t.Fatal("get found block header err:", err.Error())
+	}
+
+	nextID, err := api.LookupBlock(ctx, found.Workchain, found.Shard, found.SeqNo+1)
+	if err != nil {
+		t.Fatal("lookup next block err:", err.Error())
+	}
+
+	nextHdr, err := api.GetBlockHeader(ctx, nextID)
+	if err != nil {
+		t.Fatal("get next block header err:", err.Error())
+	}
+
+	if foundHdr.GenUtime > hdr.GenUtime-3600 || nextHdr.GenUtime <= hdr.GenUtime-3600 {
+		t.Fatal("incorrect block found by time")
+	}
+
+	if len(shards) == 0 {
+		t.Fatal("no shards found")
+	}
+}
+
+func TestAPIClient_ListTransactionsWithProof(t *testing.T) {
+	ctx := api.Client().StickyContext(context.Background())
+
+	master, err := api.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		t.Fatal("get masterchain info err:", err.Error())
+	}
+
+	acc, err := api.GetAccount(ctx, master, testContractAddr)
+	if err != nil {
+		t.Fatal("get account err:", err.Error())
+	}
+
+	txs, err := api.ListTransactionsWithProof(ctx, master, testContractAddr, 5, acc.LastTxLT, acc.LastTxHash)
+	if err != nil {
+		t.Fatal("list transactions with proof err:", err.Error())
+	}
+
+	if len(txs) == 0 || txs[len(txs)-1].LT != acc.LastTxLT {
+		t.Fatal("incorrect transactions list")
+	}
+}
+
+func TestAPIClient_GetOutMsgQueueSizes(t *testing.T) {
+	ctx := api.Client().StickyContext(context.Background())
+
+	sizes, err := api.GetOutMsgQueueSizes(ctx)
+	if err != nil {
+		t.Fatal("get out msg queue sizes err:", err.Error())
+	}
+
+	if len(sizes.Shards) == 0 {
+		t.Fatal("no shards in response")
+	}
+
+	size, err := api.GetBlockOutMsgQueueSize(ctx, sizes.Shards[0].ID)
+	if err != nil {
+		t.Fatal("get block out msg queue size err:", err.Error())
+	}
+	t.Logf("queue size: %d", size)
+}
+
+func TestAPIClient_GetValidatorStats(t *testing.T) {
+	ctx := api.Client().StickyContext(context.Background())
+
+	master, err := api.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		t.Fatal("get masterchain info err:", err.Error())
+	}
+
+	stats, _, err := api.GetValidatorStats(ctx, master, 5, nil, 0)
+	if err != nil {
+		t.Fatal("get validator stats err:", err.Error())
+	}
+
+	if len(stats) == 0 {
+		t.Fatal("no validator stats")
+	}
+
+	next, _, err := api.GetValidatorStats(ctx, master, 5, stats[len(stats)-1].PublicKey, 0)
+	if err != nil {
+		t.Fatal("get next validator stats err:", err.Error())
+	}
+
+	if len(next) > 0 && bytes.Compare(next[0].PublicKey, stats[len(stats)-1].PublicKey) <= 0 {
+		t.Fatal("incorrect stats order")
+	}
+}
+
 func TestAPIClient_GetOldBlockData(t *testing.T) {
 	client := liteclient.NewConnectionPool()
 
@@ -46,11 +46,11 @@ type TransferNotification struct {
 }
 
 type Data struct {
-	TotalSupply *big.Int
-	Mintable    bool
-	AdminAddr   *address.Address
-	Content     nft.ContentAny
-	WalletCode  *cell.Cell
+	TotalSupply *big.Int         `stack:"0"`
+	Mintable    bool             `stack:"1"`
+	AdminAddr   *address.Address `stack:"2"`
+	Content     nft.ContentAny   `stack:"3"`
+	WalletCode  *cell.Cell       `stack:"4"`
 }
 
 type Client struct {
@@ -110,45 +110,9 @@ func (c *Client) GetJettonDataAtBlock(ctx context.Context, b *ton.BlockIDExt) (*
 		return nil, fmt.Errorf("failed to run get_jetton_data method: %w", err)
 	}
 
-	supply, err := res.Int(0)
-	if err != nil {
-		return nil, fmt.Errorf("supply get err: %w", err)
-	}
-
-	mintable, err := res.Int(1)
-	if err != nil {
-		return nil, fmt.Errorf("mintable get err: %w", err)
-	}
-
-	adminAddr, err := res.Slice(2)
-	if err != nil {
-		return nil, fmt.Errorf("admin addr get err: %w", err)
-	}
-	addr, err := adminAddr.LoadAddr()
-	if err != nil {
-		return nil, fmt.Errorf("failed to load address from adminAddr slice: %w", err)
-	}
-
-	contentCell, err := res.Cell(3)
-	if err != nil {
-		return nil, fmt.Errorf("content cell get err: %w", err)
-	}
-
-	content, err := nft.ContentFromCell(contentCell)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load content from contentCell: %w", err)
+	var data Data
+	if err = res.Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode get_jetton_data result: %w", err)
 	}
-
-	walletCode, err := res.Cell(4)
-	if err != nil {
-		return nil, fmt.Errorf("wallet code get err: %w", err)
-	}
-
-	return &Data{
-		TotalSupply: supply,
-		Mintable:    mintable.Cmp(big.NewInt(0)) != 0,
-		AdminAddr:   addr,
-		Content:     content,
-		WalletCode:  walletCode,
-	}, nil
+	return &data, nil
 }
@@ -0,0 +1,334 @@
+package ton
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alan890104/tonutils-go/address"
+	"github.com/alan890104/tonutils-go/tl"
+	"github.com/alan890104/tonutils-go/tlb"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+func init() {
+	tl.Register(LookupBlockWithProof{}, "liteServer.lookupBlockWithProof mode:# id:tonNode.blockId mc_block_id:tonNode.blockIdExt lt:mode.1?long utime:mode.2?int = liteServer.LookupBlockResult")
+	tl.Register(LookupBlockResult{}, "liteServer.lookupBlockResult id:tonNode.blockIdExt mode:# mc_block_id:tonNode.blockIdExt client_mc_state_proof:bytes mc_block_proof:bytes shard_links:(vector liteServer.shardBlockLink) header:bytes prev_header:bytes = liteServer.LookupBlockResult")
+}
+
+const (
+	lookupBySeqno = 1 << 0
+	lookupByLT    = 1 << 1
+	lookupByUtime = 1 << 2
+)
+
+type LookupBlockWithProof struct {
+	Mode      uint32          `tl:"flags"`
+	ID        *BlockInfoShort `tl:"struct"`
+	McBlockID *BlockIDExt     `tl:"struct"`
+	LT        uint64          `tl:"?1 long"`
+	UTime     uint32          `tl:"?2 int"`
+}
+
+type LookupBlockResult struct {
+	ID                 *BlockIDExt      `tl:"struct"`
+	Mode               uint32           `tl:"int"`
+	McBlockID          *BlockIDExt      `tl:"struct"`
+	ClientMcStateProof []byte           `tl:"bytes"`
+	McBlockProof       []byte           `tl:"bytes"`
+	ShardLinks         []ShardBlockLink `tl:"vector struct"`
+	Header             []byte           `tl:"bytes"`
+	PrevHeader         []byte           `tl:"bytes"`
+}
+
+// LookupBlockByLT - finds block of the shard which contains the given logical time
+func (c *APIClient) LookupBlockByLT(ctx context.Context, workchain int32, shard int64, lt uint64) (*BlockIDExt, error) {
+	id, _, err := c.lookupBlockHeader(ctx, lookupByLT, &BlockInfoShort{
+		Workchain: workchain,
+		Shard:     shard,
+	}, lt, 0)
+	return id, err
+}
+
+// LookupBlockByUtime - finds block of the shard which was generated at the given unix time
+func (c *APIClient) LookupBlockByUtime(ctx context.Context, workchain int32, shard int64, utime uint32) (*BlockIDExt, error) {
+	id, _, err := c.lookupBlockHeader(ctx, lookupByUtime, &BlockInfoShort{
+		Workchain: workchain,
+		Shard:     shard,
+	}, 0, utime)
+	return id, err
+}
+
+// FindMasterBlockByUtime - finds the last master block generated not later than utime,
+// it is the block which was active at that moment. Liteserver should have blocks
+// of the requested period, archive node is required for old ones.
+func (c *APIClient) FindMasterBlockByUtime(ctx context.Context, utime uint32) (*BlockIDExt, error) {
+	id, hdr, err := c.lookupBlockHeader(ctx, lookupByUtime, &BlockInfoShort{
+		Workchain: address.MasterchainID,
+		Shard:     masterchainShard,
+	}, 0, utime)
+	if err != nil {
+		if !errors.Is(err, ErrBlockNotFound) {
+			return nil, fmt.Errorf("failed to lookup master block by utime: %w", err)
+		}
+
+		// utime can be after the last block, then the last block is active
+		last, err := c.CurrentMasterchainInfo(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get masterchain info: %w", err)
+		}
+
+		lastHdr, err := c.GetBlockHeader(ctx, last)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get last master block header: %w", err)
+		}
+
+		if lastHdr.GenUtime > utime {
+			return nil, ErrBlockNotFound
+		}
+		return last, nil
+	}
+
+	// lookup can return the first block generated after utime, then the previous one was active
+	for hdr.GenUtime > utime {
+		if id.SeqNo <= 1 {
+			return nil, ErrBlockNotFound
+		}
+
+		seqno := id.SeqNo - 1
+		id, hdr, err = c.lookupBlockHeader(ctx, lookupBySeqno, &BlockInfoShort{
+			Workchain: id.Workchain,
+			Shard:     id.Shard,
+			Seqno:     int32(seqno),
+		}, 0, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lookup master block %d: %w", seqno, err)
+		}
+	}
+	return id, nil
+}
+
+// FindBlocksByUtime - finds master block which was active at the given unix time
+// and shard blocks committed in it, together they represent consistent state of the chain at that moment
+func (c *APIClient) FindBlocksByUtime(ctx context.Context, utime uint32) (*BlockIDExt, []*BlockIDExt, error) {
+	master, err := c.FindMasterBlockByUtime(ctx, utime)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shards, err := c.GetBlockShardsInfo(ctx, master)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get shards of master block %d: %w", master.SeqNo, err)
+	}
+	return master, shards, nil
+}
+
+func (c *APIClient) lookupBlockHeader(ctx context.Context, mode uint32, id *BlockInfoShort, lt uint64, utime uint32) (*BlockIDExt, *tlb.BlockHeader, error) {
+	var resp tl.Serializable
+	err := c.client.QueryLiteserver(ctx, LookupBlock{
+		Mode:  mode,
+		ID:    id,
+		LT:    lt,
+		UTime: utime,
+	}, &resp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch t := resp.(type) {
+	case BlockHeader:
+		if t.ID == nil || t.ID.Workchain != id.Workchain ||
+			(mode&lookupBySeqno != 0 && t.ID.SeqNo != uint32(id.Seqno)) {
+			return nil, nil, fmt.Errorf("incorrect block in response")
+		}
+
+		proof, err := cell.FromBOC(t.HeaderProof)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse header proof boc: %w", err)
+		}
+
+		blk, err := CheckBlockProof(proof, t.ID.RootHash)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to check header proof: %w", err)
+		}
+
+		wc, shard := tlb.ConvertShardIdentToShard(blk.BlockInfo.Shard)
+		if blk.BlockInfo.SeqNo != t.ID.SeqNo || wc != t.ID.Workchain || int64(shard) != t.ID.Shard {
+			return nil, nil, fmt.Errorf("header not matches block in response")
+		}
+		return t.ID, &blk.BlockInfo, nil
+	case LSError:
+		return nil, nil, lookupError(t)
+	}
+	return nil, nil, errUnexpectedResponse(resp)
+}
+
+// LookupBlockWithProof - same as LookupBlock, but found block is verified
+// to be committed in the chain of the given master block
+func (c *APIClient) LookupBlockWithProof(ctx context.Context, master *BlockIDExt, workchain int32, shard int64, seqno uint32) (*BlockIDExt, error) {
+	return c.lookupBlockWithProof(ctx, master, lookupBySeqno, &BlockInfoShort{
+		Workchain: workchain,
+		Shard:     shard,
+		Seqno:     int32(seqno),
+	}, 0, 0)
+}
+
+func (c *APIClient) lookupBlockWithProof(ctx context.Context, master *BlockIDExt, mode uint32, id *BlockInfoShort, lt uint64, utime uint32) (*BlockIDExt, error) {
+	var resp tl.Serializable
+	err := c.client.QueryLiteserver(ctx, LookupBlockWithProof{
+		Mode:      mode,
+		ID:        id,
+		McBlockID: master,
+		LT:        lt,
+		UTime:     utime,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := resp.(type) {
+	case LookupBlockResult:
+		if t.ID == nil || t.McBlockID == nil {
+			return nil, fmt.Errorf("incorrect block in response")
+		}
+
+		if c.proofCheckPolicy != ProofCheckPolicyUnsafe {
+			if err = checkLookupBlockResult(&t, master, mode, id, lt, utime); err != nil {
+				return nil, fmt.Errorf("failed to check lookup proof: %w", err)
+			}
+		}
+		return t.ID, nil
+	case LSError:
+		return nil, lookupError(t)
+	}
+	return nil, errUnexpectedResponse(resp)
+}
+
+// lookupError - converts liteserver error of block lookup, block not found is returned as ErrBlockNotFound
+func lookupError(err LSError) error {
+	// 651 = block not found code
+	if err.Code == 651 {
+		return ErrBlockNotFound
+	}
+	return err
+}
+
+func checkLookupBlockResult(res *LookupBlockResult, master *BlockIDExt, mode uint32, id *BlockInfoShort, lt uint64, utime uint32) error {
+	if res.ID.Workchain != id.Workchain {
+		return fmt.Errorf("incorrect workchain of found block")
+	}
+
+	if !tlb.ShardID(res.ID.Shard).IsAncestor(tlb.ShardID(id.Shard)) &&
+		!tlb.ShardID(id.Shard).IsAncestor(tlb.ShardID(res.ID.Shard)) {
+		return fmt.Errorf("found block shard is not related to requested")
+	}
+
+	if mode&lookupBySeqno != 0 && res.ID.SeqNo != uint32(id.Seqno) {
+		return fmt.Errorf("incorrect seqno of found block")
+	}
+
+	if res.McBlockID.Workchain != address.MasterchainID || res.McBlockID.SeqNo > master.SeqNo {
+		return fmt.Errorf("incorrect master block in response")
+	}
+
+	if !res.McBlockID.Equals(master) {
+		// master block which contains found block is older than ours,
+		// so we check that it is in the prev blocks list of our state
+		blockProof, err := cell.FromBOC(res.ClientMcStateProof)
+		if err != nil {
+			return fmt.Errorf("failed to parse client master block proof boc: %w", err)
+		}
+
+		stateProof, err := cell.FromBOC(res.McBlockProof)
+		if err != nil {
+			return fmt.Errorf("failed to parse client master state proof boc: %w", err)
+		}
+
+		stateExtra, err := CheckShardMcStateExtraProof(master, []*cell.Cell{blockProof, stateProof})
+		if err != nil {
+			return fmt.Errorf("failed to check client master state proof: %w", err)
+		}
+
+		old, err := stateExtra.Info.PrevBlocks.Get(res.McBlockID.SeqNo)
+		if err != nil {
+			return fmt.Errorf("master block %d not found in prev blocks: %w", res.McBlockID.SeqNo, err)
+		}
+
+		if !bytes.Equal(old.Block.BlkRef.RootHash, res.McBlockID.RootHash) ||
+			!bytes.Equal(old.Block.BlkRef.FileHash, res.McBlockID.FileHash) {
+			return fmt.Errorf("master block %d hash not matches prev blocks", res.McBlockID.SeqNo)
+		}
+	}
+
+	if err := CheckShardBlockProof(&ShardBlockProof{
+		MasterchainID: res.McBlockID,
+		Links:         res.ShardLinks,
+	}, res.ID); err != nil {
+		return fmt.Errorf("failed to check shard links: %w", err)
+	}
+
+	headerProof, err := cell.FromBOC(res.Header)
+	if err != nil {
+		return fmt.Errorf("failed to parse header boc: %w", err)
+	}
+
+	blk, err := CheckBlockProof(headerProof, res.ID.RootHash)
+	if err != nil {
+		return fmt.Errorf("failed to check header proof: %w", err)
+	}
+
+	wc, shard := tlb.ConvertShardIdentToShard(blk.BlockInfo.Shard)
+	if blk.BlockInfo.SeqNo != res.ID.SeqNo || wc != res.ID.Workchain || int64(shard) != res.ID.Shard {
+		return fmt.Errorf("header not matches found block")
+	}
+
+	if mode&(lookupByLT|lookupByUtime) == 0 {
+		return nil
+	}
+
+	if mode&lookupByLT != 0 && lt >= blk.BlockInfo.EndLt {
+		return fmt.Errorf("requested lt is after found block")
+	}
+
+	if mode&lookupByUtime != 0 && utime > blk.BlockInfo.GenUtime {
+		return fmt.Errorf("requested utime is after found block")
+	}
+
+	if blk.BlockInfo.SeqNo == 0 {
+		// zerostate has no previous block
+		return nil
+	}
+
+	// previous block should be before requested lt or utime, otherwise it should be returned
+	prevProof, err := cell.FromBOC(res.PrevHeader)
+	if err != nil {
+		return fmt.Errorf("failed to parse prev header boc: %w", err)
+	}
+
+	parents, err := blk.BlockInfo.GetParentBlocks()
+	if err != nil {
+		return fmt.Errorf("failed to get parents of found block: %w", err)
+	}
+
+	var prev *tlb.Block
+	for _, parent := range parents {
+		// after merge block has 2 parents, proof can be for any of them
+		if prev, err = CheckBlockProof(prevProof, parent.RootHash); err == nil {
+			break
+		}
+	}
+
+	if prev == nil {
+		return fmt.Errorf("prev header is not a parent of found block")
+	}
+
+	if mode&lookupByLT != 0 && lt < prev.BlockInfo.EndLt {
+		return fmt.Errorf("requested lt is in prev block")
+	}
+
+	if mode&lookupByUtime != 0 && utime < prev.BlockInfo.GenUtime {
+		return fmt.Errorf("requested utime is before prev block")
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package ton
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/alan890104/tonutils-go/tl"
+)
+
+// header proofs of master block 24374597 and shard block 29836576
+const (
+	testMasterHeaderProof = "b5ee9c7241020701000147000946031908d071c508674d205fae80f3793faf9448202166f9189769e45d506d1e0b56001501241011ef55aaffffff110203040501a09bc7a9870000000004010173ed450000000100ffffffff0000000000000000634e93ea00001d3677b8338000001d3677b83384955d862e00058edb0173ed410173bfbec400000003000000000000002e0628480101722a64735fabcfc1da1c8d3e7bc91671a222e81838a03de86e661afffb94ce1b0003284801010c5f6b7ec9313294fae3684120186ce46f5fda895db29b38126fcda21bc77adb0014284801012736daee89910b52d7041a889bf97c864cfc84eeafba291a1b5b2e931cc1b5e80008009800001d3677a8f1440173ed443de180887d5f5a84d44bd19c87cbb664b0561d5eb81da88c5782b0e36e9a07e4d7fd7d801561f54bffc0cb5c4ec4e855deeeeb6fdf26d4c99a086ffafb93580ac075a79a"
+	testShardHeaderProof  = "b5ee9c724102080100019600094603129b0f8f95c96555fcd13074ad07ee965fdaa06b29cb5f7aeb45e566b137817b000501241011ef55aaffffff110203040502a09bc7a98700000000840101c745200000000100000000000000000000000000634e94ec00001d367caaae4000001d367caaae419bbc68ac00058fb00173ed920173bfbec400000003000000000000002e06072848010154c79ed4266d1124661a12a88fad1dc2f82f5d9e74808ac5c4ccd074cccc5abf000328480101e23f1c5911eebc3c6e20d6fd4fea82717df1864c23bb3458ca30dd58f7205e12000428480101036c9a27c1dcc7cb2d9bb2424f71b1ac9c829a129340452ce5cb68de540a878a0001009800001d367c9b6c040173ed92b57df82537164b18661e22f620e1a7a15826a73d7402eef9433d55c030232370a7caa150ac8f2f4c74cb5c77e6671edb6f8accd65c683faf6e48a88720b2c72d009800001d367c9b6c0101c7451f78d2820caf6a5f100a444450ddab2f7754bbce7c6027dce5349269227866124a33b3efd318a7ec75c8f26844fd4dce5f581927f670a0087d7fec56658b487d7285d2ed9a"
+)
+
+func testMasterHeaderBlock(t *testing.T) (*BlockIDExt, []byte) {
+	proof, err := hex.DecodeString(testMasterHeaderProof)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := testMasterBlockID(24374597, 0)
+	id.RootHash, _ = hex.DecodeString("1908d071c508674d205fae80f3793faf9448202166f9189769e45d506d1e0b56")
+	return id, proof
+}
+
+func TestLookupError(t *testing.T) {
+	if err := lookupError(LSError{Code: 651, Text: "not found"}); !errors.Is(err, ErrBlockNotFound) {
+		t.Fatal("651 should be block not found, got", err)
+	}
+
+	if err := lookupError(LSError{Code: 400, Text: "bad"}); errors.Is(err, ErrBlockNotFound) {
+		t.Fatal("other codes should be returned as is")
+	}
+}
+
+func TestCheckLookupBlockResult(t *testing.T) {
+	master, header := testMasterHeaderBlock(t)
+	shardHeader, err := hex.DecodeString(testShardHeaderProof)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bySeqno := &BlockInfoShort{Workchain: master.Workchain, Shard: master.Shard, Seqno: int32(master.SeqNo)}
+	result := func() *LookupBlockResult {
+		return &LookupBlockResult{
+			ID:        master,
+			Mode:      lookupBySeqno,
+			McBlockID: master,
+			Header:    header,
+		}
+	}
+
+	if err = checkLookupBlockResult(result(), master, lookupBySeqno, bySeqno, 0, 0); err != nil {
+		t.Fatal("correct result should pass:", err)
+	}
+
+	if err = checkLookupBlockResult(result(), master, lookupBySeqno,
+		&BlockInfoShort{Workchain: 0, Shard: master.Shard, Seqno: bySeqno.Seqno}, 0, 0); err == nil {
+		t.Fatal("other workchain should fail")
+	}
+
+	if err = checkLookupBlockResult(result(), master, lookupBySeqno,
+		&BlockInfoShort{Workchain: master.Workchain, Shard: 0x4000000000000000, Seqno: bySeqno.Seqno}, 0, 0); err != nil {
+		t.Fatal("child shard of found block should pass:", err)
+	}
+
+	res := result()
+	res.ID = testBlockID(-1, 0x4000000000000000, master.SeqNo, 0)
+	if err = checkLookupBlockResult(res, master, lookupBySeqno,
+		&BlockInfoShort{Workchain: master.Workchain, Shard: int64(-0x4000000000000000), Seqno: bySeqno.Seqno}, 0, 0); err == nil {
+		t.Fatal("unrelated shard should fail")
+	}
+
+	if err = checkLookupBlockResult(result(), master, lookupBySeqno,
+		&BlockInfoShort{Workchain: master.Workchain, Shard: master.Shard, Seqno: bySeqno.Seqno + 1}, 0, 0); err == nil {
+		t.Fatal("other seqno should fail")
+	}
+
+	res = result()
+	res.McBlockID = testMasterBlockID(master.SeqNo+1, 1)
+	if err = checkLookupBlockResult(res, master, lookupBySeqno, bySeqno, 0, 0); err == nil {
+		t.Fatal("master block newer than requested should fail")
+	}
+
+	res = result()
+	res.McBlockID = testBlockID(0, 0x8000000000000000, master.SeqNo, 0)
+	if err = checkLookupBlockResult(res, master, lookupBySeqno, bySeqno, 0, 0); err == nil {
+		t.Fatal("not master block should fail")
+	}
+
+	res = result()
+	res.McBlockID = testMasterBlockID(master.SeqNo-1, 1)
+	if err = checkLookupBlockResult(res, master, lookupBySeqno, bySeqno, 0, 0); err == nil {
+		t.Fatal("older master block without state proof should fail")
+	}
+
+	res = result()
+	res.McBlockID = testMasterBlockID(master.SeqNo-1, 1)
+	res.ClientMcStateProof, res.McBlockProof = header, header
+	if err = checkLookupBlockResult(res, master, lookupBySeqno, bySeqno, 0, 0); err == nil {
+		t.Fatal("older master block with header instead of state proof should fail")
+	}
+
+	res = result()
+	res.ShardLinks = []ShardBlockLink{{ID: master, Proof: []byte{1, 2, 3}}}
+	if err = checkLookupBlockResult(res, master, lookupBySeqno, bySeqno, 0, 0); err == nil {
+		t.Fatal("invalid shard links should fail")
+	}
+
+	res = result()
+	res.Header = shardHeader
+	if err = checkLookupBlockResult(res, master, lookupBySeqno, bySeqno, 0, 0); err == nil {
+		t.Fatal("header of other block should fail")
+	}
+
+	res = result()
+	res.Header = nil
+	if err = checkLookupBlockResult(res, master, lookupBySeqno, bySeqno, 0, 0); err == nil {
+		t.Fatal("empty header should fail")
+	}
+
+	byTime := &BlockInfoShort{Workchain: master.Workchain, Shard: master.Shard}
+	if err = checkLookupBlockResult(result(), master, lookupByLT, byTime, 32119774000004, 0); err == nil {
+		t.Fatal("lt after found block should fail")
+	}
+
+	if err = checkLookupBlockResult(result(), master, lookupByUtime, byTime, 0, 1666094059); err == nil {
+		t.Fatal("utime after found block should fail")
+	}
+
+	res = result()
+	res.PrevHeader = shardHeader
+	if err = checkLookupBlockResult(res, master, lookupByUtime, byTime, 0, 1666094058); err == nil {
+		t.Fatal("prev header which is not a parent should fail")
+	}
+
+	res = result()
+	res.PrevHeader = header
+	if err = checkLookupBlockResult(res, master, lookupByLT, byTime, 32119774000000, 0); err == nil {
+		t.Fatal("found block itself as prev header should fail")
+	}
+}
+
+func TestAPIClient_FindMasterBlockByUtime(t *testing.T) {
+	ctx := context.Background()
+	master, header := testMasterHeaderBlock(t)
+
+	// only master block 24374597 generated at 1666094058 is known, others are not found
+	var requests []LookupBlock
+	newClient := func(foundByUtime bool) *APIClient {
+		requests = nil
+		return NewAPIClient(&testLiteClient{handler: func(req tl.Serializable) (tl.Serializable, error) {
+			switch r := req.(type) {
+			case LookupBlock:
+				requests = append(requests, r)
+				if (r.Mode == lookupByUtime && foundByUtime) || (r.Mode == lookupBySeqno && uint32(r.ID.Seqno) == master.SeqNo) {
+					return BlockHeader{ID: master, HeaderProof: header}, nil
+				}
+				return LSError{Code: 651, Text: "not found"}, nil
+			case GetMasterchainInf:
+				return MasterchainInfo{Last: master}, nil
+			case GetBlockHeader:
+				return BlockHeader{ID: master, HeaderProof: header}, nil
+			}
+			t.Fatalf("unexpected request %T", req)
+			return nil, nil
+		}}, ProofCheckPolicyUnsafe)
+	}
+
+	id, err := newClient(true).FindMasterBlockByUtime(ctx, 1666094058)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !id.Equals(master) || len(requests) != 1 {
+		t.Fatal("found block should be returned with single lookup", id.SeqNo, len(requests))
+	}
+	if requests[0].ID.Workchain != -1 || requests[0].ID.Shard != masterchainShard || requests[0].UTime != 1666094058 {
+		t.Fatal("incorrect lookup request")
+	}
+
+	if _, err = newClient(true).FindMasterBlockByUtime(ctx, 1666094057); !errors.Is(err, ErrBlockNotFound) {
+		t.Fatal("previous block is unknown, should be not found, got", err)
+	}
+	if len(requests) != 2 || requests[1].Mode != lookupBySeqno || uint32(requests[1].ID.Seqno) != master.SeqNo-1 {
+		t.Fatal("should step back to the previous block")
+	}
+
+	id, err = newClient(false).FindMasterBlockByUtime(ctx, 1666094100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !id.Equals(master) {
+		t.Fatal("last block should be returned when utime is after it")
+	}
+
+	if _, err = newClient(false).FindMasterBlockByUtime(ctx, 1666094000); !errors.Is(err, ErrBlockNotFound) {
+		t.Fatal("utime before last block without lookup result should be not found, got", err)
+	}
+}
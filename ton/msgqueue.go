@@ -0,0 +1,376 @@
+package ton
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/alan890104/tonutils-go/address"
+	"github.com/alan890104/tonutils-go/tl"
+	"github.com/alan890104/tonutils-go/tlb"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+func init() {
+	tl.Register(GetOutMsgQueueSizes{}, "liteServer.getOutMsgQueueSizes mode:# wc:mode.0?int shard:mode.0?long = liteServer.OutMsgQueueSizes")
+	tl.Register(OutMsgQueueSize{}, "liteServer.outMsgQueueSize id:tonNode.blockIdExt size:int = liteServer.OutMsgQueueSize")
+	tl.Register(OutMsgQueueSizes{}, "liteServer.outMsgQueueSizes shards:(vector liteServer.outMsgQueueSize) ext_msg_queue_size_limit:int = liteServer.OutMsgQueueSizes")
+	tl.Register(GetBlockOutMsgQueueSize{}, "liteServer.getBlockOutMsgQueueSize mode:# id:tonNode.blockIdExt want_proof:mode.0?true = liteServer.BlockOutMsgQueueSize")
+	tl.Register(BlockOutMsgQueueSize{}, "liteServer.blockOutMsgQueueSize mode:# id:tonNode.blockIdExt size:long proof:mode.0?bytes = liteServer.BlockOutMsgQueueSize")
+
+	tl.Register(GetDispatchQueueInfo{}, "liteServer.getDispatchQueueInfo mode:# id:tonNode.blockIdExt after_addr:mode.1?int256 max_accounts:int want_proof:mode.0?true = liteServer.DispatchQueueInfo")
+	tl.Register(AccountDispatchQueueInfo{}, "liteServer.accountDispatchQueueInfo addr:int256 size:long min_lt:long max_lt:long = liteServer.AccountDispatchQueueInfo")
+	tl.Register(DispatchQueueInfo{}, "liteServer.dispatchQueueInfo mode:# id:tonNode.blockIdExt account_dispatch_queues:(vector liteServer.accountDispatchQueueInfo) complete:Bool proof:mode.0?bytes = liteServer.DispatchQueueInfo")
+	tl.Register(GetDispatchQueueMessages{}, "liteServer.getDispatchQueueMessages mode:# id:tonNode.blockIdExt addr:int256 after_lt:long max_messages:int want_proof:mode.0?true one_account:mode.1?true messages_boc:mode.2?true = liteServer.DispatchQueueMessages")
+	tl.Register(TransactionMetadata{}, "liteServer.transactionMetadata mode:# depth:int initiator:liteServer.accountId initiator_lt:long = liteServer.TransactionMetadata")
+	tl.Register(DispatchQueueMessage{}, "liteServer.dispatchQueueMessage addr:int256 lt:long hash:int256 metadata:liteServer.transactionMetadata = liteServer.DispatchQueueMessage")
+	tl.Register(DispatchQueueMessages{}, "liteServer.dispatchQueueMessages mode:# id:tonNode.blockIdExt messages:(vector liteServer.dispatchQueueMessage) complete:Bool proof:mode.0?bytes messages_boc:mode.2?bytes = liteServer.DispatchQueueMessages")
+}
+
+type GetOutMsgQueueSizes struct {
+	Mode      uint32 `tl:"flags"`
+	Workchain int32  `tl:"?0 int"`
+	Shard     int64  `tl:"?0 long"`
+}
+
+type OutMsgQueueSize struct {
+	ID   *BlockIDExt `tl:"struct"`
+	Size int32       `tl:"int"`
+}
+
+type OutMsgQueueSizes struct {
+	Shards               []OutMsgQueueSize `tl:"vector struct"`
+	ExtMsgQueueSizeLimit int32             `tl:"int"`
+}
+
+type GetBlockOutMsgQueueSize struct {
+	Mode      uint32      `tl:"flags"`
+	ID        *BlockIDExt `tl:"struct"`
+	WantProof *True       `tl:"?0 struct"`
+}
+
+type BlockOutMsgQueueSize struct {
+	Mode  uint32      `tl:"flags"`
+	ID    *BlockIDExt `tl:"struct"`
+	Size  uint64      `tl:"long"`
+	Proof []byte      `tl:"?0 bytes"`
+}
+
+type GetDispatchQueueInfo struct {
+	Mode        uint32      `tl:"flags"`
+	ID          *BlockIDExt `tl:"struct"`
+	AfterAddr   []byte      `tl:"?1 int256"`
+	MaxAccounts int32       `tl:"int"`
+	WantProof   *True       `tl:"?0 struct"`
+}
+
+type AccountDispatchQueueInfo struct {
+	Addr  []byte `tl:"int256"`
+	Size  uint64 `tl:"long"`
+	MinLT uint64 `tl:"long"`
+	MaxLT uint64 `tl:"long"`
+}
+
+type DispatchQueueInfo struct {
+	Mode                  uint32                     `tl:"flags"`
+	ID                    *BlockIDExt                `tl:"struct"`
+	AccountDispatchQueues []AccountDispatchQueueInfo `tl:"vector struct"`
+	Complete              bool                       `tl:"bool"`
+	Proof                 []byte                     `tl:"?0 bytes"`
+}
+
+type GetDispatchQueueMessages struct {
+	Mode        uint32      `tl:"flags"`
+	ID          *BlockIDExt `tl:"struct"`
+	Addr        []byte      `tl:"int256"`
+	AfterLT     uint64      `tl:"long"`
+	MaxMessages int32       `tl:"int"`
+	WantProof   *True       `tl:"?0 struct"`
+	OneAccount  *True       `tl:"?1 struct"`
+	MessagesBOC *True       `tl:"?2 struct"`
+}
+
+type TransactionMetadata struct {
+	Mode        uint32     `tl:"flags"`
+	Depth       int32      `tl:"int"`
+	Initiator   *AccountID `tl:"struct"`
+	InitiatorLT uint64     `tl:"long"`
+}
+
+type DispatchQueueMessage struct {
+	Addr     []byte               `tl:"int256"`
+	LT       uint64               `tl:"long"`
+	Hash     []byte               `tl:"int256"`
+	Metadata *TransactionMetadata `tl:"struct"`
+}
+
+type DispatchQueueMessages struct {
+	Mode        uint32                 `tl:"flags"`
+	ID          *BlockIDExt            `tl:"struct"`
+	Messages    []DispatchQueueMessage `tl:"vector struct"`
+	Complete    bool                   `tl:"bool"`
+	Proof       []byte                 `tl:"?0 bytes"`
+	MessagesBOC []byte                 `tl:"?2 bytes"`
+}
+
+// GetOutMsgQueueSizes - gets out message queue sizes of the latest blocks of all shards
+func (c *APIClient) GetOutMsgQueueSizes(ctx context.Context) (*OutMsgQueueSizes, error) {
+	return c.getOutMsgQueueSizes(ctx, GetOutMsgQueueSizes{})
+}
+
+// GetShardOutMsgQueueSizes - same as GetOutMsgQueueSizes, but only for the given shard
+func (c *APIClient) GetShardOutMsgQueueSizes(ctx context.Context, workchain int32, shard int64) (*OutMsgQueueSizes, error) {
+	return c.getOutMsgQueueSizes(ctx, GetOutMsgQueueSizes{Mode: 1, Workchain: workchain, Shard: shard})
+}
+
+func (c *APIClient) getOutMsgQueueSizes(ctx context.Context, req GetOutMsgQueueSizes) (*OutMsgQueueSizes, error) {
+	var resp tl.Serializable
+	err := c.client.QueryLiteserver(ctx, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := resp.(type) {
+	case OutMsgQueueSizes:
+		return &t, nil
+	case LSError:
+		return nil, t
+	}
+	return nil, errUnexpectedResponse(resp)
+}
+
+// GetBlockOutMsgQueueSize - gets out message queue size of the block, size is verified with state proof
+func (c *APIClient) GetBlockOutMsgQueueSize(ctx context.Context, block *BlockIDExt) (uint64, error) {
+	req := GetBlockOutMsgQueueSize{ID: block}
+	if c.proofCheckPolicy != ProofCheckPolicyUnsafe {
+		req.Mode = 1
+		req.WantProof = &True{}
+	}
+
+	var resp tl.Serializable
+	err := c.client.QueryLiteserver(ctx, req, &resp)
+	if err != nil {
+		return 0, err
+	}
+
+	switch t := resp.(type) {
+	case BlockOutMsgQueueSize:
+		if !t.ID.Equals(block) {
+			return 0, fmt.Errorf("incorrect block in response")
+		}
+
+		if c.proofCheckPolicy != ProofCheckPolicyUnsafe {
+			info, err := checkOutMsgQueueInfoProof(block, t.Proof)
+			if err != nil {
+				return 0, err
+			}
+
+			if info.Extra == nil || info.Extra.OutQueueSize == nil {
+				return 0, fmt.Errorf("out queue size is not stored in the block state, it cannot be proven")
+			}
+
+			if *info.Extra.OutQueueSize != t.Size {
+				return 0, fmt.Errorf("out queue size not matches proof")
+			}
+		}
+		return t.Size, nil
+	case LSError:
+		return 0, t
+	}
+	return 0, errUnexpectedResponse(resp)
+}
+
+// GetDispatchQueueInfo - gets dispatch queues of accounts in the block, sorted by address.
+// afterAddr can be nil, when set only accounts with greater address are returned.
+func (c *APIClient) GetDispatchQueueInfo(ctx context.Context, block *BlockIDExt, afterAddr []byte, maxAccounts int32) (*DispatchQueueInfo, error) {
+	req := GetDispatchQueueInfo{ID: block, MaxAccounts: maxAccounts}
+	if afterAddr != nil {
+		req.Mode |= 1 << 1
+		req.AfterAddr = afterAddr
+	}
+	if c.proofCheckPolicy != ProofCheckPolicyUnsafe {
+		req.Mode |= 1
+		req.WantProof = &True{}
+	}
+
+	var resp tl.Serializable
+	err := c.client.QueryLiteserver(ctx, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := resp.(type) {
+	case DispatchQueueInfo:
+		if !t.ID.Equals(block) {
+			return nil, fmt.Errorf("incorrect block in response")
+		}
+
+		if c.proofCheckPolicy != ProofCheckPolicyUnsafe {
+			info, err := checkOutMsgQueueInfoProof(block, t.Proof)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, acc := range t.AccountDispatchQueues {
+				minLT, queue, err := loadAccountDispatchQueue(info, acc.Addr)
+				if err != nil {
+					return nil, fmt.Errorf("failed to load dispatch queue of %s from proof: %w", hex.EncodeToString(acc.Addr), err)
+				}
+
+				if queue.Count != acc.Size || minLT != acc.MinLT {
+					return nil, fmt.Errorf("dispatch queue of %s not matches proof", hex.EncodeToString(acc.Addr))
+				}
+			}
+		}
+		return &t, nil
+	case LSError:
+		return nil, t
+	}
+	return nil, errUnexpectedResponse(resp)
+}
+
+// GetDispatchQueueMessages - gets messages from dispatch queue of the account, starting after the given lt.
+// When oneAccount is false, messages of the next accounts are also returned if limit allows.
+func (c *APIClient) GetDispatchQueueMessages(ctx context.Context, block *BlockIDExt, addr *address.Address, afterLT uint64, maxMessages int32, oneAccount bool) (*DispatchQueueMessages, error) {
+	req := GetDispatchQueueMessages{
+		Mode:        1 << 2,
+		ID:          block,
+		Addr:        addr.Data(),
+		AfterLT:     afterLT,
+		MaxMessages: maxMessages,
+		MessagesBOC: &True{},
+	}
+	if oneAccount {
+		req.Mode |= 1 << 1
+		req.OneAccount = &True{}
+	}
+	if c.proofCheckPolicy != ProofCheckPolicyUnsafe {
+		req.Mode |= 1
+		req.WantProof = &True{}
+	}
+
+	var resp tl.Serializable
+	err := c.client.QueryLiteserver(ctx, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := resp.(type) {
+	case DispatchQueueMessages:
+		if !t.ID.Equals(block) {
+			return nil, fmt.Errorf("incorrect block in response")
+		}
+
+		if len(t.Messages) > 0 {
+			msgs, err := cell.FromBOCMultiRoot(t.MessagesBOC)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse messages boc: %w", err)
+			}
+
+			if len(msgs) != len(t.Messages) {
+				return nil, fmt.Errorf("messages num not matches")
+			}
+
+			for i, msg := range msgs {
+				if !bytes.Equal(msg.Hash(), t.Messages[i].Hash) {
+					return nil, fmt.Errorf("message %d hash not matches", i)
+				}
+			}
+		}
+
+		if c.proofCheckPolicy != ProofCheckPolicyUnsafe {
+			info, err := checkOutMsgQueueInfoProof(block, t.Proof)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, msg := range t.Messages {
+				if oneAccount && !bytes.Equal(msg.Addr, addr.Data()) {
+					return nil, fmt.Errorf("message of another account in response")
+				}
+
+				_, queue, err := loadAccountDispatchQueue(info, msg.Addr)
+				if err != nil {
+					return nil, fmt.Errorf("failed to load dispatch queue of %s from proof: %w", hex.EncodeToString(msg.Addr), err)
+				}
+
+				if queue.Messages == nil {
+					return nil, fmt.Errorf("no messages in dispatch queue proof of %s", hex.EncodeToString(msg.Addr))
+				}
+
+				val, err := queue.Messages.LoadValue(cell.BeginCell().MustStoreUInt(msg.LT, 64).EndCell())
+				if err != nil {
+					return nil, fmt.Errorf("message %d not found in proof: %w", msg.LT, err)
+				}
+
+				if _, err = val.LoadUInt(64); err != nil {
+					return nil, fmt.Errorf("failed to load enqueued lt: %w", err)
+				}
+
+				env, err := val.LoadRefCell()
+				if err != nil {
+					return nil, fmt.Errorf("failed to load message envelope: %w", err)
+				}
+
+				msgCell, err := env.PeekRef(0)
+				if err != nil {
+					return nil, fmt.Errorf("failed to load message from envelope: %w", err)
+				}
+
+				if !bytes.Equal(msgCell.Hash(0), msg.Hash) {
+					return nil, fmt.Errorf("message %d hash not matches proof", msg.LT)
+				}
+			}
+		}
+		return &t, nil
+	case LSError:
+		return nil, t
+	}
+	return nil, errUnexpectedResponse(resp)
+}
+
+func checkOutMsgQueueInfoProof(block *BlockIDExt, proof []byte) (*tlb.OutMsgQueueInfo, error) {
+	if len(proof) == 0 {
+		return nil, fmt.Errorf("no proof passed by ls")
+	}
+
+	roots, err := cell.FromBOCMultiRoot(proof)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse proof boc: %w", err)
+	}
+
+	state, err := CheckBlockShardStateProof(roots, block.RootHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check state proof: %w", err)
+	}
+
+	info, err := state.LoadOutMsgQueueInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load out msg queue info from proof: %w", err)
+	}
+	return info, nil
+}
+
+func loadAccountDispatchQueue(info *tlb.OutMsgQueueInfo, addr []byte) (uint64, *tlb.AccountDispatchQueue, error) {
+	if info.Extra == nil || info.Extra.DispatchQueue.Accounts == nil {
+		return 0, nil, errors.New("no dispatch queue in state")
+	}
+
+	val, err := info.Extra.DispatchQueue.Accounts.LoadValue(cell.BeginCell().MustStoreSlice(addr, 256).EndCell())
+	if err != nil {
+		return 0, nil, err
+	}
+
+	minLT, err := val.LoadUInt(64)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to load min lt: %w", err)
+	}
+
+	var queue tlb.AccountDispatchQueue
+	if err = tlb.LoadFromCell(&queue, val); err != nil {
+		return 0, nil, fmt.Errorf("failed to load account dispatch queue: %w", err)
+	}
+	return minLT, &queue, nil
+}
@@ -0,0 +1,61 @@
+package ton
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/alan890104/tonutils-go/tlb"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+func TestLoadAccountDispatchQueue(t *testing.T) {
+	addr := bytes.Repeat([]byte{0xAA}, 32)
+
+	accQueue, err := tlb.ToCell(tlb.AccountDispatchQueue{Messages: cell.NewDict(64), Count: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dispatch := cell.NewDict(256)
+	if err = dispatch.Set(cell.BeginCell().MustStoreSlice(addr, 256).EndCell(),
+		cell.BeginCell().MustStoreUInt(1000, 64).MustStoreBuilder(accQueue.ToBuilder()).EndCell()); err != nil {
+		t.Fatal(err)
+	}
+
+	info := &tlb.OutMsgQueueInfo{
+		Extra: &tlb.OutMsgQueueExtra{
+			DispatchQueue: tlb.DispatchQueue{Accounts: dispatch, MinLT: 1000},
+		},
+	}
+
+	minLT, queue, err := loadAccountDispatchQueue(info, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if minLT != 1000 || queue.Count != 3 {
+		t.Fatal("incorrect dispatch queue", minLT, queue.Count)
+	}
+
+	if _, _, err = loadAccountDispatchQueue(info, bytes.Repeat([]byte{0xBB}, 32)); !errors.Is(err, cell.ErrNoSuchKeyInDict) {
+		t.Fatal("not existing account should fail with no key error, got", err)
+	}
+
+	if _, _, err = loadAccountDispatchQueue(&tlb.OutMsgQueueInfo{}, addr); err == nil {
+		t.Fatal("state without extra should fail")
+	}
+
+	if _, _, err = loadAccountDispatchQueue(&tlb.OutMsgQueueInfo{Extra: &tlb.OutMsgQueueExtra{}}, addr); err == nil {
+		t.Fatal("empty dispatch queue should fail")
+	}
+
+	broken := cell.NewDict(256)
+	if err = broken.Set(cell.BeginCell().MustStoreSlice(addr, 256).EndCell(),
+		cell.BeginCell().MustStoreUInt(1000, 32).EndCell()); err != nil {
+		t.Fatal(err)
+	}
+	info.Extra.DispatchQueue.Accounts = broken
+	if _, _, err = loadAccountDispatchQueue(info, addr); err == nil {
+		t.Fatal("broken queue value should fail")
+	}
+}
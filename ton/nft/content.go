@@ -4,9 +4,25 @@ import (
 	"crypto/sha256"
 	"fmt"
 
+	"github.com/alan890104/tonutils-go/ton"
 	"github.com/alan890104/tonutils-go/tvm/cell"
 )
 
+func init() {
+	// allows to decode content fields of get method results using ExecutionResult.Decode
+	ton.RegisterStackDecoder(func(v any) (ContentAny, error) {
+		switch val := v.(type) {
+		case nil:
+			return nil, nil
+		case *cell.Cell:
+			return ContentFromCell(val)
+		case *cell.Slice:
+			return ContentFromSlice(val.Copy())
+		}
+		return nil, fmt.Errorf("%w: %T cannot be converted to content", ton.ErrIncorrectResultType, v)
+	})
+}
+
 type ContentAny interface {
 	ContentCell() (*cell.Cell, error)
 }
@@ -0,0 +1,95 @@
+package ton
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/alan890104/tonutils-go/tl"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+func TestAPIClient_GetNonfinalCandidate(t *testing.T) {
+	root := cell.BeginCell().MustStoreUInt(0x11ef55aa, 32).EndCell()
+	data := root.ToBOC()
+	collated := []byte("collated")
+
+	fileHash := sha256.Sum256(data)
+	collatedHash := sha256.Sum256(collated)
+
+	id := &NonfinalCandidateID{
+		BlockID:          testBlockID(0, 0x8000000000000000, 10, 0),
+		Creator:          make([]byte, 32),
+		CollatedDataHash: collatedHash[:],
+	}
+	id.BlockID.RootHash = root.Hash()
+	id.BlockID.FileHash = fileHash[:]
+
+	for _, tt := range []struct {
+		name   string
+		modify func(id *NonfinalCandidateID, resp *NonfinalCandidate)
+		fail   bool
+	}{
+		{name: "valid", modify: func(id *NonfinalCandidateID, resp *NonfinalCandidate) {}},
+		{name: "other block", fail: true, modify: func(id *NonfinalCandidateID, resp *NonfinalCandidate) {
+			resp.ID = &NonfinalCandidateID{BlockID: testBlockID(0, 0x8000000000000000, 11, 0)}
+		}},
+		{name: "no id", fail: true, modify: func(id *NonfinalCandidateID, resp *NonfinalCandidate) {
+			resp.ID = nil
+		}},
+		{name: "data not matches file hash", fail: true, modify: func(id *NonfinalCandidateID, resp *NonfinalCandidate) {
+			resp.Data = cell.BeginCell().MustStoreUInt(1, 32).EndCell().ToBOC()
+		}},
+		{name: "collated data not matches hash", fail: true, modify: func(id *NonfinalCandidateID, resp *NonfinalCandidate) {
+			resp.CollatedData = []byte("other")
+		}},
+		{name: "not a boc", fail: true, modify: func(id *NonfinalCandidateID, resp *NonfinalCandidate) {
+			resp.Data = []byte("not a boc")
+			hash := sha256.Sum256(resp.Data)
+			id.BlockID.FileHash = hash[:]
+		}},
+		{name: "root not matches hash", fail: true, modify: func(id *NonfinalCandidateID, resp *NonfinalCandidate) {
+			resp.Data = cell.BeginCell().MustStoreUInt(1, 32).EndCell().ToBOC()
+			hash := sha256.Sum256(resp.Data)
+			id.BlockID.FileHash = hash[:]
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			reqID := *id
+			blockID := *id.BlockID
+			reqID.BlockID = &blockID
+
+			resp := NonfinalCandidate{ID: &reqID, Data: data, CollatedData: collated}
+			tt.modify(&reqID, &resp)
+
+			c := NewAPIClient(&testLiteClient{handler: func(req tl.Serializable) (tl.Serializable, error) {
+				if _, ok := req.(GetNonfinalCandidate); !ok {
+					t.Fatal("unexpected request", req)
+				}
+				return resp, nil
+			}})
+
+			res, err := c.GetNonfinalCandidate(context.Background(), &reqID)
+			if tt.fail {
+				if err == nil {
+					t.Fatal("should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(res.Hash(), root.Hash()) {
+				t.Fatal("incorrect candidate root")
+			}
+		})
+	}
+
+	c := NewAPIClient(&testLiteClient{handler: func(req tl.Serializable) (tl.Serializable, error) {
+		return LSError{Code: 651, Text: "not found"}, nil
+	}})
+	if _, err := c.GetNonfinalCandidate(context.Background(), id); err == nil {
+		t.Fatal("ls error should be returned")
+	}
+}
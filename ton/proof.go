@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"hash/crc32"
+	"log"
 	"math/big"
 	"reflect"
 	"sort"
@@ -37,12 +38,7 @@ func CheckShardMcStateExtraProof(master *BlockIDExt, shardProof []*cell.Cell) (*
 		return nil, fmt.Errorf("not a masterchain block")
 	}
 
-	var stateExtra tlb.McStateExtra
-	err = tlb.LoadFromCell(&stateExtra, shardState.McStateExtra.BeginParse())
-	if err != nil {
-		return nil, fmt.Errorf("failed to load masterchain state extra: %w", err)
-	}
-	return &stateExtra, nil
+	return shardState.LoadMcStateExtra()
 }
 
 func CheckShardInMasterProof(master *BlockIDExt, shardProof []*cell.Cell, workchain int32, shardRootHash []byte) error {
@@ -74,6 +70,10 @@ func CheckBlockShardStateProof(proof []*cell.Cell, blockRootHash []byte) (*tlb.S
 		return nil, fmt.Errorf("incorrect block proof: %w", err)
 	}
 
+	if block.StateUpdate == nil {
+		return nil, fmt.Errorf("no state update in block proof")
+	}
+
 	upd, err := block.StateUpdate.PeekRef(1)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load state update ref: %w", err)
@@ -209,6 +209,78 @@ func CheckTransactionProof(txHash []byte, txLT uint64, txAccount []byte, shardAc
 	return nil
 }
 
+// CheckShardBlockProof - verifies chain of links from proof.MasterchainID to the target block.
+// First link proves that shard top block is committed in master block,
+// next links go back through previous blocks of the shard until the target block.
+func CheckShardBlockProof(proof *ShardBlockProof, target *BlockIDExt) error {
+	if proof.MasterchainID == nil || proof.MasterchainID.Workchain != address.MasterchainID {
+		return fmt.Errorf("proof should start from masterchain block")
+	}
+
+	cur := proof.MasterchainID
+	for i, link := range proof.Links {
+		if link.ID == nil {
+			return fmt.Errorf("link %d has no block id", i)
+		}
+
+		linkProof, err := cell.FromBOC(link.Proof)
+		if err != nil {
+			return fmt.Errorf("failed to parse link %d proof boc: %w", i, err)
+		}
+
+		blk, err := CheckBlockProof(linkProof, cur.RootHash)
+		if err != nil {
+			return fmt.Errorf("failed to check link %d proof: %w", i, err)
+		}
+
+		if i == 0 {
+			if blk.Extra == nil || blk.Extra.Custom == nil {
+				return fmt.Errorf("no shard hashes in master block proof")
+			}
+
+			shards, err := LoadShardsFromHashes(blk.Extra.Custom.ShardHashes, true)
+			if err != nil {
+				return fmt.Errorf("failed to load shard hashes from proof: %w", err)
+			}
+
+			found := false
+			for _, shard := range shards {
+				if shard.Equals(link.ID) {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				return fmt.Errorf("shard block %d not found in master block %d", link.ID.SeqNo, cur.SeqNo)
+			}
+		} else {
+			parents, err := blk.BlockInfo.GetParentBlocks()
+			if err != nil {
+				return fmt.Errorf("failed to get parents of block %d: %w", cur.SeqNo, err)
+			}
+
+			found := false
+			for _, parent := range parents {
+				if parent.Equals(link.ID) {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				return fmt.Errorf("block %d is not a parent of %d", link.ID.SeqNo, cur.SeqNo)
+			}
+		}
+		cur = link.ID
+	}
+
+	if !cur.Equals(target) {
+		return fmt.Errorf("proof is not for the target block")
+	}
+	return nil
+}
+
 func CheckBackwardBlockProof(from, to *BlockIDExt, toKey bool, stateProof, destProof, proof *cell.Cell) error {
 	if from.Workchain != address.MasterchainID || to.Workchain != address.MasterchainID {
 		return fmt.Errorf("both blocks should be from masterchain")
@@ -232,34 +304,16 @@ func CheckBackwardBlockProof(from, to *BlockIDExt, toKey bool, stateProof, destP
 		return fmt.Errorf("failed to check proof for mc state extra: %w", err)
 	}
 
-	var info tlb.McStateExtraBlockInfo
-	err = tlb.LoadFromCellAsProof(&info, stateExtra.Info.BeginParse())
-	if err != nil {
-		return fmt.Errorf("failed to load tx CurrencyCollection proof cell: %w", err)
-	}
-
-	toInfo := info.PrevBlocks.GetByIntKey(big.NewInt(int64(to.SeqNo)))
-	if toInfo == nil {
-		return fmt.Errorf("target block not found in state proof")
-	}
-
-	slc := toInfo.BeginParse()
-	err = tlb.LoadFromCellAsProof(new(tlb.KeyMaxLt), slc)
-	if err != nil {
-		return fmt.Errorf("failed to load block KeyMaxLt proof cell: %w", err)
-	}
-
-	var blk tlb.KeyExtBlkRef
-	err = tlb.LoadFromCellAsProof(&blk, slc)
+	blk, err := stateExtra.Info.PrevBlocks.Get(to.SeqNo)
 	if err != nil {
-		return fmt.Errorf("failed to load block KeyExtBlkRef proof cell: %w", err)
+		return fmt.Errorf("target block not found in state proof: %w", err)
 	}
 
-	if blk.IsKey != toKey {
+	if blk.Block.IsKey != toKey {
 		return fmt.Errorf("target block type in proof not matches requested")
 	}
 
-	if !bytes.Equal(blk.BlkRef.RootHash, to.RootHash) {
+	if !bytes.Equal(blk.Block.BlkRef.RootHash, to.RootHash) {
 		return fmt.Errorf("incorrect target block hash in proof")
 	}
 	return nil
@@ -535,6 +589,18 @@ func calcValidatorSetHash(ccSeqno uint32, validators []*tlb.ValidatorAddr) (uint
 func (c *APIClient) VerifyProofChain(ctx context.Context, from, to *BlockIDExt) error {
 	isForward := to.SeqNo > from.SeqNo
 
+	if store := c.getTrustStore(); isForward && store != nil {
+		cp, err := store.GetLatest(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get latest checkpoint: %w", err)
+		}
+
+		// resume from already verified key block
+		if cp != nil && cp.Block.SeqNo > from.SeqNo && cp.Block.SeqNo <= to.SeqNo {
+			from = cp.Block
+		}
+	}
+
 	for from.SeqNo != to.SeqNo {
 		part, err := c.GetBlockProof(ctx, from, to)
 		if err != nil {
@@ -607,6 +673,10 @@ func (c *APIClient) VerifyProofChain(ctx context.Context, from, to *BlockIDExt)
 				if err != nil {
 					return fmt.Errorf("invalid forward block from %d to %d proof: %w", fwd.From.SeqNo, fwd.To.SeqNo, err)
 				}
+
+				if fwd.ToKeyBlock {
+					c.saveCheckpoint(ctx, fwd.To)
+				}
 				from = fwd.To
 			}
 		} else {
@@ -629,3 +699,16 @@ func (c *APIClient) VerifyProofChain(ctx context.Context, from, to *BlockIDExt)
 	}
 	return nil
 }
+
+// saveCheckpoint - stores verified key block in trust store, if it is set.
+// Proof chain is already valid at this point, so store failure is only reported.
+func (c *APIClient) saveCheckpoint(ctx context.Context, keyBlock *BlockIDExt) {
+	store := c.getTrustStore()
+	if store == nil {
+		return
+	}
+
+	if err := store.Save(ctx, &TrustedCheckpoint{Block: keyBlock.Copy()}); err != nil {
+		log.Println("[WARNING] failed to save key block", keyBlock.SeqNo, "checkpoint to trust store:", err.Error())
+	}
+}
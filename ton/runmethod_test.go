@@ -1,10 +1,17 @@
 package ton
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
 	"math/big"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/alan890104/tonutils-go/address"
+	"github.com/alan890104/tonutils-go/tl"
 	"github.com/alan890104/tonutils-go/tlb"
 	"github.com/alan890104/tonutils-go/tvm/cell"
 )
@@ -102,3 +109,301 @@ func TestExecutionResult(t *testing.T) {
 		t.Fatal("as tuple wrong")
 	}
 }
+
+type testStackPoint struct {
+	X int32
+	Y uint8
+}
+
+type testStackResult struct {
+	Supply   *big.Int         `stack:"0"`
+	Enabled  bool             `stack:"1"`
+	Owner    *address.Address `stack:"2"`
+	Balance  tlb.Coins        `stack:"3"`
+	Code     *cell.Cell       `stack:"4"`
+	Point    testStackPoint   `stack:"5"`
+	Points   []testStackPoint `stack:"6"`
+	Init     *tlb.StateInit   `stack:"7"`
+	Seqno    uint32
+	Missing  *big.Int `stack:"20,optional"`
+	Skipped  int      `stack:"-"`
+	internal int
+}
+
+func TestExecutionResult_Decode(t *testing.T) {
+	addr := address.MustParseAddr("EQC9bWZd29foipyPOGWlVNVCQzpGAjvi1rGWF7EbNcSVClpA")
+
+	src := testStackResult{
+		Supply:  big.NewInt(1000),
+		Enabled: true,
+		Owner:   addr,
+		Balance: tlb.MustFromTON("1.5"),
+		Code:    cell.BeginCell().MustStoreUInt(7, 8).EndCell(),
+		Point:   testStackPoint{X: -5, Y: 3},
+		Points:  []testStackPoint{{X: 1, Y: 2}, {X: 3, Y: 4}},
+		Init:    &tlb.StateInit{Code: cell.BeginCell().EndCell()},
+		Seqno:   77,
+	}
+
+	params, err := EncodeGetMethodParams(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(params) != 9 {
+		t.Fatal("incorrect params len", len(params))
+	}
+	if params[1].(*big.Int).Int64() != -1 {
+		t.Fatal("bool should be encoded as -1")
+	}
+	if _, ok := params[2].(*cell.Slice); !ok {
+		t.Fatal("address should be encoded as slice")
+	}
+
+	// pass through stack serialization, same as with real get method
+	var st tlb.Stack
+	for i := len(params) - 1; i >= 0; i-- {
+		st.Push(params[i])
+	}
+	stCell, err := st.ToCell()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = st.LoadFromCell(stCell.BeginParse()); err != nil {
+		t.Fatal(err)
+	}
+	var values []any
+	for st.Depth() > 0 {
+		v, _ := st.Pop()
+		values = append(values, v)
+	}
+
+	var dst testStackResult
+	if err = NewExecutionResult(values).Decode(&dst); err != nil {
+		t.Fatal(err)
+	}
+
+	dst.Skipped, src.Skipped = 0, 0
+	if dst.Supply.Cmp(src.Supply) != 0 || !dst.Enabled || !dst.Owner.Equals(addr) ||
+		dst.Balance.Nano().Cmp(src.Balance.Nano()) != 0 || !reflect.DeepEqual(dst.Point, src.Point) ||
+		!reflect.DeepEqual(dst.Points, src.Points) || dst.Seqno != 77 || dst.Missing != nil ||
+		string(dst.Code.Hash()) != string(src.Code.Hash()) || dst.Init == nil || dst.Init.Code == nil {
+		t.Fatal("incorrect decoded result")
+	}
+
+	var overflow struct {
+		V int8
+	}
+	if err = NewExecutionResult([]any{big.NewInt(300)}).Decode(&overflow); err == nil {
+		t.Fatal("should be overflow error")
+	}
+
+	var wrong struct {
+		V *address.Address
+	}
+	if err = NewExecutionResult([]any{big.NewInt(1)}).Decode(&wrong); !errors.Is(err, ErrIncorrectResultType) {
+		t.Fatal("should be incorrect type error", err)
+	}
+
+	if err = NewExecutionResult(nil).Decode(&wrong); !errors.Is(err, ErrResultIndexOutOfRange) {
+		t.Fatal("should be out of range error", err)
+	}
+}
+
+func TestRunGetMethodVerified_Helpers(t *testing.T) {
+	cfg := &BlockchainConfig{data: map[int32]*cell.Cell{
+		0:  cell.BeginCell().MustStoreUInt(1, 8).EndCell(),
+		34: cell.BeginCell().MustStoreUInt(2, 8).EndCell(),
+	}}
+
+	root, err := buildConfigDict(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dict := root.AsDict(32)
+	v, err := dict.LoadValueByIntKey(big.NewInt(34))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.MustLoadRef().MustLoadUInt(8) != 2 {
+		t.Fatal("incorrect config param")
+	}
+
+	code := cell.BeginCell().MustStoreRef(cell.BeginCell().MustStoreUInt(7, 8).EndCell()).EndCell()
+	if len(collectLibraryHashes(code, nil)) != 0 {
+		t.Fatal("should be no libraries")
+	}
+
+	addr := address.MustParseAddr("EQBL2_3lMiyywU17g-or8N7v9hDmPCpttzBPE2isF2GTzpK4")
+	_, err = NewAPIClient(nil).RunGetMethodVerified(context.Background(), nil, addr, "seqno")
+	if !errors.Is(err, ErrNoTVMExecutor) {
+		t.Fatal("should fail without executor", err)
+	}
+}
+
+type testTVMExecutor struct {
+	req *LocalGetMethodRequest
+}
+
+func (e *testTVMExecutor) RunGetMethod(_ context.Context, req *LocalGetMethodRequest) (int32, *tlb.Stack, error) {
+	e.req = req
+
+	res := tlb.NewStack()
+	res.Push(big.NewInt(7))
+	return 0, res, nil
+}
+
+func TestRunGetMethodVerified_LocalRequest(t *testing.T) {
+	addr := address.MustParseAddr("EQBL2_3lMiyywU17g-or8N7v9hDmPCpttzBPE2isF2GTzpK4")
+	code := cell.BeginCell().MustStoreUInt(1, 8).EndCell()
+	data := cell.BeginCell().MustStoreUInt(2, 8).EndCell()
+
+	storagePrice := func(since uint32) *cell.Cell {
+		return cell.BeginCell().MustStoreUInt(0xcc, 8).MustStoreUInt(uint64(since), 32).MustStoreUInt(uint64(since), 64).EndCell()
+	}
+	prices := cell.NewDict(32)
+	for i, since := range []uint32{100, 200, 5000} {
+		if err := prices.SetIntKey(big.NewInt(int64(i)), storagePrice(since)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &BlockchainConfig{data: map[int32]*cell.Cell{
+		18: prices.AsCell(),
+		19: cell.BeginCell().MustStoreUInt(239, 32).EndCell(),
+	}}
+
+	libs := cell.NewDict(256)
+	if err := libs.Set(cell.BeginCell().MustStoreSlice(code.Hash(), 256).EndCell(), cell.BeginCell().MustStoreRef(code).EndCell()); err != nil {
+		t.Fatal(err)
+	}
+
+	due := tlb.MustFromTON("0.5")
+	acc := &tlb.Account{
+		IsActive: true,
+		Code:     code,
+		Data:     data,
+		State: &tlb.AccountState{
+			StorageInfo:    tlb.StorageInfo{DuePayment: &due},
+			AccountStorage: tlb.AccountStorage{Balance: tlb.MustFromTON("3")},
+		},
+	}
+
+	blk := &tlb.Block{Extra: &tlb.BlockExtra{RandSeed: make([]byte, 32)}}
+	blk.BlockInfo.GenUtime = 1000
+	blk.BlockInfo.StartLt = 5000
+	blk.Extra.RandSeed[0] = 1
+
+	prevBlocks := []any{[]any{blockIDTuple(testMasterBlockID(10, 1))}, blockIDTuple(testMasterBlockID(5, 2))}
+
+	req, err := buildLocalGetMethodRequest(blk, acc, addr, cfg, libs, prevBlocks, "get_sum", []any{big.NewInt(1), big.NewInt(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	executor := &testTVMExecutor{}
+	res, err := runLocalGetMethod(context.Background(), executor, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.MustInt(0).Uint64() != 7 {
+		t.Fatal("incorrect result")
+	}
+
+	got := executor.req
+	if got.Code != code || got.Data != data || got.Libraries != libs || got.MethodID != tlb.MethodNameHash("get_sum") {
+		t.Fatal("incorrect code, data, libraries or method")
+	}
+
+	first, _ := got.Stack.Pop()
+	second, _ := got.Stack.Pop()
+	if first.(*big.Int).Uint64() != 1 || second.(*big.Int).Uint64() != 2 || got.Stack.Depth() != 0 {
+		t.Fatal("first argument should be on top of the stack")
+	}
+
+	c7 := got.C7
+	if len(c7) != 16 || c7[0] != uint32(smcInfoMagic) || c7[3] != uint32(1000) || c7[4].(*big.Int).Uint64() != 5000 {
+		t.Fatal("incorrect c7 header fields")
+	}
+
+	seed := sha256.Sum256(append(append([]byte{}, blk.Extra.RandSeed...), addr.Data()...))
+	if !bytes.Equal(c7[6].(*big.Int).FillBytes(make([]byte, 32)), seed[:]) {
+		t.Fatal("seed should be derived from block rand seed and address")
+	}
+
+	if c7[7].([]any)[0].(*big.Int).Cmp(tlb.MustFromTON("3").Nano()) != 0 {
+		t.Fatal("incorrect balance")
+	}
+
+	if !c7[8].(*cell.Slice).MustLoadAddr().Equals(addr) || c7[9] != got.Config || c7[10] != code {
+		t.Fatal("incorrect address, config or code")
+	}
+
+	if c7[11].([]any)[0].(*big.Int).Sign() != 0 || c7[12] != 0 || !reflect.DeepEqual(c7[13], prevBlocks) {
+		t.Fatal("incorrect incoming value, storage fees or prev blocks")
+	}
+
+	unpacked := c7[14].([]any)
+	if len(unpacked) != 7 || unpacked[0].(*cell.Slice).MustLoadUInt(40) != 0xcc000000c8 ||
+		unpacked[1].(*cell.Slice).MustLoadUInt(32) != 239 || unpacked[2] != nil {
+		t.Fatal("incorrect unpacked config, storage prices active at block time should be used")
+	}
+
+	if c7[15].(*big.Int).Cmp(due.Nano()) != 0 {
+		t.Fatal("incorrect due payment")
+	}
+
+	// stack and c7 should be serializable for the emulator
+	c7Stack := tlb.NewStack()
+	c7Stack.Push([]any{c7})
+	if _, err = c7Stack.ToCell(); err != nil {
+		t.Fatal("c7 should be serializable:", err)
+	}
+
+	blk.Extra = nil
+	if _, err = buildLocalGetMethodRequest(blk, acc, addr, cfg, libs, prevBlocks, "get_sum", nil); err == nil {
+		t.Fatal("block without rand seed should fail")
+	}
+
+	c := NewAPIClient(nil)
+	c.WithTimeout(time.Second).SetTVMExecutor(executor)
+	if c.getTVMExecutor() != executor {
+		t.Fatal("executor should be set to root")
+	}
+
+	if _, err = c.RunGetMethodVerified(context.Background(), nil, addr, "seqno"); err == nil {
+		t.Fatal("nil block should fail")
+	}
+}
+
+func TestAPIClient_GetPrevBlocksInfo(t *testing.T) {
+	master := testMasterBlockID(1, 1)
+	key := testMasterBlockID(0, 2)
+
+	c := NewAPIClient(&testLiteClient{handler: func(req tl.Serializable) (tl.Serializable, error) {
+		if r, ok := req.(LookupBlockWithProof); ok && r.ID.Seqno == 0 && r.McBlockID.Equals(master) {
+			return LookupBlockResult{ID: key, McBlockID: master}, nil
+		}
+		t.Fatalf("unexpected request %T", req)
+		return nil, nil
+	}}, ProofCheckPolicyUnsafe)
+
+	var hdr tlb.BlockHeader
+	hdr.SeqNo = 1
+	hdr.PrevRef.Prev1 = tlb.ExtBlkRef{SeqNo: 0, RootHash: key.RootHash, FileHash: key.FileHash}
+
+	info, err := c.getPrevBlocksInfo(context.Background(), master, &hdr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []any{[]any{blockIDTuple(master), blockIDTuple(key)}, blockIDTuple(key)}
+	if !reflect.DeepEqual(info, want) {
+		t.Fatal("incorrect prev blocks info")
+	}
+
+	if shard := info[1].([]any)[1].(*big.Int); shard.Uint64() != 0x8000000000000000 {
+		t.Fatal("shard should be unsigned", shard)
+	}
+}
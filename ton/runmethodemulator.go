@@ -0,0 +1,106 @@
+//go:build emulator && cgo
+
+package ton
+
+/*
+#cgo LDFLAGS: -lemulator
+#include <stdint.h>
+#include <stdlib.h>
+
+const char *tvm_emulator_emulate_run_method(uint32_t len, const char *params_boc, int64_t gas_limit);
+*/
+import "C"
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"unsafe"
+
+	"github.com/alan890104/tonutils-go/tlb"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+// EmulatorTVMExecutor - TVMExecutor which runs get methods with emulator library (libemulator) from ton repository.
+// It is built only with 'emulator' build tag, library should be available for linker.
+type EmulatorTVMExecutor struct{}
+
+func NewEmulatorTVMExecutor() *EmulatorTVMExecutor {
+	return &EmulatorTVMExecutor{}
+}
+
+func (e *EmulatorTVMExecutor) RunGetMethod(ctx context.Context, req *LocalGetMethodRequest) (int32, *tlb.Stack, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, nil, err
+	}
+
+	stack, err := req.Stack.ToCell()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to serialize stack: %w", err)
+	}
+
+	// c7 register is a tuple, smart contract info is its first element
+	c7 := tlb.NewStack()
+	c7.Push([]any{req.C7})
+	c7Cell, err := c7.ToCell()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to serialize c7: %w", err)
+	}
+
+	data := req.Data
+	if data == nil {
+		data = cell.BeginCell().EndCell()
+	}
+
+	libs := cell.BeginCell().EndCell()
+	if !req.Libraries.IsEmpty() {
+		libs = req.Libraries.AsCell()
+	}
+
+	// code:^Cell data:^Cell stack:^VmStack params:^[c7:^VmStack libs:^Cell] method_id:(## 32)
+	params := cell.BeginCell().
+		MustStoreRef(req.Code).
+		MustStoreRef(data).
+		MustStoreRef(stack).
+		MustStoreRef(cell.BeginCell().MustStoreRef(c7Cell).MustStoreRef(libs).EndCell()).
+		MustStoreUInt(req.MethodID, 32).
+		EndCell().ToBOC()
+
+	ptr := C.tvm_emulator_emulate_run_method(C.uint32_t(len(params)),
+		(*C.char)(unsafe.Pointer(&params[0])), C.int64_t(req.GasLimit))
+	if ptr == nil {
+		return 0, nil, fmt.Errorf("emulator failed to run method")
+	}
+	defer C.free(unsafe.Pointer(ptr))
+
+	// result is prefixed with 8 bytes of length in host order
+	sz := binary.LittleEndian.Uint64(C.GoBytes(unsafe.Pointer(ptr), 8))
+	boc := C.GoBytes(unsafe.Add(unsafe.Pointer(ptr), 8), C.int(sz))
+
+	res, err := cell.FromBOC(boc)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to parse emulator result: %w", err)
+	}
+
+	// exit_code:int32 gas_used:int64 stack:^VmStack
+	loader := res.BeginParse()
+	exitCode, err := loader.LoadInt(32)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to load exit code: %w", err)
+	}
+
+	if _, err = loader.LoadUInt(64); err != nil {
+		return 0, nil, fmt.Errorf("failed to load gas used: %w", err)
+	}
+
+	stackRef, err := loader.LoadRef()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to load result stack: %w", err)
+	}
+
+	var result tlb.Stack
+	if err = result.LoadFromCell(stackRef); err != nil {
+		return 0, nil, fmt.Errorf("failed to parse result stack: %w", err)
+	}
+	return int32(exitCode), &result, nil
+}
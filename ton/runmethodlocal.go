@@ -0,0 +1,349 @@
+package ton
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/alan890104/tonutils-go/address"
+	"github.com/alan890104/tonutils-go/tlb"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+var ErrNoTVMExecutor = errors.New("tvm executor is not set, use SetTVMExecutor")
+
+// c7 tuple magic, 'SmartContractInfo'
+const smcInfoMagic = 0x076ef1ea
+
+// default gas limit for get methods, same as liteserver uses
+const defaultGetMethodGasLimit = 1_000_000
+
+// LocalGetMethodRequest - everything needed to execute get method locally,
+// all the data is taken from proven account state, block and config.
+type LocalGetMethodRequest struct {
+	Address  *address.Address
+	Code     *cell.Cell
+	Data     *cell.Cell
+	MethodID uint64
+	// Stack - method params, in the same form as for liteserver request, can be serialized with ToCell
+	Stack *tlb.Stack
+	// C7 - smart contract info tuple, it is the only element of c7 register tuple
+	C7 []any
+	// Libraries - library cells used by the code, HashmapE 256 ^Cell
+	Libraries *cell.Dictionary
+	Config    *cell.Cell
+	GasLimit  uint64
+}
+
+// TVMExecutor - executes get methods locally, EmulatorTVMExecutor (emulator build tag) implements it using emulator library.
+// Result stack should be loaded with LoadFromCell from the serialized vm stack, like liteserver result.
+type TVMExecutor interface {
+	RunGetMethod(ctx context.Context, req *LocalGetMethodRequest) (exitCode int32, result *tlb.Stack, err error)
+}
+
+// SetTVMExecutor - sets executor for RunGetMethodVerified
+func (c *APIClient) SetTVMExecutor(executor TVMExecutor) {
+	root := c.root()
+	root.tvmExecutorLock.Lock()
+	defer root.tvmExecutorLock.Unlock()
+
+	root.tvmExecutor = executor
+}
+
+func (c *APIClient) getTVMExecutor() TVMExecutor {
+	root := c.root()
+	root.tvmExecutorLock.RLock()
+	defer root.tvmExecutorLock.RUnlock()
+
+	return root.tvmExecutor
+}
+
+// RunGetMethodVerified - same as RunGetMethod, but result is not taken from liteserver.
+// Account state, libraries, block and config are requested with proofs, and method is executed locally
+// with TVMExecutor, so the result is never trusted blindly. Proof check policy should not be unsafe.
+func (c *APIClient) RunGetMethodVerified(ctx context.Context, blockInfo *BlockIDExt, addr *address.Address, method string, params ...any) (*ExecutionResult, error) {
+	executor := c.getTVMExecutor()
+	if executor == nil {
+		return nil, ErrNoTVMExecutor
+	}
+
+	if blockInfo == nil {
+		return nil, fmt.Errorf("block is required for verified execution")
+	}
+
+	if c.proofCheckPolicy == ProofCheckPolicyUnsafe {
+		return nil, fmt.Errorf("verified execution is not possible with unsafe proof check policy")
+	}
+
+	if blockInfo.Workchain != address.MasterchainID {
+		return nil, fmt.Errorf("master block is required to build config")
+	}
+
+	acc, err := c.GetAccount(ctx, blockInfo, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	if !acc.IsActive || acc.Code == nil {
+		return nil, ContractExecError{ErrCodeContractNotInitialized}
+	}
+
+	// extra is requested to get rand seed of the block
+	blk, err := c.getBlockHeaderProof(ctx, blockInfo, blockHeaderWithExtra)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block header: %w", err)
+	}
+
+	cfg, err := c.GetBlockchainConfig(ctx, blockInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+
+	libs := cell.NewDict(256)
+	if hashes := collectLibraryHashes(acc.Code, acc.Data); len(hashes) > 0 {
+		list, err := c.GetLibrariesWithProof(ctx, blockInfo, hashes...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get libraries: %w", err)
+		}
+
+		for i, lib := range list {
+			if lib == nil {
+				return nil, fmt.Errorf("library %x not found", hashes[i])
+			}
+
+			if err = libs.Set(cell.BeginCell().MustStoreSlice(hashes[i], 256).EndCell(),
+				cell.BeginCell().MustStoreRef(lib).EndCell()); err != nil {
+				return nil, fmt.Errorf("failed to store library: %w", err)
+			}
+		}
+	}
+
+	prevBlocks, err := c.getPrevBlocksInfo(ctx, blockInfo, &blk.BlockInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prev blocks info: %w", err)
+	}
+
+	req, err := buildLocalGetMethodRequest(blk, acc, addr, cfg, libs, prevBlocks, method, params)
+	if err != nil {
+		return nil, err
+	}
+	return runLocalGetMethod(ctx, executor, req)
+}
+
+// getPrevBlocksInfo - builds PREVBLOCKSINFOTUPLE of c7: [last_mc_blocks prev_key_block],
+// last blocks are the given master block and up to 15 blocks before it, newest first.
+// Hashes of previous blocks are taken from verified headers, key block is looked up with proof.
+func (c *APIClient) getPrevBlocksInfo(ctx context.Context, master *BlockIDExt, hdr *tlb.BlockHeader) ([]any, error) {
+	last := []any{blockIDTuple(master)}
+	for cur := hdr; len(last) < 16 && cur.SeqNo > 0; {
+		ref := cur.PrevRef.Prev1
+		prev := &BlockIDExt{
+			Workchain: master.Workchain,
+			Shard:     master.Shard,
+			SeqNo:     ref.SeqNo,
+			RootHash:  ref.RootHash,
+			FileHash:  ref.FileHash,
+		}
+		last = append(last, blockIDTuple(prev))
+
+		if len(last) == 16 || prev.SeqNo == 0 {
+			break
+		}
+
+		var err error
+		if cur, err = c.GetBlockHeader(ctx, prev); err != nil {
+			return nil, fmt.Errorf("failed to get header of master block %d: %w", prev.SeqNo, err)
+		}
+	}
+
+	keyBlock, err := c.LookupBlockWithProof(ctx, master, master.Workchain, master.Shard, hdr.PrevKeyBlockSeqno)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup prev key block %d: %w", hdr.PrevKeyBlockSeqno, err)
+	}
+	return []any{last, blockIDTuple(keyBlock)}, nil
+}
+
+// buildLocalGetMethodRequest - prepares stack and c7 the same way as liteserver does for get methods
+func buildLocalGetMethodRequest(blk *tlb.Block, acc *tlb.Account, addr *address.Address, cfg *BlockchainConfig,
+	libs *cell.Dictionary, prevBlocks []any, method string, params []any) (*LocalGetMethodRequest, error) {
+	if blk.Extra == nil || len(blk.Extra.RandSeed) != 32 {
+		return nil, fmt.Errorf("no rand seed in block proof")
+	}
+
+	configRoot, err := buildConfigDict(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	unpackedConfig, err := unpackConfigTuple(cfg, blk.BlockInfo.GenUtime)
+	if err != nil {
+		return nil, err
+	}
+
+	stack := tlb.NewStack()
+	for i := len(params) - 1; i >= 0; i-- {
+		// push args in reverse order
+		stack.Push(params[i])
+	}
+
+	// random seed is derived the same way as for transactions: sha256(block_rand_seed || account_address)
+	seed := sha256.Sum256(append(append([]byte{}, blk.Extra.RandSeed...), addr.Data()...))
+
+	var extra any
+	if acc.State.ExtraCurrencies != nil {
+		extra = acc.State.ExtraCurrencies.AsCell()
+	}
+
+	duePayment := big.NewInt(0)
+	if acc.State.StorageInfo.DuePayment != nil {
+		duePayment = acc.State.StorageInfo.DuePayment.Nano()
+	}
+
+	c7 := []any{
+		uint32(smcInfoMagic),
+		0, // actions
+		0, // msgs sent
+		blk.BlockInfo.GenUtime,
+		new(big.Int).SetUint64(blk.BlockInfo.StartLt), // block lt
+		new(big.Int).SetUint64(blk.BlockInfo.StartLt), // trans lt
+		new(big.Int).SetBytes(seed[:]),
+		[]any{acc.State.Balance.Nano(), extra},
+		cell.BeginCell().MustStoreAddr(addr).EndCell().BeginParse(),
+		configRoot,
+		acc.Code,                  // my code
+		[]any{big.NewInt(0), nil}, // incoming value, get methods have no message
+		0,                         // storage fees
+		prevBlocks,
+		unpackedConfig,
+		duePayment,
+	}
+
+	return &LocalGetMethodRequest{
+		Address:   addr,
+		Code:      acc.Code,
+		Data:      acc.Data,
+		MethodID:  tlb.MethodNameHash(method),
+		Stack:     stack,
+		C7:        c7,
+		Libraries: libs,
+		Config:    configRoot,
+		GasLimit:  defaultGetMethodGasLimit,
+	}, nil
+}
+
+func runLocalGetMethod(ctx context.Context, executor TVMExecutor, req *LocalGetMethodRequest) (*ExecutionResult, error) {
+	exitCode, res, err := executor.RunGetMethod(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute method: %w", err)
+	}
+
+	if exitCode != 0 && exitCode != 1 {
+		return nil, ContractExecError{exitCode}
+	}
+
+	var result []any
+	for res != nil && res.Depth() > 0 {
+		v, err := res.Pop()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+	return NewExecutionResult(result), nil
+}
+
+// blockIDTuple - BlockId of c7: [wc:Integer shard:Integer seqno:Integer root_hash:Integer file_hash:Integer]
+func blockIDTuple(id *BlockIDExt) []any {
+	return []any{
+		id.Workchain,
+		new(big.Int).SetUint64(uint64(id.Shard)),
+		id.SeqNo,
+		new(big.Int).SetBytes(id.RootHash),
+		new(big.Int).SetBytes(id.FileHash),
+	}
+}
+
+// unpackConfigTuple - builds UNPACKEDCONFIGTUPLE of c7: storage prices active at now (18), global id (19),
+// gas prices (20, 21), forward prices (24, 25) and size limits (43), missing params are null
+func unpackConfigTuple(cfg *BlockchainConfig, now uint32) ([]any, error) {
+	tuple := make([]any, 7)
+
+	if prices := cfg.Get(18); prices != nil {
+		list, err := prices.AsDict(32).LoadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load storage prices: %w", err)
+		}
+
+		var latest uint32
+		for _, kv := range list {
+			// storage_prices#cc utime_since:uint32 ...
+			s := kv.Value.Copy()
+			if _, err = s.LoadUInt(8); err != nil {
+				return nil, fmt.Errorf("failed to load storage prices tag: %w", err)
+			}
+
+			since, err := s.LoadUInt(32)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load storage prices utime: %w", err)
+			}
+
+			if uint32(since) <= now && (tuple[0] == nil || uint32(since) >= latest) {
+				tuple[0], latest = kv.Value, uint32(since)
+			}
+		}
+	}
+
+	for i, id := range []int32{19, 20, 21, 24, 25, 43} {
+		if param := cfg.Get(id); param != nil {
+			tuple[i+1] = param.BeginParse()
+		}
+	}
+	return tuple, nil
+}
+
+func buildConfigDict(cfg *BlockchainConfig) (*cell.Cell, error) {
+	dict := cell.NewDict(32)
+	for id, param := range cfg.All() {
+		if err := dict.SetIntKey(big.NewInt(int64(id)), cell.BeginCell().MustStoreRef(param).EndCell()); err != nil {
+			return nil, fmt.Errorf("failed to store config param %d: %w", id, err)
+		}
+	}
+	return dict.AsCell(), nil
+}
+
+// collectLibraryHashes - finds all library cells in the given trees, returns unique hashes
+func collectLibraryHashes(roots ...*cell.Cell) [][]byte {
+	var hashes [][]byte
+	seen := map[string]bool{}
+	seenLibs := map[string]bool{}
+
+	var walk func(c *cell.Cell)
+	walk = func(c *cell.Cell) {
+		if c == nil || seen[string(c.Hash())] {
+			return
+		}
+		seen[string(c.Hash())] = true
+
+		if c.GetType() == cell.LibraryCellType {
+			s := c.BeginParse()
+			s.MustLoadUInt(8)
+			hash := s.MustLoadSlice(256)
+			if !seenLibs[string(hash)] {
+				seenLibs[string(hash)] = true
+				hashes = append(hashes, hash)
+			}
+			return
+		}
+
+		for i := 0; i < int(c.RefsNum()); i++ {
+			walk(c.MustPeekRef(i))
+		}
+	}
+
+	for _, root := range roots {
+		walk(root)
+	}
+	return hashes
+}
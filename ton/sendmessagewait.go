@@ -7,37 +7,216 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/alan890104/tonutils-go/address"
 	"github.com/alan890104/tonutils-go/tlb"
 )
 
 var ErrTxWasNotConfirmed = errors.New("transaction was not confirmed in a given deadline, but it may still be confirmed later")
 
+// ConfirmationLevel - when transaction of the sent message is considered as confirmed
+type ConfirmationLevel int
+
+const (
+	// ConfirmationInShard - transaction is found in a block, this is the fastest level
+	ConfirmationInShard ConfirmationLevel = iota
+	// ConfirmationInMasterchain - block of the transaction is committed in a masterchain block
+	ConfirmationInMasterchain
+	// ConfirmationMasterchainDepth - block of the transaction is committed in a masterchain block,
+	// and at least Depth masterchain blocks were created after it
+	ConfirmationMasterchainDepth
+)
+
+type ConfirmationPolicy struct {
+	Level ConfirmationLevel
+	// Depth - number of masterchain blocks after the committing one, used by ConfirmationMasterchainDepth
+	Depth uint32
+}
+
+type ConfirmedTransaction struct {
+	Transaction *tlb.Transaction
+	// Block - block which contains the transaction, can be shard or master
+	Block *BlockIDExt
+	// MasterBlock - masterchain block which committed Block, nil for ConfirmationInShard
+	MasterBlock *BlockIDExt
+	// Depth - number of masterchain blocks after MasterBlock at the moment of confirmation
+	Depth     uint32
+	InMsgHash []byte
+}
+
 func (c *APIClient) SendExternalMessageWaitTransaction(ctx context.Context, ext *tlb.ExternalMessage) (*tlb.Transaction, *BlockIDExt, []byte, error) {
+	tx, _, master, inMsgHash, err := c.sendExternalMessageAndWait(ctx, ext)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return tx, master, inMsgHash, nil
+}
+
+// SendExternalMessageWaitConfirmation - sends message and waits for its transaction according to the policy.
+// In case of masterchain confirmation, the committing master block is returned, so it can be used
+// to credit funds only after masterchain inclusion. In secure proof mode master block is also verified
+// against the trusted block.
+func (c *APIClient) SendExternalMessageWaitConfirmation(ctx context.Context, ext *tlb.ExternalMessage, policy ConfirmationPolicy) (*ConfirmedTransaction, error) {
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		// fallback timeout to not stuck forever with background context
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 180*time.Second)
+		defer cancel()
+	}
+
+	tx, txBlock, _, inMsgHash, err := c.sendExternalMessageAndWait(ctx, ext)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ConfirmedTransaction{
+		Transaction: tx,
+		Block:       txBlock,
+		InMsgHash:   inMsgHash,
+	}
+
+	if err = c.waitFinalization(c.Client().StickyContext(ctx), res, policy); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *APIClient) sendExternalMessageAndWait(ctx context.Context, ext *tlb.ExternalMessage) (*tlb.Transaction, *BlockIDExt, *BlockIDExt, []byte, error) {
 	block, err := c.CurrentMasterchainInfo(ctx)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to get block: %w", err)
+		return nil, nil, nil, nil, fmt.Errorf("failed to get block: %w", err)
 	}
 
 	acc, err := c.WaitForBlock(block.SeqNo).GetAccount(ctx, block, ext.DstAddr)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to get account state: %w", err)
+		return nil, nil, nil, nil, fmt.Errorf("failed to get account state: %w", err)
 	}
 
 	inMsgHash := ext.Body.Hash()
 
 	if err = c.SendExternalMessage(ctx, ext); err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to send message: %w", err)
+		return nil, nil, nil, nil, fmt.Errorf("failed to send message: %w", err)
 	}
 
-	tx, block, err := c.waitConfirmation(ctx, block, acc, ext)
+	tx, txBlock, block, err := c.waitConfirmation(ctx, block, acc, ext)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
+	}
+
+	return tx, txBlock, block, inMsgHash, nil
+}
+
+// waitFinalization - finds masterchain block which committed the transaction block,
+// and waits for the required number of masterchain blocks after it
+func (c *APIClient) waitFinalization(ctx context.Context, res *ConfirmedTransaction, policy ConfirmationPolicy) error {
+	till, ok := ctx.Deadline()
+	if !ok {
+		till = time.Now().Add(180 * time.Second)
+	}
+
+	if res.Transaction != nil && c.proofCheckPolicy != ProofCheckPolicyUnsafe {
+		// block of the transaction was taken from unproven list, so we check it before trusting
+		if err := c.proveTransactionBlock(ctx, res, till); err != nil {
+			return err
+		}
+	}
+
+	if policy.Level == ConfirmationInShard {
+		return nil
 	}
 
-	return tx, block, inMsgHash, nil
+	var master *BlockIDExt
+	for master == nil {
+		if !time.Now().Before(till) {
+			return ErrTxWasNotConfirmed
+		}
+
+		if res.Block.Workchain == address.MasterchainID {
+			master = res.Block
+		} else {
+			// proof is checked inside, unless policy is unsafe
+			proof, err := c.GetShardBlockProof(ctx, res.Block)
+			if err != nil {
+				if !isNotFoundError(err) {
+					// proof verification failure will not be fixed by retry
+					return fmt.Errorf("failed to get shard block proof: %w", err)
+				}
+
+				select {
+				case <-ctx.Done():
+					return ErrTxWasNotConfirmed
+				case <-time.After(1 * time.Second):
+				}
+				continue
+			}
+			master = proof.MasterchainID
+		}
+
+		if err := c.verifyBlockInMasterchain(ctx, master); err != nil {
+			return fmt.Errorf("failed to verify master block: %w", err)
+		}
+	}
+	res.MasterBlock = master
+
+	if policy.Level == ConfirmationInMasterchain {
+		return nil
+	}
+
+	target := master.SeqNo + policy.Depth
+	for time.Now().Before(till) {
+		// waits for the required block to appear on the node
+		block, err := c.WaitForBlock(target).GetMasterchainInfo(ctx)
+		if err == nil && block.SeqNo >= target {
+			res.Depth = block.SeqNo - master.SeqNo
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ErrTxWasNotConfirmed
+		case <-time.After(1 * time.Second):
+		}
+	}
+	return ErrTxWasNotConfirmed
+}
+
+// proveTransactionBlock - requests transaction from its block with proof, to make sure it is really there
+func (c *APIClient) proveTransactionBlock(ctx context.Context, res *ConfirmedTransaction, till time.Time) error {
+	addr := address.NewAddress(0, byte(res.Block.Workchain), res.Transaction.AccountAddr)
+	for {
+		if !time.Now().Before(till) {
+			return ErrTxWasNotConfirmed
+		}
+
+		// proof is checked inside
+		tx, err := c.GetTransaction(ctx, res.Block, addr, res.Transaction.LT)
+		if err != nil {
+			if !isNotFoundError(err) {
+				return fmt.Errorf("failed to prove transaction in block: %w", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return ErrTxWasNotConfirmed
+			case <-time.After(1 * time.Second):
+			}
+			continue
+		}
+
+		if !bytes.Equal(tx.Hash, res.Transaction.Hash) {
+			return fmt.Errorf("transaction hash not matches proven transaction in block")
+		}
+		return nil
+	}
+}
+
+// isNotFoundError - node has no requested data yet, so it makes sense to retry later
+func isNotFoundError(err error) bool {
+	var lsErr LSError
+	return errors.As(err, &lsErr) || errors.Is(err, ErrNoTransactionsWereFound) || errors.Is(err, ErrBlockNotFound)
 }
 
-func (c *APIClient) waitConfirmation(ctx context.Context, block *BlockIDExt, acc *tlb.Account, ext *tlb.ExternalMessage) (*tlb.Transaction, *BlockIDExt, error) {
+// waitConfirmation - returns transaction, its block and master block where it was seen
+func (c *APIClient) waitConfirmation(ctx context.Context, block *BlockIDExt, acc *tlb.Account, ext *tlb.ExternalMessage) (*tlb.Transaction, *BlockIDExt, *BlockIDExt, error) {
 	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
 		// fallback timeout to not stuck forever with background context
 		var cancel context.CancelFunc
@@ -75,7 +254,7 @@ func (c *APIClient) waitConfirmation(ctx context.Context, block *BlockIDExt, acc
 		// to prevent this we will scan till we reach last seen offset.
 		for time.Now().Before(till) {
 			// we try to get last 5 transactions, and check if we have our new there.
-			txList, err := c.WaitForBlock(block.SeqNo).ListTransactions(ctx, ext.DstAddr, 5, lastLt, lastHash)
+			txList, txBlocks, err := c.waitForBlock(block.SeqNo).listTransactions(ctx, ext.DstAddr, 5, lastLt, lastHash)
 			if err != nil {
 				continue
 			}
@@ -112,7 +291,7 @@ func (c *APIClient) waitConfirmation(ctx context.Context, block *BlockIDExt, acc
 						continue
 					}
 
-					return transaction, block, nil
+					return transaction, txBlocks[i], block, nil
 				}
 			}
 
@@ -123,5 +302,5 @@ func (c *APIClient) waitConfirmation(ctx context.Context, block *BlockIDExt, acc
 		acc = accNew
 	}
 
-	return nil, nil, ErrTxWasNotConfirmed
+	return nil, nil, nil, ErrTxWasNotConfirmed
 }
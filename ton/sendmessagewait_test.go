@@ -0,0 +1,162 @@
+package ton
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alan890104/tonutils-go/tl"
+	"github.com/alan890104/tonutils-go/tlb"
+)
+
+func TestAPIClient_WaitFinalization(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c := NewAPIClient(nil, ProofCheckPolicyUnsafe)
+	master := testMasterBlockID(10, 0)
+
+	res := &ConfirmedTransaction{Block: master}
+	if err := c.waitFinalization(ctx, res, ConfirmationPolicy{Level: ConfirmationInShard}); err != nil {
+		t.Fatal(err)
+	}
+	if res.MasterBlock != nil {
+		t.Fatal("master block should not be resolved for shard confirmation")
+	}
+
+	if err := c.waitFinalization(ctx, res, ConfirmationPolicy{Level: ConfirmationInMasterchain}); err != nil {
+		t.Fatal(err)
+	}
+	if !res.MasterBlock.Equals(master) || res.Depth != 0 {
+		t.Fatal("master block of masterchain transaction should be its block")
+	}
+}
+
+func TestAPIClient_WaitFinalization_Shard(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	shardBlock := testBlockID(0, 0x8000000000000000, 50, 1)
+	master := testMasterBlockID(10, 2)
+
+	var proofRequests, infoRequests int
+	// first time node is behind, then it reaches the required depth
+	lastSeqno := func() uint32 { return master.SeqNo + uint32(infoRequests) }
+	c := NewAPIClient(&testLiteClient{handler: func(req tl.Serializable) (tl.Serializable, error) {
+		switch r := req.(type) {
+		case GetShardBlockProof:
+			proofRequests++
+			if !r.ID.Equals(shardBlock) {
+				return nil, fmt.Errorf("unexpected block")
+			}
+			if proofRequests == 1 {
+				// not yet available on node, should be retried
+				return LSError{Code: 651, Text: "not found"}, nil
+			}
+			return ShardBlockProof{MasterchainID: master}, nil
+		case GetMasterchainInf:
+			infoRequests++
+			return MasterchainInfo{Last: testMasterBlockID(lastSeqno(), 3)}, nil
+		}
+		return nil, fmt.Errorf("unexpected request %T", req)
+	}}, ProofCheckPolicyUnsafe)
+
+	res := &ConfirmedTransaction{Block: shardBlock}
+	if err := c.waitFinalization(ctx, res, ConfirmationPolicy{Level: ConfirmationMasterchainDepth, Depth: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !res.MasterBlock.Equals(master) || res.Depth != 2 {
+		t.Fatal("incorrect finalization result", res.MasterBlock, res.Depth)
+	}
+	if proofRequests != 2 || infoRequests != 2 {
+		t.Fatal("incorrect requests number", proofRequests, infoRequests)
+	}
+
+	// node never reaches the depth, should stop on deadline without busy loop
+	shortCtx, cancelShort := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancelShort()
+
+	infoRequests = 0
+	lastSeqno = func() uint32 { return master.SeqNo }
+	res = &ConfirmedTransaction{Block: shardBlock}
+	if err := c.waitFinalization(shortCtx, res, ConfirmationPolicy{Level: ConfirmationMasterchainDepth, Depth: 2000}); err != ErrTxWasNotConfirmed {
+		t.Fatal("should be not confirmed", err)
+	}
+	if infoRequests > 3 {
+		t.Fatal("too many requests", infoRequests)
+	}
+}
+
+func TestAPIClient_WaitFinalization_ProveTransaction(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	master, _ := testMasterHeaderBlock(t)
+	proof, txs := testMasterTransactions(t, master, bytes.Repeat([]byte{0x33}, 32))
+
+	var tx tlb.Transaction
+	if err := tlb.LoadFromCell(&tx, txs[0].BeginParse()); err != nil {
+		t.Fatal(err)
+	}
+	tx.Hash = txs[0].Hash()
+
+	var txRequests, proofRequests int
+	c := NewAPIClient(&testLiteClient{handler: func(req tl.Serializable) (tl.Serializable, error) {
+		switch r := req.(type) {
+		case GetOneTransaction:
+			txRequests++
+			if txRequests == 1 {
+				// not yet available on node, should be retried
+				return LSError{Code: 651, Text: "not found"}, nil
+			}
+			return TransactionInfo{ID: r.ID, Proof: proof, Transaction: txs[0].ToBOC()}, nil
+		case GetShardBlockProof:
+			proofRequests++
+			// proof without links to the shard block, cannot be verified
+			return ShardBlockProof{MasterchainID: master}, nil
+		}
+		return nil, fmt.Errorf("unexpected request %T", req)
+	}}, ProofCheckPolicyFast)
+
+	res := &ConfirmedTransaction{Transaction: &tx, Block: master}
+	if err := c.waitFinalization(ctx, res, ConfirmationPolicy{Level: ConfirmationInMasterchain}); err != nil {
+		t.Fatal(err)
+	}
+	if !res.MasterBlock.Equals(master) || txRequests != 2 {
+		t.Fatal("incorrect finalization result", res.MasterBlock, txRequests)
+	}
+
+	// list claimed a block which does not contain the transaction
+	other := master.Copy()
+	other.RootHash, _ = hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	res = &ConfirmedTransaction{Transaction: &tx, Block: other}
+	if err := c.waitFinalization(ctx, res, ConfirmationPolicy{Level: ConfirmationInShard}); err == nil {
+		t.Fatal("transaction proof for other block should fail")
+	}
+	if txRequests != 3 {
+		t.Fatal("proof failure should not be retried", txRequests)
+	}
+
+	wrongTx := tx
+	wrongTx.Hash = bytes.Repeat([]byte{1}, 32)
+	res = &ConfirmedTransaction{Transaction: &wrongTx, Block: master}
+	if err := c.waitFinalization(ctx, res, ConfirmationPolicy{Level: ConfirmationInShard}); err == nil {
+		t.Fatal("transaction with other hash should fail")
+	}
+
+	// transaction proof is checked only against root hash, so the same block is presented as a shard one
+	shardBlock := master.Copy()
+	shardBlock.Workchain = 0
+	shardBlock.Shard = -0x8000000000000000
+	res = &ConfirmedTransaction{Transaction: &tx, Block: shardBlock}
+	if err := c.waitFinalization(ctx, res, ConfirmationPolicy{Level: ConfirmationInMasterchain}); err == nil {
+		t.Fatal("unverified shard block proof should fail")
+	}
+	if proofRequests != 1 || res.MasterBlock != nil {
+		t.Fatal("shard block proof failure should not be retried", proofRequests)
+	}
+}
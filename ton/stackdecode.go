@@ -0,0 +1,424 @@
+package ton
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alan890104/tonutils-go/address"
+	"github.com/alan890104/tonutils-go/tlb"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+var (
+	stackDecoders   = map[reflect.Type]func(v any) (any, error){}
+	stackDecodersMx sync.RWMutex
+)
+
+var (
+	bigIntType      = reflect.TypeOf(&big.Int{})
+	coinsType       = reflect.TypeOf(tlb.Coins{})
+	addressType     = reflect.TypeOf(&address.Address{})
+	cellType        = reflect.TypeOf(&cell.Cell{})
+	sliceType       = reflect.TypeOf(&cell.Slice{})
+	builderType     = reflect.TypeOf(&cell.Builder{})
+	unmarshalerType = reflect.TypeOf((*tlb.Unmarshaler)(nil)).Elem()
+)
+
+// RegisterStackDecoder adds custom conversion of stack value to type T, which will be used by ExecutionResult.Decode,
+// it is useful for interface types, for example nft.ContentAny is registered this way.
+func RegisterStackDecoder[T any](fn func(v any) (T, error)) {
+	stackDecodersMx.Lock()
+	defer stackDecodersMx.Unlock()
+
+	stackDecoders[reflect.TypeOf((*T)(nil)).Elem()] = func(v any) (any, error) {
+		return fn(v)
+	}
+}
+
+type stackField struct {
+	index    int
+	field    int
+	optional bool
+	asSlice  bool
+}
+
+// parseStackFields returns stack positions of struct fields,
+// tag format is `stack:"index,options"`, where options are: optional, slice.
+// Fields without index are mapped to the next position after previous field, `stack:"-"` skips field.
+func parseStackFields(t reflect.Type) ([]stackField, error) {
+	var fields []stackField
+	next := 0
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		tag := strings.TrimSpace(f.Tag.Get("stack"))
+		if tag == "-" {
+			continue
+		}
+
+		sf := stackField{index: next, field: i}
+		for j, opt := range strings.Split(tag, ",") {
+			opt = strings.TrimSpace(opt)
+			switch {
+			case opt == "":
+			case j == 0 && opt[0] >= '0' && opt[0] <= '9':
+				idx, err := strconv.Atoi(opt)
+				if err != nil {
+					return nil, fmt.Errorf("invalid stack index of field %s: %w", f.Name, err)
+				}
+				sf.index = idx
+			case opt == "optional":
+				sf.optional = true
+			case opt == "slice":
+				sf.asSlice = true
+			default:
+				return nil, fmt.Errorf("unknown stack tag option %s of field %s", opt, f.Name)
+			}
+		}
+
+		next = sf.index + 1
+		fields = append(fields, sf)
+	}
+	return fields, nil
+}
+
+// Decode maps result stack to struct fields, dst should be a pointer to struct.
+// Position of field in stack is set by `stack:"index"` tag, or it is next after previous field when not set.
+// Values are converted automatically to the type of field:
+// ints to *big.Int, integers, bool and tlb.Coins; slices to *address.Address;
+// cells, slices and builders to each other and to tlb types; tuples to nested structs and slices.
+// Nil values are allowed only for pointers, interfaces and fields with optional tag.
+func (r ExecutionResult) Decode(dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("dst should be a pointer to struct")
+	}
+	return decodeStackTuple(r.result, rv.Elem())
+}
+
+func decodeStackTuple(values []any, rv reflect.Value) error {
+	fields, err := parseStackFields(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, f := range fields {
+		name := rv.Type().Field(f.field).Name
+		if f.index >= len(values) {
+			if f.optional {
+				continue
+			}
+			return fmt.Errorf("field %s: %w", name, ErrResultIndexOutOfRange)
+		}
+
+		if values[f.index] == nil && f.optional {
+			continue
+		}
+
+		if err = decodeStackValue(values[f.index], rv.Field(f.field)); err != nil {
+			return fmt.Errorf("field %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func decodeStackValue(v any, rv reflect.Value) error {
+	t := rv.Type()
+
+	stackDecodersMx.RLock()
+	custom := stackDecoders[t]
+	stackDecodersMx.RUnlock()
+
+	if custom != nil {
+		res, err := custom(v)
+		if err != nil {
+			return err
+		}
+		if res == nil {
+			rv.Set(reflect.Zero(t))
+		} else {
+			rv.Set(reflect.ValueOf(res))
+		}
+		return nil
+	}
+
+	if v == nil {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+			rv.Set(reflect.Zero(t))
+			return nil
+		}
+		return fmt.Errorf("%w: nil cannot be converted to %s", ErrIncorrectResultType, t.String())
+	}
+
+	switch t {
+	case bigIntType:
+		i, ok := v.(*big.Int)
+		if !ok {
+			return incorrectStackType(v, t)
+		}
+		rv.Set(reflect.ValueOf(new(big.Int).Set(i)))
+		return nil
+	case coinsType:
+		i, ok := v.(*big.Int)
+		if !ok {
+			return incorrectStackType(v, t)
+		}
+		coins, err := tlb.FromNano(i, 9)
+		if err != nil {
+			return fmt.Errorf("failed to convert coins: %w", err)
+		}
+		rv.Set(reflect.ValueOf(coins))
+		return nil
+	case addressType:
+		s, err := stackValueToSlice(v)
+		if err != nil {
+			return incorrectStackType(v, t)
+		}
+		addr, err := s.LoadAddr()
+		if err != nil {
+			return fmt.Errorf("failed to load address: %w", err)
+		}
+		rv.Set(reflect.ValueOf(addr))
+		return nil
+	case cellType:
+		c, err := stackValueToCell(v)
+		if err != nil {
+			return incorrectStackType(v, t)
+		}
+		rv.Set(reflect.ValueOf(c))
+		return nil
+	case sliceType:
+		s, err := stackValueToSlice(v)
+		if err != nil {
+			return incorrectStackType(v, t)
+		}
+		rv.Set(reflect.ValueOf(s))
+		return nil
+	case builderType:
+		c, err := stackValueToCell(v)
+		if err != nil {
+			return incorrectStackType(v, t)
+		}
+		rv.Set(reflect.ValueOf(c.ToBuilder()))
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		if !reflect.TypeOf(v).AssignableTo(t) {
+			return incorrectStackType(v, t)
+		}
+		rv.Set(reflect.ValueOf(v))
+		return nil
+	case reflect.Bool:
+		i, ok := v.(*big.Int)
+		if !ok {
+			return incorrectStackType(v, t)
+		}
+		rv.SetBool(i.Sign() != 0)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := v.(*big.Int)
+		if !ok {
+			return incorrectStackType(v, t)
+		}
+		if !i.IsInt64() || rv.OverflowInt(i.Int64()) {
+			return fmt.Errorf("value %s overflows %s", i.String(), t.String())
+		}
+		rv.SetInt(i.Int64())
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, ok := v.(*big.Int)
+		if !ok {
+			return incorrectStackType(v, t)
+		}
+		if !i.IsUint64() || rv.OverflowUint(i.Uint64()) {
+			return fmt.Errorf("value %s overflows %s", i.String(), t.String())
+		}
+		rv.SetUint(i.Uint64())
+		return nil
+	case reflect.Slice:
+		tuple, ok := v.([]any)
+		if !ok {
+			return incorrectStackType(v, t)
+		}
+
+		list := reflect.MakeSlice(t, len(tuple), len(tuple))
+		for i := range tuple {
+			if err := decodeStackValue(tuple[i], list.Index(i)); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		rv.Set(list)
+		return nil
+	case reflect.Pointer:
+		val := reflect.New(t.Elem())
+		if err := decodeStackValue(v, val.Elem()); err != nil {
+			return err
+		}
+		rv.Set(val)
+		return nil
+	case reflect.Struct:
+		if tuple, ok := v.([]any); ok {
+			return decodeStackTuple(tuple, rv)
+		}
+
+		// cell with tlb structure
+		if reflect.PointerTo(t).Implements(unmarshalerType) || t.NumField() > 0 && t.Field(0).Tag.Get("tlb") != "" {
+			s, err := stackValueToSlice(v)
+			if err != nil {
+				return incorrectStackType(v, t)
+			}
+			if err = tlb.LoadFromCell(rv.Addr().Interface(), s); err != nil {
+				return fmt.Errorf("failed to load tlb structure: %w", err)
+			}
+			return nil
+		}
+	}
+	return incorrectStackType(v, t)
+}
+
+// EncodeGetMethodParams builds RunGetMethod params from struct, using the same tags as ExecutionResult.Decode.
+// Numbers, bool and tlb.Coins are converted to ints, *address.Address to slice,
+// nested structs and slices to tuples, tlb structures to cells (or slices, with slice option).
+// Nil fields with optional tag are omitted.
+func EncodeGetMethodParams(src any) ([]any, error) {
+	rv := reflect.ValueOf(src)
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("src should be a struct or pointer to struct")
+	}
+	return encodeStackTuple(rv)
+}
+
+func encodeStackTuple(rv reflect.Value) ([]any, error) {
+	fields, err := parseStackFields(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	var res []any
+	for _, f := range fields {
+		name := rv.Type().Field(f.field).Name
+
+		val, err := encodeStackValue(rv.Field(f.field), f.asSlice)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", name, err)
+		}
+
+		if val == nil && f.optional {
+			continue
+		}
+
+		for len(res) <= f.index {
+			res = append(res, nil)
+		}
+		res[f.index] = val
+	}
+	return res, nil
+}
+
+func encodeStackValue(rv reflect.Value, asSlice bool) (any, error) {
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice:
+		if rv.IsNil() {
+			return nil, nil
+		}
+	}
+
+	toCellValue := func(c *cell.Cell) any {
+		if asSlice {
+			return c.BeginParse()
+		}
+		return c
+	}
+
+	switch v := rv.Interface().(type) {
+	case *big.Int:
+		return new(big.Int).Set(v), nil
+	case tlb.Coins:
+		return v.Nano(), nil
+	case *address.Address:
+		return cell.BeginCell().MustStoreAddr(v).EndCell().BeginParse(), nil
+	case *cell.Cell:
+		return toCellValue(v), nil
+	case *cell.Slice:
+		return v.Copy(), nil
+	case *cell.Builder:
+		return v, nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		return encodeStackValue(rv.Elem(), asSlice)
+	case reflect.Bool:
+		if rv.Bool() {
+			return big.NewInt(-1), nil
+		}
+		return big.NewInt(0), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(rv.Uint()), nil
+	case reflect.Slice:
+		tuple := make([]any, rv.Len())
+		for i := range tuple {
+			val, err := encodeStackValue(rv.Index(i), false)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+			tuple[i] = val
+		}
+		return tuple, nil
+	case reflect.Pointer:
+		return encodeStackValue(rv.Elem(), asSlice)
+	case reflect.Struct:
+		if _, ok := rv.Interface().(tlb.Marshaller); ok || rv.NumField() > 0 && rv.Type().Field(0).Tag.Get("tlb") != "" {
+			c, err := tlb.ToCell(rv.Interface())
+			if err != nil {
+				return nil, fmt.Errorf("failed to serialize tlb structure: %w", err)
+			}
+			return toCellValue(c), nil
+		}
+		return encodeStackTuple(rv)
+	}
+	return nil, fmt.Errorf("unsupported type %s", rv.Type().String())
+}
+
+func stackValueToCell(v any) (*cell.Cell, error) {
+	switch val := v.(type) {
+	case *cell.Cell:
+		return val, nil
+	case *cell.Slice:
+		return val.ToCell()
+	case *cell.Builder:
+		return val.EndCell(), nil
+	}
+	return nil, ErrIncorrectResultType
+}
+
+func stackValueToSlice(v any) (*cell.Slice, error) {
+	switch val := v.(type) {
+	case *cell.Slice:
+		return val.Copy(), nil
+	case *cell.Cell:
+		return val.BeginParse(), nil
+	case *cell.Builder:
+		return val.EndCell().BeginParse(), nil
+	}
+	return nil, ErrIncorrectResultType
+}
+
+func incorrectStackType(v any, t reflect.Type) error {
+	return fmt.Errorf("%w: %T cannot be converted to %s", ErrIncorrectResultType, v, t.String())
+}
@@ -50,6 +50,55 @@ type GetTransactions struct {
 // ListTransactions - returns list of transactions before (including) passed lt and hash, the oldest one is first in result slice
 // Transactions will be verified to match final tx hash, which should be taken from proved account state, then it is safe.
 func (c *APIClient) ListTransactions(ctx context.Context, addr *address.Address, limit uint32, lt uint64, txHash []byte) ([]*tlb.Transaction, error) {
+	txs, _, err := c.listTransactions(ctx, addr, limit, lt, txHash)
+	return txs, err
+}
+
+// ListTransactionsWithProof - same as ListTransactions, but each transaction is also proven to be included in its block,
+// and each block is proven to be committed in the chain of the given master block.
+// Master block should be trusted, for example taken from CurrentMasterchainInfo with secure proof check policy.
+// Block proofs are requested once per block, so transactions from the same block share it.
+// Proofs cannot be checked with unsafe proof check policy, so error is returned in this case.
+func (c *APIClient) ListTransactionsWithProof(ctx context.Context, master *BlockIDExt, addr *address.Address, limit uint32, lt uint64, txHash []byte) ([]*tlb.Transaction, error) {
+	if c.proofCheckPolicy == ProofCheckPolicyUnsafe {
+		return nil, fmt.Errorf("transactions cannot be proven with unsafe proof check policy")
+	}
+
+	txs, blocks, err := c.listTransactions(ctx, addr, limit, lt, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	provenBlocks := map[string]bool{}
+	for i, tx := range txs {
+		block := blocks[i]
+
+		if key := string(block.RootHash); !provenBlocks[key] {
+			id, err := c.LookupBlockWithProof(ctx, master, block.Workchain, block.Shard, block.SeqNo)
+			if err != nil {
+				return nil, fmt.Errorf("failed to prove block %d of transaction %d: %w", block.SeqNo, tx.LT, err)
+			}
+
+			if !id.Equals(block) {
+				return nil, fmt.Errorf("block %d of transaction %d is not in master chain", block.SeqNo, tx.LT)
+			}
+			provenBlocks[key] = true
+		}
+
+		// proof is checked inside, because policy is not unsafe
+		provenTx, err := c.GetTransaction(ctx, block, addr, tx.LT)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get proof of transaction %d: %w", tx.LT, err)
+		}
+
+		if !bytes.Equal(provenTx.Hash, tx.Hash) {
+			return nil, fmt.Errorf("transaction %d not matches its block proof", tx.LT)
+		}
+	}
+	return txs, nil
+}
+
+func (c *APIClient) listTransactions(ctx context.Context, addr *address.Address, limit uint32, lt uint64, txHash []byte) ([]*tlb.Transaction, []*BlockIDExt, error) {
 	var resp tl.Serializable
 	err := c.client.QueryLiteserver(ctx, GetTransactions{
 		Limit: int32(limit),
@@ -61,21 +110,26 @@ func (c *APIClient) ListTransactions(ctx context.Context, addr *address.Address,
 		TxHash: txHash,
 	}, &resp)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	switch t := resp.(type) {
 	case TransactionList:
 		if len(t.Transactions) == 0 {
-			return nil, ErrNoTransactionsWereFound
+			return nil, nil, ErrNoTransactionsWereFound
 		}
 
 		txList, err := cell.FromBOCMultiRoot(t.Transactions)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse cell from transaction bytes: %w", err)
+			return nil, nil, fmt.Errorf("failed to parse cell from transaction bytes: %w", err)
+		}
+
+		if len(t.IDs) != len(txList) {
+			return nil, nil, fmt.Errorf("block ids number not matches transactions number")
 		}
 
 		res := make([]*tlb.Transaction, len(txList))
+		blocks := make([]*BlockIDExt, len(txList))
 
 		for i := 0; i < len(txList); i++ {
 			loader := txList[i].BeginParse()
@@ -83,25 +137,26 @@ func (c *APIClient) ListTransactions(ctx context.Context, addr *address.Address,
 			var tx tlb.Transaction
 			err = tlb.LoadFromCell(&tx, loader)
 			if err != nil {
-				return nil, fmt.Errorf("failed to load transaction from cell: %w", err)
+				return nil, nil, fmt.Errorf("failed to load transaction from cell: %w", err)
 			}
 			tx.Hash = txList[i].Hash()
 
 			if !bytes.Equal(txHash, tx.Hash) {
-				return nil, fmt.Errorf("incorrect transaction hash, not matches prev tx hash")
+				return nil, nil, fmt.Errorf("incorrect transaction hash, not matches prev tx hash")
 			}
 			txHash = tx.PrevTxHash
 			res[(len(txList)-1)-i] = &tx
+			blocks[(len(txList)-1)-i] = t.IDs[i]
 		}
-		return res, nil
+		return res, blocks, nil
 	case LSError:
 		if t.Code == 0 {
-			return nil, ErrNoTransactionsWereFound
+			return nil, nil, ErrNoTransactionsWereFound
 		}
-		return nil, t
+		return nil, nil, t
 	}
 
-	return nil, errors.New("unknown response type")
+	return nil, nil, errors.New("unknown response type")
 }
 
 func (c *APIClient) GetTransaction(ctx context.Context, block *BlockIDExt, addr *address.Address, lt uint64) (*tlb.Transaction, error) {
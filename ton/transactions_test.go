@@ -0,0 +1,134 @@
+package ton
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/alan890104/tonutils-go/address"
+	"github.com/alan890104/tonutils-go/tl"
+	"github.com/alan890104/tonutils-go/tlb"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+// testMasterTxProof - proof of the master block from testMasterHeaderBlock with header and all account blocks
+const testMasterTxProof = "b5ee9c7241022e010008ac000946031908d071c508674d205fae80f3793faf9448202166f9189769e45d506d1e0b56001501241011ef55aaffffff110203040501a09bc7a9870000000004010173ed450000000100ffffffff0000000000000000634e93ea00001d3677b8338000001d3677b83384955d862e00058edb0173ed410173bfbec400000003000000000000002e0628480101722a64735fabcfc1da1c8d3e7bc91671a222e81838a03de86e661afffb94ce1b0003284801010c5f6b7ec9313294fae3684120186ce46f5fda895db29b38126fcda21bc77adb001424894a33f6fd0be55a2d75c3eae367b5ba338705f0319041b70e23a5c9b65374cf2739898e58f78b372f9292751451def1be4dc7cf494ef17470574d85c253ef77746b8ea127c00708090a009800001d3677a8f1440173ed443de180887d5f5a84d44bd19c87cbb664b0561d5eb81da88c5782b0e36e9a07e4d7fd7d801561f54bffc0cb5c4ec4e855deeeeb6fdf26d4c99a086ffafb93580a2848010163653b2464b7eb0378ef92eb61c8ca692682e02c89411cc3ddc81937b604eb8e00040001020101820b28480101bf9dbbf5e1f25af6a75e7f4dd4a128c29a55b1e615d4d35847e58dd55266ba3a0005020340400c0d020376040e0f0297bf955555555555555555555555555555555555555555555555555555555555555502aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaad00000074d9dee0ce0c110110397beb33333333333333333333333333333333333333333333333333333333333333029999999999999999999999999999999999999999999999999999999999999999cf8000074d9dee0ce00401213140397be8517c7bdf5187c55af4f8b61fdc321588c7ab768dee24b006df29106458d7cf029a28be3defa8c3e2ad7a7c5b0fee190ac463d5bb46f71258036f9488322c6be7cf8000074d9dee0ce004015161703af7555555555555555555555555555555555555555555555555555555555555555500001d3677b83383f9b2f37bf03c07595e4f861c14a0779c5b0ddb12bd6b0bf59b7f464fab4b279400001d3677a8f143634e93ea00014082c11180082720ac47779e474df79ac188caf2308fa7fccf511a8be789a6502f15ca63fba64408669008ce4710e1108a5eee86c282b1d13feaf7634e0c592943ae844ddd4ca0c0103504019010340401a0082722f7566ede0ba3a333ac2ca4e9820a0eb28fa3c675e8c5b7378fbba7d487af6b6d8b330226ee7a4226c9a4e28167203a4dec229d3f51655422b56dd7122352fda010350401b010350401c0082723145f857768776495406acbcd9f6451e43b82a7cf2b787bdfcd66f54e8f61eb2f5fc1aa51cd06879f30dac067b3d17d0571b7c8ef15db6c57ce3e90f63e7d80802053030241d2d03af7333333333333333333333333333333333333333333333333333333333333333300001d3677b8338199ff4756d3363cbb8d2ef1acd9bcdb85ff220a0116f74843bac2e4c0c79ceed000001d3677a8f142634e93ea00014082c1e1f03af7333333333333333333333333333333333333333333333333333333333333333300001d3677b8338232a2e0d714f1820922c85912266b270b551f97fd88c9ce96b02fbe0b94fedd3300001d3677b83381634e93ea000140820212203af734517c7bdf5187c55af4f8b61fdc321588c7ab768dee24b006df29106458d7cf00001d3677b833817a340a8997502684b5be64a7ba6e4f4f1393c8316bcf1149956bbf00b95dd25600001d3677a8f143634e93ea00014082c232403af734517c7bdf5187c55af4f8b61fdc321588c7ab768dee24b006df29106458d7cf00001d3677b83383a4dbec8658831b756fd060883f7d013972d9838f66cebcd2e28d66f2b2d6d46900001d3677b83381634e93ea00014082c252600a041297004c4b40000000000000000002e000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000082722f7566ede0ba3a333ac2ca4e9820a0eb28fa3c675e8c5b7378fbba7d487af6b69e73e012c2b93293818802ecda692b6a70c7bc140c3d6ba22159dd15949099300205203024272d0101a0280082729e73e012c2b93293818802ecda692b6a70c7bc140c3d6ba22159dd1594909930d8b330226ee7a4226c9a4e28167203a4dec229d3f51655422b56dd7122352fda020f0409283baec01811292d0082723145f857768776495406acbcd9f6451e43b82a7cf2b787bdfcd66f54e8f61eb20d9c166ab6df5f0d47d18e86fc45c1e5f42681c1184337189ef2e4aa3f2552c102052030342a2b0082720d9c166ab6df5f0d47d18e86fc45c1e5f42681c1184337189ef2e4aa3f2552c1f5fc1aa51cd06879f30dac067b3d17d0571b7c8ef15db6c57ce3e90f63e7d80802053030342a2b00a0431b9004c4b4000000000000000000960000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000ab69fe00000000000000000000000000000000000000000000000000000000000000013fccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccd283baec000000003a6cef706700c69d27d440009e42614c107ac00000000000000000640000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000a042665004c4b400000000000000000030000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000069600000009600000004000600000000000519ae84f17b8f8b22026a975ff55f1ab19fde4a768744d2178dfa63bb533e107a409026bc000120005bc00000000000000000000000012d452da449e50b8cf7dd27861f146122afe1b546bb8b70fc8216f0c614139f8e044f2ae40c"
+
+// testMasterTransactions - transactions of the account from proof, the newest one is first
+func testMasterTransactions(t *testing.T, master *BlockIDExt, acc []byte) ([]byte, []*cell.Cell) {
+	proof, err := hex.DecodeString(testMasterTxProof)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := cell.FromBOC(proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block, err := CheckBlockProof(root, master.RootHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var accounts tlb.ShardAccountBlocks
+	if err = tlb.LoadFromCellAsProof(&accounts, block.Extra.ShardAccountBlocks.BeginParse()); err != nil {
+		t.Fatal(err)
+	}
+
+	accSlice := accounts.Accounts.Get(cell.BeginCell().MustStoreSlice(acc, 256).EndCell()).BeginParse()
+	if err = tlb.LoadFromCell(new(tlb.CurrencyCollection), accSlice); err != nil {
+		t.Fatal(err)
+	}
+
+	var accBlock tlb.AccountBlock
+	if err = tlb.LoadFromCell(&accBlock, accSlice); err != nil {
+		t.Fatal(err)
+	}
+
+	kvs, err := accBlock.Transactions.LoadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var txs []*cell.Cell
+	for _, kv := range kvs {
+		if err = tlb.LoadFromCell(new(tlb.CurrencyCollection), kv.Value); err != nil {
+			t.Fatal(err)
+		}
+		tx, err := kv.Value.LoadRefCell()
+		if err != nil {
+			t.Fatal(err)
+		}
+		txs = append([]*cell.Cell{tx}, txs...)
+	}
+	return proof, txs
+}
+
+func TestAPIClient_ListTransactionsWithProof_BlockProofReuse(t *testing.T) {
+	master, _ := testMasterHeaderBlock(t)
+	addr := address.MustParseRawAddr("-1:" + hex.EncodeToString(bytes.Repeat([]byte{0x33}, 32)))
+
+	proof, txs := testMasterTransactions(t, master, addr.Data())
+	if len(txs) != 2 {
+		t.Fatal("incorrect transactions num in proof", len(txs))
+	}
+
+	var lookups, txRequests int
+	newClient := func(listBlock, lookupBlock *BlockIDExt, policy ProofCheckPolicy) *APIClient {
+		lookups, txRequests = 0, 0
+		return NewAPIClient(&testLiteClient{handler: func(req tl.Serializable) (tl.Serializable, error) {
+			switch r := req.(type) {
+			case GetTransactions:
+				return TransactionList{IDs: []*BlockIDExt{listBlock, listBlock}, Transactions: cell.ToBOCWithFlags(txs, false)}, nil
+			case LookupBlockWithProof:
+				lookups++
+				return LookupBlockResult{ID: lookupBlock, Mode: r.Mode, McBlockID: master, Header: proof}, nil
+			case GetOneTransaction:
+				txRequests++
+				for _, tx := range txs {
+					var parsed tlb.Transaction
+					if err := tlb.LoadFromCell(&parsed, tx.BeginParse()); err != nil {
+						t.Fatal(err)
+					}
+					if parsed.LT == uint64(r.LT) {
+						return TransactionInfo{ID: r.ID, Proof: proof, Transaction: tx.ToBOC()}, nil
+					}
+				}
+				return LSError{Code: 651, Text: "not found"}, nil
+			}
+			t.Fatal("unexpected request", req)
+			return nil, nil
+		}}, policy)
+	}
+
+	lastTx := txs[0]
+	res, err := newClient(master, master, ProofCheckPolicyFast).
+		ListTransactionsWithProof(context.Background(), master, addr, 2, 32119774000002, lastTx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 || res[0].LT != 32119774000001 || res[1].LT != 32119774000002 {
+		t.Fatal("incorrect transactions")
+	}
+	if lookups != 1 || txRequests != 2 {
+		t.Fatal("block should be proven once and each transaction separately", lookups, txRequests)
+	}
+
+	// list claims other block, but lookup proves that block with this seqno is different
+	other := master.Copy()
+	other.FileHash = bytes.Repeat([]byte{1}, 32)
+	if _, err = newClient(other, master, ProofCheckPolicyFast).
+		ListTransactionsWithProof(context.Background(), master, addr, 2, 32119774000002, lastTx.Hash()); err == nil {
+		t.Fatal("block not matching proven one should fail")
+	}
+	if txRequests != 0 {
+		t.Fatal("transaction should not be requested for not proven block")
+	}
+
+	if _, err = newClient(master, master, ProofCheckPolicyUnsafe).
+		ListTransactionsWithProof(context.Background(), master, addr, 2, 32119774000002, lastTx.Hash()); err == nil {
+		t.Fatal("unsafe policy should fail")
+	}
+}
@@ -0,0 +1,185 @@
+package ton
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/alan890104/tonutils-go/address"
+)
+
+// TrustedCheckpoint - master key block verified with proof chain
+type TrustedCheckpoint struct {
+	Block *BlockIDExt `json:"block"`
+}
+
+// TrustStore - storage of verified key blocks, used to resume proof chain verification
+// from the latest checkpoint instead of init block after restart
+type TrustStore interface {
+	// GetLatest - returns checkpoint with the highest seqno, or nil if store is empty
+	GetLatest(ctx context.Context) (*TrustedCheckpoint, error)
+	// Save - stores checkpoint, it should be already verified
+	Save(ctx context.Context, checkpoint *TrustedCheckpoint) error
+}
+
+type MemoryTrustStore struct {
+	checkpoints []*TrustedCheckpoint
+	mx          sync.RWMutex
+}
+
+// NewMemoryTrustStore - creates trust store which keeps checkpoints only while process is running
+func NewMemoryTrustStore() *MemoryTrustStore {
+	return &MemoryTrustStore{}
+}
+
+func (m *MemoryTrustStore) GetLatest(_ context.Context) (*TrustedCheckpoint, error) {
+	m.mx.RLock()
+	defer m.mx.RUnlock()
+
+	if len(m.checkpoints) == 0 {
+		return nil, nil
+	}
+	return m.checkpoints[len(m.checkpoints)-1], nil
+}
+
+func (m *MemoryTrustStore) Save(_ context.Context, checkpoint *TrustedCheckpoint) error {
+	if err := validateCheckpoint(checkpoint); err != nil {
+		return err
+	}
+
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	m.checkpoints = insertCheckpoint(m.checkpoints, checkpoint)
+	return nil
+}
+
+type FileTrustStore struct {
+	path        string
+	checkpoints []*TrustedCheckpoint
+	mx          sync.RWMutex
+}
+
+// NewFileTrustStore - creates trust store which keeps checkpoints in json file by the given path,
+// existing checkpoints are loaded from it, file is created on first save
+func NewFileTrustStore(path string) (*FileTrustStore, error) {
+	s := &FileTrustStore{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read trust store file: %w", err)
+	}
+
+	if err = json.Unmarshal(data, &s.checkpoints); err != nil {
+		return nil, fmt.Errorf("failed to parse trust store file: %w", err)
+	}
+
+	for _, cp := range s.checkpoints {
+		if err = validateCheckpoint(cp); err != nil {
+			return nil, fmt.Errorf("invalid checkpoint in trust store file: %w", err)
+		}
+	}
+
+	sort.Slice(s.checkpoints, func(i, j int) bool {
+		return s.checkpoints[i].Block.SeqNo < s.checkpoints[j].Block.SeqNo
+	})
+	return s, nil
+}
+
+func (f *FileTrustStore) GetLatest(_ context.Context) (*TrustedCheckpoint, error) {
+	f.mx.RLock()
+	defer f.mx.RUnlock()
+
+	if len(f.checkpoints) == 0 {
+		return nil, nil
+	}
+	return f.checkpoints[len(f.checkpoints)-1], nil
+}
+
+func (f *FileTrustStore) Save(_ context.Context, checkpoint *TrustedCheckpoint) error {
+	if err := validateCheckpoint(checkpoint); err != nil {
+		return err
+	}
+
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	list := insertCheckpoint(append([]*TrustedCheckpoint{}, f.checkpoints...), checkpoint)
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize checkpoints: %w", err)
+	}
+
+	// write to temp file and rename, to not corrupt store on crash
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to sync temp file: %w", err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	if err = os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("failed to replace trust store file: %w", err)
+	}
+
+	f.checkpoints = list
+	return nil
+}
+
+func validateCheckpoint(checkpoint *TrustedCheckpoint) error {
+	if checkpoint == nil || checkpoint.Block == nil {
+		return fmt.Errorf("checkpoint has no block")
+	}
+
+	if checkpoint.Block.Workchain != address.MasterchainID {
+		return fmt.Errorf("checkpoint block should be from masterchain")
+	}
+
+	if len(checkpoint.Block.RootHash) != 32 || len(checkpoint.Block.FileHash) != 32 {
+		return fmt.Errorf("checkpoint block has incorrect hashes")
+	}
+	return nil
+}
+
+// insertCheckpoint - puts checkpoint to the list sorted by seqno, replacing one with the same seqno
+func insertCheckpoint(list []*TrustedCheckpoint, checkpoint *TrustedCheckpoint) []*TrustedCheckpoint {
+	cp := &TrustedCheckpoint{
+		Block: checkpoint.Block.Copy(),
+	}
+
+	i := sort.Search(len(list), func(i int) bool {
+		return list[i].Block.SeqNo >= cp.Block.SeqNo
+	})
+
+	if i < len(list) && list[i].Block.SeqNo == cp.Block.SeqNo {
+		list[i] = cp
+		return list
+	}
+
+	list = append(list, nil)
+	copy(list[i+1:], list[i:])
+	list[i] = cp
+	return list
+}
@@ -0,0 +1,171 @@
+package ton
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testCheckpoint(seqno uint32, fill byte) *TrustedCheckpoint {
+	return &TrustedCheckpoint{
+		Block: testMasterBlockID(seqno, fill),
+	}
+}
+
+func TestMemoryTrustStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryTrustStore()
+
+	cp, err := s.GetLatest(ctx)
+	if err != nil || cp != nil {
+		t.Fatal("store should be empty", err)
+	}
+
+	for _, c := range []*TrustedCheckpoint{testCheckpoint(20, 1), testCheckpoint(10, 2), testCheckpoint(20, 3)} {
+		if err = s.Save(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cp, err = s.GetLatest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.Block.SeqNo != 20 || cp.Block.RootHash[0] != 3 || len(s.checkpoints) != 2 {
+		t.Fatal("incorrect latest checkpoint")
+	}
+
+	bad := testCheckpoint(30, 1)
+	bad.Block.Workchain = 0
+	if err = s.Save(ctx, bad); err == nil {
+		t.Fatal("not masterchain checkpoint should fail")
+	}
+}
+
+func TestFileTrustStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "trust.json")
+
+	s, err := NewFileTrustStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = s.Save(ctx, testCheckpoint(100, 5)); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Save(ctx, testCheckpoint(50, 7)); err != nil {
+		t.Fatal(err)
+	}
+
+	s2, err := NewFileTrustStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cp, err := s2.GetLatest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := testCheckpoint(100, 5)
+	if !cp.Block.Equals(want.Block) {
+		t.Fatal("incorrect loaded checkpoint")
+	}
+
+	if err = os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = NewFileTrustStore(path); err == nil {
+		t.Fatal("corrupted file should fail")
+	}
+}
+
+func TestAPIClient_SetTrustStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryTrustStore()
+	if err := s.Save(ctx, testCheckpoint(100, 5)); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewAPIClient(nil, ProofCheckPolicySecure)
+	c.SetTrustedBlock(testCheckpoint(10, 1).Block)
+
+	if err := c.SetTrustStore(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.trustedBlock.SeqNo != 100 {
+		t.Fatal("trusted block should be taken from store")
+	}
+
+	child := c.waitForBlock(100)
+	if child.getTrustStore() != s {
+		t.Fatal("store should be shared with child clients")
+	}
+
+	// store can be replaced while proofs are checked
+	done := make(chan bool)
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			_ = child.getTrustStore()
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if err := c.SetTrustStore(ctx, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-done
+}
+
+type failingTrustStore struct {
+	saves int
+}
+
+func (f *failingTrustStore) GetLatest(_ context.Context) (*TrustedCheckpoint, error) {
+	return nil, nil
+}
+
+func (f *failingTrustStore) Save(_ context.Context, _ *TrustedCheckpoint) error {
+	f.saves++
+	return errors.New("disk is full")
+}
+
+func TestAPIClient_SaveCheckpoint(t *testing.T) {
+	ctx := context.Background()
+
+	// no liteserver requests are expected, checkpoint contains only verified block
+	c := NewAPIClient(nil, ProofCheckPolicySecure)
+	c.saveCheckpoint(ctx, testMasterBlockID(10, 1))
+
+	s := NewMemoryTrustStore()
+	if err := c.SetTrustStore(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+
+	key := testMasterBlockID(20, 2)
+	c.saveCheckpoint(ctx, key)
+
+	cp, err := s.GetLatest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp == nil || !cp.Block.Equals(key) {
+		t.Fatal("key block should be saved")
+	}
+
+	failing := &failingTrustStore{}
+	if err = c.SetTrustStore(ctx, failing); err != nil {
+		t.Fatal(err)
+	}
+
+	// store error should not break proof chain verification, so it is only logged
+	c.saveCheckpoint(ctx, key)
+	if failing.saves != 1 {
+		t.Fatal("checkpoint should be passed to store")
+	}
+}
@@ -0,0 +1,450 @@
+package ton
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alan890104/tonutils-go/address"
+	"github.com/alan890104/tonutils-go/tlb"
+)
+
+// TransactionCursor - last processed transaction of the account
+type TransactionCursor struct {
+	LT   uint64
+	Hash []byte
+}
+
+// TransactionCursorStore - storage of per address cursors and of the last processed master block,
+// used to not deliver already processed transactions again and to fill gaps after restart
+type TransactionCursorStore interface {
+	// GetCursor - returns cursor of the address, or nil if address has no processed transactions
+	GetCursor(ctx context.Context, addr *address.Address) (*TransactionCursor, error)
+	SetCursor(ctx context.Context, addr *address.Address, cursor *TransactionCursor) error
+	// GetMasterSeqno - returns seqno of the last processed master block, or 0 if nothing was processed
+	GetMasterSeqno(ctx context.Context) (uint32, error)
+	SetMasterSeqno(ctx context.Context, seqno uint32) error
+}
+
+type MemoryTransactionCursorStore struct {
+	cursors     map[string]*TransactionCursor
+	masterSeqno uint32
+	mx          sync.RWMutex
+}
+
+// NewMemoryTransactionCursorStore - creates cursor store which keeps cursors only while process is running
+func NewMemoryTransactionCursorStore() *MemoryTransactionCursorStore {
+	return &MemoryTransactionCursorStore{
+		cursors: map[string]*TransactionCursor{},
+	}
+}
+
+func (m *MemoryTransactionCursorStore) GetCursor(_ context.Context, addr *address.Address) (*TransactionCursor, error) {
+	m.mx.RLock()
+	defer m.mx.RUnlock()
+
+	cur := m.cursors[addr.StringRaw()]
+	if cur == nil {
+		return nil, nil
+	}
+	return &TransactionCursor{LT: cur.LT, Hash: append([]byte{}, cur.Hash...)}, nil
+}
+
+func (m *MemoryTransactionCursorStore) SetCursor(_ context.Context, addr *address.Address, cursor *TransactionCursor) error {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	m.cursors[addr.StringRaw()] = &TransactionCursor{LT: cursor.LT, Hash: append([]byte{}, cursor.Hash...)}
+	return nil
+}
+
+func (m *MemoryTransactionCursorStore) GetMasterSeqno(_ context.Context) (uint32, error) {
+	m.mx.RLock()
+	defer m.mx.RUnlock()
+
+	return m.masterSeqno, nil
+}
+
+func (m *MemoryTransactionCursorStore) SetMasterSeqno(_ context.Context, seqno uint32) error {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	m.masterSeqno = seqno
+	return nil
+}
+
+// TransactionSubscription - watches transactions of many addresses at once.
+// Instead of polling each account, it scans transactions of new blocks once
+// and requests transactions only of the watched accounts which were active.
+type TransactionSubscription struct {
+	api   APIClientWrapped
+	store TransactionCursorStore
+
+	addresses map[string]*address.Address
+	onError   func(addr *address.Address, err error)
+	mx        sync.RWMutex
+}
+
+// TransactionGapError - transactions of the address cannot be delivered, because its history
+// is not available on the node or does not contain the last processed transaction.
+// Such transactions are skipped, retry will not help.
+type TransactionGapError struct {
+	Address *address.Address
+	Err     error
+}
+
+func (e *TransactionGapError) Error() string {
+	return fmt.Sprintf("transactions of %s cannot be delivered: %s", e.Address.String(), e.Err.Error())
+}
+
+func (e *TransactionGapError) Unwrap() error {
+	return e.Err
+}
+
+// max transactions of the account kept in memory while the gap is filled,
+// when there are more, newer pages are dropped and requested again before sending
+const maxGapTransactionsInMemory = 256
+
+type accountActivity struct {
+	addr    *address.Address
+	minLT   uint64
+	maxLT   uint64
+	maxHash []byte
+}
+
+// txPage - transactions of the account listed from lt and hash, from new to old
+type txPage struct {
+	lt   uint64
+	hash []byte
+	// nil when dropped to save memory
+	txs []*tlb.Transaction
+}
+
+// NewTransactionSubscription - creates subscription, cursors of addresses are loaded from and saved to the store
+func NewTransactionSubscription(api APIClientWrapped, store TransactionCursorStore) *TransactionSubscription {
+	return &TransactionSubscription{
+		api:       api,
+		store:     store,
+		addresses: map[string]*address.Address{},
+	}
+}
+
+// AddAddresses - starts watching the addresses, can be called while subscription is running
+func (s *TransactionSubscription) AddAddresses(addrs ...*address.Address) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	for _, addr := range addrs {
+		s.addresses[addr.StringRaw()] = addr
+	}
+}
+
+// RemoveAddresses - stops watching the addresses, can be called while subscription is running
+func (s *TransactionSubscription) RemoveAddresses(addrs ...*address.Address) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	for _, addr := range addrs {
+		delete(s.addresses, addr.StringRaw())
+	}
+}
+
+// SetErrorHandler - sets handler which is called when transactions of the address are skipped,
+// error is always *TransactionGapError. Without handler such errors are only logged.
+func (s *TransactionSubscription) SetErrorHandler(handler func(addr *address.Address, err error)) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.onError = handler
+}
+
+func (s *TransactionSubscription) reportError(addr *address.Address, err error) {
+	s.mx.RLock()
+	handler := s.onError
+	s.mx.RUnlock()
+
+	if handler == nil {
+		log.Println("[WARNING]", err.Error())
+		return
+	}
+	handler(addr, err)
+}
+
+func (s *TransactionSubscription) getAddress(key string) *address.Address {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	return s.addresses[key]
+}
+
+// Start - scans blocks starting from master block fromSeqno and sends transactions of watched addresses
+// to the channel, from old to new for each address. When fromSeqno is 0, scan continues after the last
+// processed master block saved in the store, or starts from the current one if there is nothing saved.
+// Cursor of the address is saved after its transactions are sent, so after restart
+// only not yet sent transactions are delivered. When transactions of the address cannot be delivered,
+// they are skipped and reported to error handler, other addresses are processed as usual.
+// Channel is closed when context is done.
+func (s *TransactionSubscription) Start(workerCtx context.Context, fromSeqno uint32, channel chan<- *tlb.Transaction) {
+	defer close(channel)
+
+	wait := 3 * time.Second
+	for fromSeqno == 0 {
+		ctx, cancel := context.WithTimeout(workerCtx, 10*time.Second)
+		seqno, err := s.startSeqno(ctx)
+		cancel()
+		if err == nil {
+			fromSeqno = seqno
+			break
+		}
+
+		select {
+		case <-workerCtx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+
+	updates := make(chan *BlockUpdate)
+	go s.api.SubscribeOnBlocks(workerCtx, fromSeqno, updates)
+
+	for upd := range updates {
+		// retry the same update until success, already sent transactions are skipped using cursors
+		for {
+			err := s.processUpdate(workerCtx, upd, channel)
+			if err == nil {
+				break
+			}
+
+			select {
+			case <-workerCtx.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+	}
+}
+
+// startSeqno - returns master block after the last processed one, or the current one if nothing was processed
+func (s *TransactionSubscription) startSeqno(ctx context.Context) (uint32, error) {
+	last, err := s.store.GetMasterSeqno(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last processed master seqno: %w", err)
+	}
+
+	if last > 0 {
+		return last + 1, nil
+	}
+
+	master, err := s.api.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get masterchain info: %w", err)
+	}
+	return master.SeqNo, nil
+}
+
+func (s *TransactionSubscription) processUpdate(ctx context.Context, upd *BlockUpdate, channel chan<- *tlb.Transaction) error {
+	blocks := append(append([]*BlockIDExt{}, upd.Shards...), upd.Master)
+	api := s.api.WaitForBlock(upd.Master.SeqNo)
+
+	active := map[string]*accountActivity{}
+	for _, block := range blocks {
+		var after *TransactionID3
+		for more := true; more; {
+			qCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			ids, hasMore, err := api.GetBlockTransactionsV2(qCtx, block, 256, after)
+			cancel()
+			if err != nil {
+				return fmt.Errorf("failed to get transactions of block %d: %w", block.SeqNo, err)
+			}
+
+			more = hasMore
+			if more && len(ids) > 0 {
+				after = ids[len(ids)-1].ID3()
+			}
+
+			for _, id := range ids {
+				addr := address.NewAddress(0, byte(block.Workchain), id.Account)
+				key := addr.StringRaw()
+
+				watched := s.getAddress(key)
+				if watched == nil {
+					continue
+				}
+
+				act := active[key]
+				if act == nil {
+					act = &accountActivity{addr: watched, minLT: id.LT}
+					active[key] = act
+				}
+
+				if id.LT < act.minLT {
+					act.minLT = id.LT
+				}
+				if id.LT > act.maxLT {
+					act.maxLT, act.maxHash = id.LT, id.Hash
+				}
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(active))
+	for key := range active {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		act := active[key]
+		err := s.processAccount(ctx, act, channel)
+		if err == nil {
+			continue
+		}
+
+		var gapErr *TransactionGapError
+		if !errors.As(err, &gapErr) {
+			return err
+		}
+
+		// retry will not help, so skip to the last transaction of this update and continue with others
+		s.reportError(act.addr, gapErr)
+		if err = s.store.SetCursor(ctx, act.addr, &TransactionCursor{
+			LT:   act.maxLT,
+			Hash: act.maxHash,
+		}); err != nil {
+			return fmt.Errorf("failed to save cursor of %s: %w", act.addr.String(), err)
+		}
+	}
+
+	if err := s.store.SetMasterSeqno(ctx, upd.Master.SeqNo); err != nil {
+		return fmt.Errorf("failed to save master seqno: %w", err)
+	}
+	return nil
+}
+
+func (s *TransactionSubscription) processAccount(ctx context.Context, act *accountActivity, channel chan<- *tlb.Transaction) error {
+	cur, err := s.store.GetCursor(ctx, act.addr)
+	if err != nil {
+		return fmt.Errorf("failed to get cursor of %s: %w", act.addr.String(), err)
+	}
+
+	stopLT := act.minLT - 1
+	if cur != nil {
+		if cur.LT >= act.maxLT {
+			// already processed
+			return nil
+		}
+		// also deliver transactions which were missed since the last processed one
+		stopLT = cur.LT
+	}
+
+	// walk back to the cursor, only limited number of transactions is kept in memory
+	var pages []*txPage
+	var kept int
+	lt, hash := act.maxLT, act.maxHash
+	reached := false
+	for !reached && lt > stopLT {
+		res, err := s.listTransactions(ctx, act.addr, lt, hash)
+		if err != nil {
+			return err
+		}
+
+		page := &txPage{lt: lt, hash: hash}
+		for i := len(res) - 1; i >= 0; i-- {
+			if res[i].LT <= stopLT {
+				if err = checkTransactionCursor(cur, res[i].LT, res[i].Hash); err != nil {
+					return &TransactionGapError{Address: act.addr, Err: err}
+				}
+				reached = true
+				break
+			}
+			page.txs = append(page.txs, res[i])
+		}
+		pages = append(pages, page)
+		lt, hash = res[0].PrevTxLT, res[0].PrevTxHash
+
+		kept += len(page.txs)
+		for i := 0; kept > maxGapTransactionsInMemory && i < len(pages)-1; i++ {
+			kept -= len(pages[i].txs)
+			pages[i].txs = nil
+		}
+	}
+
+	if !reached {
+		// previous transaction of the oldest listed one should be the cursor
+		if err = checkTransactionCursor(cur, lt, hash); err != nil {
+			return &TransactionGapError{Address: act.addr, Err: err}
+		}
+	}
+
+	// send in correct time order (from old to new), cursor is saved after each page
+	for i := len(pages) - 1; i >= 0; i-- {
+		txs := pages[i].txs
+		if txs == nil {
+			res, err := s.listTransactions(ctx, act.addr, pages[i].lt, pages[i].hash)
+			if err != nil {
+				return err
+			}
+
+			for j := len(res) - 1; j >= 0 && res[j].LT > stopLT; j-- {
+				txs = append(txs, res[j])
+			}
+		}
+
+		for j := len(txs) - 1; j >= 0; j-- {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case channel <- txs[j]:
+			}
+		}
+
+		if len(txs) > 0 {
+			if err = s.store.SetCursor(ctx, act.addr, &TransactionCursor{
+				LT:   txs[0].LT,
+				Hash: txs[0].Hash,
+			}); err != nil {
+				return fmt.Errorf("failed to save cursor of %s: %w", act.addr.String(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// listTransactions - lists page of account transactions ending at lt, result is from old to new
+func (s *TransactionSubscription) listTransactions(ctx context.Context, addr *address.Address, lt uint64, hash []byte) ([]*tlb.Transaction, error) {
+	qCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	res, err := s.api.ListTransactions(qCtx, addr, 16, lt, hash)
+	if err != nil {
+		var lsErr LSError
+		if errors.Is(err, ErrNoTransactionsWereFound) || (errors.As(err, &lsErr) && lsErr.Code == -400) {
+			// history is not available on the node, gap cannot be filled
+			return nil, &TransactionGapError{Address: addr, Err: fmt.Errorf("history at lt %d is not available: %w", lt, err)}
+		}
+		return nil, fmt.Errorf("failed to list transactions of %s: %w", addr.String(), err)
+	}
+
+	if len(res) == 0 {
+		return nil, &TransactionGapError{Address: addr, Err: fmt.Errorf("history at lt %d is not available: %w", lt, ErrNoTransactionsWereFound)}
+	}
+	return res, nil
+}
+
+// checkTransactionCursor - verifies that transaction where walk back stopped is the last processed one
+func checkTransactionCursor(cur *TransactionCursor, lt uint64, hash []byte) error {
+	if cur == nil {
+		// nothing was processed, walk stops at the first transaction of the block
+		return nil
+	}
+
+	if lt != cur.LT || !bytes.Equal(hash, cur.Hash) {
+		return fmt.Errorf("history does not contain processed transaction %d, got %d", cur.LT, lt)
+	}
+	return nil
+}
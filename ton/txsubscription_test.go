@@ -0,0 +1,313 @@
+package ton
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/alan890104/tonutils-go/address"
+	"github.com/alan890104/tonutils-go/tlb"
+)
+
+type txListMock struct {
+	APIClientWrapped
+	txs      []*tlb.Transaction
+	requests int
+
+	// for process update, history of broken accounts is not available
+	blockTxs []TransactionShortInfo
+	broken   map[string]bool
+}
+
+func (m *txListMock) WaitForBlock(_ uint32) APIClientWrapped {
+	return m
+}
+
+func (m *txListMock) GetBlockTransactionsV2(_ context.Context, block *BlockIDExt, _ uint32, _ ...*TransactionID3) ([]TransactionShortInfo, bool, error) {
+	if block.Workchain != 0 {
+		return nil, false, nil
+	}
+	return m.blockTxs, false, nil
+}
+
+func (m *txListMock) CurrentMasterchainInfo(_ context.Context) (*BlockIDExt, error) {
+	return testMasterBlockID(100, 0), nil
+}
+
+func (m *txListMock) ListTransactions(_ context.Context, addr *address.Address, num uint32, lt uint64, _ []byte) ([]*tlb.Transaction, error) {
+	m.requests++
+	if m.broken[addr.StringRaw()] {
+		return nil, LSError{Code: -400, Text: "pruned"}
+	}
+
+	var res []*tlb.Transaction
+	for i := len(m.txs) - 1; i >= 0 && uint32(len(res)) < num; i-- {
+		if m.txs[i].LT <= lt {
+			res = append([]*tlb.Transaction{m.txs[i]}, res...)
+		}
+	}
+	if len(res) == 0 {
+		return nil, ErrNoTransactionsWereFound
+	}
+	return res, nil
+}
+
+func testTxHash(lt uint64) []byte {
+	h := make([]byte, 32)
+	binary.BigEndian.PutUint64(h, lt)
+	return h
+}
+
+func testTxHistory(from, to uint64) *txListMock {
+	mock := &txListMock{}
+	for lt := from; lt <= to; lt++ {
+		mock.txs = append(mock.txs, &tlb.Transaction{
+			LT:         lt,
+			Hash:       testTxHash(lt),
+			PrevTxLT:   lt - 1,
+			PrevTxHash: testTxHash(lt - 1),
+		})
+	}
+	return mock
+}
+
+func TestTransactionSubscription_ProcessAccount(t *testing.T) {
+	ctx := context.Background()
+	addr := address.MustParseAddr("EQCD39VS5jcptHL8vMjEXrzGaRcCVYto7HUn4bpAOg8xqB2N")
+
+	mock := testTxHistory(1, 40)
+
+	store := NewMemoryTransactionCursorStore()
+	if err := store.SetCursor(ctx, addr, &TransactionCursor{LT: 10, Hash: testTxHash(10)}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewTransactionSubscription(mock, store)
+	act := &accountActivity{addr: addr, minLT: 35, maxLT: 40, maxHash: testTxHash(40)}
+
+	ch := make(chan *tlb.Transaction, 100)
+	if err := s.processAccount(ctx, act, ch); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ch) != 30 {
+		t.Fatal("missed transactions should be delivered, got", len(ch))
+	}
+	for lt := uint64(11); lt <= 40; lt++ {
+		if tx := <-ch; tx.LT != lt {
+			t.Fatal("incorrect order, want", lt, "got", tx.LT)
+		}
+	}
+
+	cur, err := store.GetCursor(ctx, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cur.LT != 40 {
+		t.Fatal("cursor should be moved to the last transaction")
+	}
+
+	// already processed activity should not be delivered again
+	if err = s.processAccount(ctx, act, ch); err != nil {
+		t.Fatal(err)
+	}
+	if len(ch) != 0 {
+		t.Fatal("transactions should not be delivered twice")
+	}
+
+	// without cursor only transactions of the block are delivered
+	other := address.MustParseAddr("EQAYqo4u7VF0fa4DPAebk4g9lBytj2VFny7pzXR0trjtXQaO")
+	act = &accountActivity{addr: other, minLT: 38, maxLT: 40, maxHash: testTxHash(40)}
+	if err = s.processAccount(ctx, act, ch); err != nil {
+		t.Fatal(err)
+	}
+	if len(ch) != 3 || (<-ch).LT != 38 {
+		t.Fatal("only block transactions should be delivered")
+	}
+}
+
+func TestTransactionSubscription_ProcessAccount_Gap(t *testing.T) {
+	ctx := context.Background()
+	addr := address.MustParseAddr("EQCD39VS5jcptHL8vMjEXrzGaRcCVYto7HUn4bpAOg8xqB2N")
+	act := &accountActivity{addr: addr, minLT: 40, maxLT: 40, maxHash: testTxHash(40)}
+	ch := make(chan *tlb.Transaction, 1000)
+
+	// cursor transaction with other hash means that history or cursor is wrong
+	store := NewMemoryTransactionCursorStore()
+	if err := store.SetCursor(ctx, addr, &TransactionCursor{LT: 10, Hash: testTxHash(11)}); err != nil {
+		t.Fatal(err)
+	}
+	var gapErr *TransactionGapError
+	if err := NewTransactionSubscription(testTxHistory(1, 40), store).processAccount(ctx, act, ch); !errors.As(err, &gapErr) {
+		t.Fatal("cursor hash mismatch should fail with gap error, got", err)
+	}
+
+	// cursor is in the middle of the page
+	mock := testTxHistory(1, 40)
+	mock.txs = append(mock.txs[:9], mock.txs[10:]...)
+	if err := store.SetCursor(ctx, addr, &TransactionCursor{LT: 10, Hash: testTxHash(10)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewTransactionSubscription(mock, store).processAccount(ctx, act, ch); !errors.As(err, &gapErr) {
+		t.Fatal("missing cursor transaction should fail with gap error, got", err)
+	}
+
+	// history before lt 20 is not available, so gap cannot be filled
+	if err := NewTransactionSubscription(testTxHistory(20, 40), store).processAccount(ctx, act, ch); !errors.Is(err, ErrNoTransactionsWereFound) ||
+		!errors.As(err, &gapErr) || gapErr.Address != addr {
+		t.Fatal("unavailable history should fail with gap error, got", err)
+	}
+
+	if len(ch) != 0 {
+		t.Fatal("nothing should be delivered when gap cannot be filled")
+	}
+	if cur, _ := store.GetCursor(ctx, addr); cur.LT != 10 {
+		t.Fatal("cursor should not be moved when gap cannot be filled")
+	}
+
+	// big gap is delivered in pages, cursor is moved after each of them
+	mock = testTxHistory(1, 1000)
+	act = &accountActivity{addr: addr, minLT: 1000, maxLT: 1000, maxHash: testTxHash(1000)}
+	if err := NewTransactionSubscription(mock, store).processAccount(ctx, act, ch); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ch) != 990 {
+		t.Fatal("all missed transactions should be delivered, got", len(ch))
+	}
+	for lt := uint64(11); lt <= 1000; lt++ {
+		if tx := <-ch; tx.LT != lt {
+			t.Fatal("incorrect order, want", lt, "got", tx.LT)
+		}
+	}
+
+	if cur, _ := store.GetCursor(ctx, addr); cur.LT != 1000 {
+		t.Fatal("cursor should be moved to the last transaction")
+	}
+
+	pages := (990 + 15) / 16
+	if mock.requests <= pages || mock.requests > 2*pages {
+		t.Fatal("dropped pages should be requested again, requests:", mock.requests)
+	}
+}
+
+func TestTransactionSubscription_ProcessUpdate_Gap(t *testing.T) {
+	ctx := context.Background()
+	broken := address.MustParseAddr("EQCD39VS5jcptHL8vMjEXrzGaRcCVYto7HUn4bpAOg8xqB2N")
+	ok := address.MustParseAddr("EQAYqo4u7VF0fa4DPAebk4g9lBytj2VFny7pzXR0trjtXQaO")
+
+	mock := testTxHistory(1, 40)
+	mock.broken = map[string]bool{broken.StringRaw(): true}
+	for _, addr := range []*address.Address{broken, ok} {
+		mock.blockTxs = append(mock.blockTxs, TransactionShortInfo{Account: addr.Data(), LT: 40, Hash: testTxHash(40)})
+	}
+
+	store := NewMemoryTransactionCursorStore()
+	for _, addr := range []*address.Address{broken, ok} {
+		if err := store.SetCursor(ctx, addr, &TransactionCursor{LT: 30, Hash: testTxHash(30)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewTransactionSubscription(mock, store)
+	s.AddAddresses(broken, ok)
+
+	var reported []*address.Address
+	s.SetErrorHandler(func(addr *address.Address, err error) {
+		var gapErr *TransactionGapError
+		if !errors.As(err, &gapErr) {
+			t.Fatal("gap error expected, got", err)
+		}
+		reported = append(reported, addr)
+	})
+
+	ch := make(chan *tlb.Transaction, 100)
+	upd := &BlockUpdate{Master: testMasterBlockID(100, 0), Shards: []*BlockIDExt{testBlockID(0, 0x8000000000000000, 50, 0)}}
+	if err := s.processUpdate(ctx, upd, ch); err != nil {
+		t.Fatal("failure of one address should not fail update:", err)
+	}
+
+	if len(reported) != 1 || reported[0] != broken {
+		t.Fatal("broken address should be reported once")
+	}
+
+	if len(ch) != 10 {
+		t.Fatal("transactions of other address should be delivered, got", len(ch))
+	}
+
+	for _, addr := range []*address.Address{broken, ok} {
+		if cur, _ := store.GetCursor(ctx, addr); cur.LT != 40 || !bytes.Equal(cur.Hash, testTxHash(40)) {
+			t.Fatal("cursor should be moved to the last transaction of update")
+		}
+	}
+
+	if seqno, _ := store.GetMasterSeqno(ctx); seqno != 100 {
+		t.Fatal("update should be marked as processed")
+	}
+
+	// transient errors are still returned to retry the update
+	mock.broken = nil
+	mock.txs = nil
+	mock.blockTxs = []TransactionShortInfo{{Account: ok.Data(), LT: 50, Hash: testTxHash(50)}}
+	s.api = &txListFailMock{txListMock: mock}
+	if err := s.processUpdate(ctx, upd, ch); err == nil {
+		t.Fatal("transient error should fail update")
+	}
+	if len(reported) != 1 {
+		t.Fatal("transient error should not be reported")
+	}
+}
+
+type txListFailMock struct {
+	*txListMock
+}
+
+func (m *txListFailMock) WaitForBlock(_ uint32) APIClientWrapped {
+	return m
+}
+
+func (m *txListFailMock) ListTransactions(_ context.Context, _ *address.Address, _ uint32, _ uint64, _ []byte) ([]*tlb.Transaction, error) {
+	return nil, errors.New("timeout")
+}
+
+func TestTransactionSubscription_StartSeqno(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryTransactionCursorStore()
+	s := NewTransactionSubscription(&txListMock{}, store)
+
+	seqno, err := s.startSeqno(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seqno != 100 {
+		t.Fatal("should start from current block when nothing was processed")
+	}
+
+	if err = store.SetMasterSeqno(ctx, 50); err != nil {
+		t.Fatal(err)
+	}
+
+	if seqno, err = s.startSeqno(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if seqno != 51 {
+		t.Fatal("should continue after last processed block")
+	}
+}
+
+func TestTransactionSubscription_Addresses(t *testing.T) {
+	addr := address.MustParseAddr("EQCD39VS5jcptHL8vMjEXrzGaRcCVYto7HUn4bpAOg8xqB2N")
+	s := NewTransactionSubscription(nil, NewMemoryTransactionCursorStore())
+
+	s.AddAddresses(addr)
+	if s.getAddress(address.NewAddress(0, byte(addr.Workchain()), addr.Data()).StringRaw()) == nil {
+		t.Fatal("address should be watched")
+	}
+
+	s.RemoveAddresses(addr)
+	if s.getAddress(addr.StringRaw()) != nil {
+		t.Fatal("address should not be watched")
+	}
+}
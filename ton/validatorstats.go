@@ -0,0 +1,123 @@
+package ton
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/alan890104/tonutils-go/tl"
+	"github.com/alan890104/tonutils-go/tlb"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+func init() {
+	tl.Register(GetValidatorStats{}, "liteServer.getValidatorStats#091a58bc mode:# id:tonNode.blockIdExt limit:int start_after:mode.0?int256 modified_after:mode.2?int = liteServer.ValidatorStats")
+	tl.Register(ValidatorStats{}, "liteServer.validatorStats mode:# id:tonNode.blockIdExt count:int complete:Bool state_proof:bytes data_proof:bytes = liteServer.ValidatorStats")
+}
+
+type GetValidatorStats struct {
+	Mode          uint32      `tl:"flags"`
+	ID            *BlockIDExt `tl:"struct"`
+	Limit         int32       `tl:"int"`
+	StartAfter    []byte      `tl:"?0 int256"`
+	ModifiedAfter uint32      `tl:"?2 int"`
+}
+
+type ValidatorStats struct {
+	Mode       uint32      `tl:"int"`
+	ID         *BlockIDExt `tl:"struct"`
+	Count      int32       `tl:"int"`
+	Complete   bool        `tl:"bool"`
+	StateProof *cell.Cell  `tl:"cell"`
+	DataProof  *cell.Cell  `tl:"cell"`
+}
+
+// GetValidatorStats - gets block creation stats of validators from the master block state, ordered by public key.
+// Stats are returned starting after startAfter key (if not nil) and only when updated after modifiedAfter (if not 0).
+// Second return value is true when there are no more records.
+func (c *APIClient) GetValidatorStats(ctx context.Context, master *BlockIDExt, limit int32, startAfter []byte, modifiedAfter uint32) ([]*tlb.ValidatorCreateStats, bool, error) {
+	if startAfter != nil && len(startAfter) != 32 {
+		return nil, false, fmt.Errorf("start after key should be 32 bytes")
+	}
+
+	var mode uint32
+	if startAfter != nil {
+		mode |= 1 << 0
+	}
+	if modifiedAfter != 0 {
+		mode |= 1 << 2
+	}
+
+	var resp tl.Serializable
+	err := c.client.QueryLiteserver(ctx, GetValidatorStats{
+		Mode:          mode,
+		ID:            master,
+		Limit:         limit,
+		StartAfter:    startAfter,
+		ModifiedAfter: modifiedAfter,
+	}, &resp)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch t := resp.(type) {
+	case ValidatorStats:
+		if !t.ID.Equals(master) {
+			return nil, false, fmt.Errorf("incorrect block in response")
+		}
+
+		if t.Count < 0 || t.Count > limit {
+			return nil, false, fmt.Errorf("incorrect records count in response")
+		}
+
+		// stats are taken from the proof, so it is always verified
+		stateExtra, err := CheckShardMcStateExtraProof(master, []*cell.Cell{t.StateProof, t.DataProof})
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to check validator stats proof: %w", err)
+		}
+
+		res, err := filterValidatorStats(stateExtra.Info.BlockCreateStats, t.Count, startAfter, modifiedAfter)
+		if err != nil {
+			return nil, false, err
+		}
+		return res, t.Complete, nil
+	case LSError:
+		return nil, false, t
+	}
+	return nil, false, errUnexpectedResponse(resp)
+}
+
+// filterValidatorStats - selects count records after startAfter key which were updated after modifiedAfter,
+// proof may contain pruned branches, so all the requested records should be present in it.
+func filterValidatorStats(stats *tlb.BlockCreateStats, count int32, startAfter []byte, modifiedAfter uint32) ([]*tlb.ValidatorCreateStats, error) {
+	if stats == nil {
+		return nil, fmt.Errorf("no block create stats in state")
+	}
+
+	all, err := stats.LoadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load block create stats: %w", err)
+	}
+
+	res := make([]*tlb.ValidatorCreateStats, 0, count)
+	for _, st := range all {
+		if len(res) == int(count) {
+			break
+		}
+
+		if startAfter != nil && bytes.Compare(st.PublicKey, startAfter) <= 0 {
+			continue
+		}
+
+		if modifiedAfter != 0 &&
+			st.Stats.McBlocks.LastUpdated < modifiedAfter && st.Stats.ShardBlocks.LastUpdated < modifiedAfter {
+			continue
+		}
+		res = append(res, st)
+	}
+
+	if len(res) != int(count) {
+		return nil, fmt.Errorf("not enough records in proof")
+	}
+	return res, nil
+}
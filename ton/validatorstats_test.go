@@ -0,0 +1,108 @@
+package ton
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/alan890104/tonutils-go/tlb"
+	"github.com/alan890104/tonutils-go/tvm/cell"
+)
+
+// testCreateStats - stats loaded from proof where all keys with the first bit set are pruned
+func testCreateStats(t *testing.T) *tlb.BlockCreateStats {
+	counters := cell.NewDict(256)
+	for _, k := range []struct {
+		key     byte
+		updated uint32
+	}{{0x10, 100}, {0x20, 200}, {0x30, 300}, {0x40, 400}, {0x90, 500}, {0xA0, 600}} {
+		st, err := tlb.ToCell(tlb.CreatorStats{
+			McBlocks:    tlb.Counters{LastUpdated: k.updated},
+			ShardBlocks: tlb.Counters{LastUpdated: k.updated / 2},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = counters.Set(cell.BeginCell().MustStoreSlice(bytes.Repeat([]byte{k.key}, 32), 256).EndCell(), st); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	full, err := tlb.ToCell(tlb.BlockCreateStats{Stats: tlb.BlockCreateStatsOrdinary{Counters: counters}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sk := cell.CreateProofSkeleton()
+	sk.ProofRef(0).ProofRef(0).SetRecursive()
+	proof, err := full.CreateProof(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pruned, err := cell.UnwrapProof(proof, full.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stats tlb.BlockCreateStats
+	if err = tlb.LoadFromCellAsProof(&stats, pruned.BeginParse()); err != nil {
+		t.Fatal(err)
+	}
+	return &stats
+}
+
+func TestFilterValidatorStats(t *testing.T) {
+	stats := testCreateStats(t)
+
+	for _, tt := range []struct {
+		name          string
+		count         int32
+		startAfter    byte
+		modifiedAfter uint32
+		keys          []byte
+		fail          bool
+	}{
+		{name: "from start", count: 2, keys: []byte{0x10, 0x20}},
+		{name: "all proven", count: 4, keys: []byte{0x10, 0x20, 0x30, 0x40}},
+		{name: "start after", count: 2, startAfter: 0x10, keys: []byte{0x20, 0x30}},
+		{name: "start after not existing key", count: 1, startAfter: 0x25, keys: []byte{0x30}},
+		{name: "modified after", count: 2, modifiedAfter: 250, keys: []byte{0x30, 0x40}},
+		{name: "modified at the same time", count: 1, modifiedAfter: 400, keys: []byte{0x40}},
+		{name: "start and modified after", count: 1, startAfter: 0x30, modifiedAfter: 150, keys: []byte{0x40}},
+		{name: "zero count", count: 0, keys: []byte{}},
+		{name: "records are pruned", count: 5, fail: true},
+		{name: "not enough after start", count: 2, startAfter: 0x30, fail: true},
+		{name: "not enough modified", count: 1, modifiedAfter: 1000, fail: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var startAfter []byte
+			if tt.startAfter != 0 {
+				startAfter = bytes.Repeat([]byte{tt.startAfter}, 32)
+			}
+
+			res, err := filterValidatorStats(stats, tt.count, startAfter, tt.modifiedAfter)
+			if tt.fail {
+				if err == nil {
+					t.Fatal("should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(res) != len(tt.keys) {
+				t.Fatal("incorrect records num", len(res))
+			}
+			for i, key := range tt.keys {
+				if !bytes.Equal(res[i].PublicKey, bytes.Repeat([]byte{key}, 32)) {
+					t.Fatal("incorrect record", i)
+				}
+			}
+		})
+	}
+
+	if _, err := filterValidatorStats(nil, 1, nil, 0); err == nil {
+		t.Fatal("no stats should fail")
+	}
+}
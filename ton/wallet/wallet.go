@@ -21,6 +21,7 @@ import (
 
 	"github.com/alan890104/tonutils-go/address"
 	"github.com/alan890104/tonutils-go/tlb"
+	"github.com/alan890104/tonutils-go/tlb/payloads"
 	"github.com/alan890104/tonutils-go/tvm/cell"
 )
 
@@ -373,6 +374,25 @@ func (w *Wallet) GetBalance(ctx context.Context, block *ton.BlockIDExt) (tlb.Coi
 	return acc.State.Balance, nil
 }
 
+// GetBalanceWithExtraCurrencies - returns TON balance of wallet together with its extra currencies
+func (w *Wallet) GetBalanceWithExtraCurrencies(ctx context.Context, block *ton.BlockIDExt) (tlb.Coins, tlb.ExtraCurrencies, error) {
+	acc, err := w.api.WaitForBlock(block.SeqNo).GetAccount(ctx, block, w.addr)
+	if err != nil {
+		return tlb.Coins{}, nil, fmt.Errorf("failed to get account state: %w", err)
+	}
+
+	if !acc.IsActive {
+		return tlb.Coins{}, tlb.ExtraCurrencies{}, nil
+	}
+
+	extra, err := acc.State.ExtraCurrenciesMap()
+	if err != nil {
+		return tlb.Coins{}, nil, fmt.Errorf("failed to parse extra currencies: %w", err)
+	}
+
+	return acc.State.Balance, extra, nil
+}
+
 func (w *Wallet) GetSpec() any {
 	return w.spec
 }
@@ -480,6 +500,19 @@ func (w *Wallet) BuildTransfer(to *address.Address, amount tlb.Coins, bounce boo
 	}, nil
 }
 
+// BuildTransferWithExtraCurrencies - same as BuildTransfer, but also attaches extra currencies to message
+func (w *Wallet) BuildTransferWithExtraCurrencies(to *address.Address, amount tlb.Coins, extra tlb.ExtraCurrencies, bounce bool, comment string) (*Message, error) {
+	msg, err := w.BuildTransfer(to, amount, bounce, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = msg.SetExtraCurrencies(extra); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
 func (w *Wallet) BuildTransferEncrypted(ctx context.Context, to *address.Address, amount tlb.Coins, bounce bool, comment string) (_ *Message, err error) {
 	var body *cell.Cell
 	if comment != "" {
@@ -591,15 +624,23 @@ func (w *Wallet) TransferWithEncryptedComment(ctx context.Context, to *address.A
 	return w.Send(ctx, transfer, waitConfirmation...)
 }
 
+// CreateCommentCell - builds text comment payload, text should be valid utf8,
+// it is split between cells only on runes boundaries.
 func CreateCommentCell(text string) (*cell.Cell, error) {
-	// comment ident
-	root := cell.BeginCell().MustStoreUInt(0, 32)
-
-	if err := root.StoreStringSnake(text); err != nil {
+	c, err := payloads.Comment{Text: text}.ToCell()
+	if err != nil {
 		return nil, fmt.Errorf("failed to build comment: %w", err)
 	}
+	return c, nil
+}
 
-	return root.EndCell(), nil
+// CreateBinaryCommentCell - builds binary comment payload
+func CreateBinaryCommentCell(data []byte) (*cell.Cell, error) {
+	c, err := payloads.BinaryComment{Data: data}.ToCell()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build binary comment: %w", err)
+	}
+	return c, nil
 }
 
 const EncryptedCommentOpcode = 0x2167da4b
@@ -824,3 +865,24 @@ func SimpleMessageAutoBounce(to *address.Address, amount tlb.Coins, payload *cel
 		},
 	}
 }
+
+// SimpleMessageWithExtraCurrencies - same as SimpleMessage, but also attaches extra currencies to message
+func SimpleMessageWithExtraCurrencies(to *address.Address, amount tlb.Coins, extra tlb.ExtraCurrencies, payload *cell.Cell) (*Message, error) {
+	msg := SimpleMessage(to, amount, payload)
+	if err := msg.SetExtraCurrencies(extra); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// SetExtraCurrencies - replaces extra currencies attached to message
+func (m *Message) SetExtraCurrencies(extra tlb.ExtraCurrencies) error {
+	if m.InternalMessage == nil {
+		return fmt.Errorf("internal message is nil")
+	}
+
+	if err := m.InternalMessage.SetExtraCurrencies(extra); err != nil {
+		return fmt.Errorf("failed to set extra currencies: %w", err)
+	}
+	return nil
+}
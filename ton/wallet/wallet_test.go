@@ -521,21 +521,141 @@ func (w WaiterMock) SetTrustedBlockFromConfig(cfg *liteclient.GlobalConfig) {
 	panic("implement me")
 }
 
+func (w WaiterMock) SetTrustStore(ctx context.Context, store ton.TrustStore) error {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (w WaiterMock) SubscribeOnTransactions(workerCtx context.Context, addr *address.Address, lastProcessedLT uint64, channel chan<- *tlb.Transaction) {
 	//TODO implement me
 	panic("implement me")
 }
 
+func (w WaiterMock) SubscribeOnBlocks(workerCtx context.Context, fromSeqno uint32, channel chan<- *ton.BlockUpdate) {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (w WaiterMock) VerifyProofChain(ctx context.Context, from, to *ton.BlockIDExt) error {
 	//TODO implement me
 	panic("implement me")
 }
 
+func (w WaiterMock) GetBlockTransactionsExt(ctx context.Context, block *ton.BlockIDExt, count uint32, after ...*ton.TransactionID3) ([]*tlb.Transaction, bool, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) GetBlockHeader(ctx context.Context, block *ton.BlockIDExt) (*tlb.BlockHeader, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) GetShardInfo(ctx context.Context, master *ton.BlockIDExt, workchain int32, shard int64, exact bool) (*ton.BlockIDExt, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) GetShardBlockProof(ctx context.Context, block *ton.BlockIDExt) (*ton.ShardBlockProof, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) GetFullState(ctx context.Context, block *ton.BlockIDExt) (*cell.Cell, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) GetMasterchainInfoExt(ctx context.Context) (*ton.MasterchainInfoExt, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) GetVersion(ctx context.Context) (*ton.Version, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) GetLibrariesWithProof(ctx context.Context, block *ton.BlockIDExt, hashes ...[]byte) ([]*cell.Cell, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) LookupBlockByLT(ctx context.Context, workchain int32, shard int64, lt uint64) (*ton.BlockIDExt, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) LookupBlockByUtime(ctx context.Context, workchain int32, shard int64, utime uint32) (*ton.BlockIDExt, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) FindMasterBlockByUtime(ctx context.Context, utime uint32) (*ton.BlockIDExt, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) FindBlocksByUtime(ctx context.Context, utime uint32) (*ton.BlockIDExt, []*ton.BlockIDExt, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) LookupBlockWithProof(ctx context.Context, master *ton.BlockIDExt, workchain int32, shard int64, seqno uint32) (*ton.BlockIDExt, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) GetOutMsgQueueSizes(ctx context.Context) (*ton.OutMsgQueueSizes, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) GetShardOutMsgQueueSizes(ctx context.Context, workchain int32, shard int64) (*ton.OutMsgQueueSizes, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) GetBlockOutMsgQueueSize(ctx context.Context, block *ton.BlockIDExt) (uint64, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) GetDispatchQueueInfo(ctx context.Context, block *ton.BlockIDExt, afterAddr []byte, maxAccounts int32) (*ton.DispatchQueueInfo, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) GetDispatchQueueMessages(ctx context.Context, block *ton.BlockIDExt, addr *address.Address, afterLT uint64, maxMessages int32, oneAccount bool) (*ton.DispatchQueueMessages, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) GetValidatorStats(ctx context.Context, master *ton.BlockIDExt, limit int32, startAfter []byte, modifiedAfter uint32) ([]*tlb.ValidatorCreateStats, bool, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) GetNonfinalValidatorGroups(ctx context.Context, workchain *int32, shard *int64) ([]ton.NonfinalValidatorGroupInfo, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) GetNonfinalCandidate(ctx context.Context, id *ton.NonfinalCandidateID) (*cell.Cell, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (w WaiterMock) Client() ton.LiteClient {
 	//TODO implement me
 	panic("implement me")
 }
 
+func (w WaiterMock) SendExternalMessageWaitConfirmation(ctx context.Context, msg *tlb.ExternalMessage, policy ton.ConfirmationPolicy) (*ton.ConfirmedTransaction, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (w WaiterMock) SendExternalMessageWaitTransaction(ctx context.Context, msg *tlb.ExternalMessage) (*tlb.Transaction, *ton.BlockIDExt, []byte, error) {
 	return w.MSendExternalMessageWaitTransaction(ctx, msg)
 }
@@ -600,10 +720,25 @@ func (w WaiterMock) RunGetMethod(ctx context.Context, blockInfo *ton.BlockIDExt,
 	return w.MRunGetMethod(ctx, blockInfo, addr, method, params...)
 }
 
+func (w WaiterMock) RunGetMethodVerified(ctx context.Context, blockInfo *ton.BlockIDExt, addr *address.Address, method string, params ...any) (*ton.ExecutionResult, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WaiterMock) SetTVMExecutor(executor ton.TVMExecutor) {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (w WaiterMock) ListTransactions(ctx context.Context, addr *address.Address, num uint32, lt uint64, txHash []byte) ([]*tlb.Transaction, error) {
 	return w.MListTransactions(ctx, addr, num, lt, txHash)
 }
 
+func (w WaiterMock) ListTransactionsWithProof(ctx context.Context, master *ton.BlockIDExt, addr *address.Address, num uint32, lt uint64, txHash []byte) ([]*tlb.Transaction, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (w WaiterMock) GetTransaction(ctx context.Context, block *ton.BlockIDExt, addr *address.Address, lt uint64) (*tlb.Transaction, error) {
 	return w.MGetTransaction(ctx, block, addr, lt)
 }
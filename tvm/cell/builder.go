@@ -3,6 +3,7 @@ package cell
 import (
 	"encoding/binary"
 	"math/big"
+	"unicode/utf8"
 
 	"github.com/alan890104/tonutils-go/address"
 )
@@ -68,6 +69,47 @@ func (b *Builder) StoreBigVarUInt(val *big.Int, sz uint) error {
 	return nil
 }
 
+// StoreBigVarInt - stores signed integer as VarInteger sz
+func (b *Builder) StoreBigVarInt(val *big.Int, sz uint) error {
+	bits := val.BitLen() + 1 // sign bit
+	if val.Sign() < 0 {
+		bits = new(big.Int).Not(val).BitLen() + 1
+	}
+	if val.Sign() == 0 {
+		bits = 0
+	}
+
+	ln := uint((bits + 7) >> 3) // bytes required for value
+	if ln >= sz {
+		return ErrTooBigValue
+	}
+
+	szLen := uint(big.NewInt(int64(sz - 1)).BitLen())
+	if b.bitsSz+szLen+(ln*8) >= 1024 {
+		return ErrNotFit1023
+	}
+
+	err := b.StoreUInt(uint64(ln), szLen)
+	if err != nil {
+		return err
+	}
+
+	if ln == 0 {
+		return nil
+	}
+
+	// copy, because StoreBigInt modifies negative values
+	return b.StoreBigInt(new(big.Int).Set(val), ln*8)
+}
+
+func (b *Builder) MustStoreBigVarInt(val *big.Int, sz uint) *Builder {
+	err := b.StoreBigVarInt(val, sz)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
 func (b *Builder) MustStoreBigVarUInt(val *big.Int, sz uint) *Builder {
 	err := b.StoreBigVarUInt(val, sz)
 	if err != nil {
@@ -343,6 +385,65 @@ func (b *Builder) StoreStringSnake(str string) error {
 	return b.StoreBinarySnake([]byte(str))
 }
 
+func (b *Builder) MustStoreTextSnake(str string, maxChunks int) *Builder {
+	err := b.StoreTextSnake(str, maxChunks)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// StoreTextSnake - stores utf8 string as snake, chunks are split only on runes boundaries,
+// so each cell contains valid utf8 part of the string.
+// When maxChunks > 0, ErrTooManyChunks is returned if more cells are required.
+func (b *Builder) StoreTextSnake(str string, maxChunks int) error {
+	if !utf8.ValidString(str) {
+		return ErrInvalidUTF8
+	}
+
+	data := []byte(str)
+	space := int(b.BitsLeft() / 8)
+
+	var chunks [][]byte
+	for {
+		sz := space
+		if sz >= len(data) {
+			chunks = append(chunks, data)
+			break
+		}
+
+		// move back to the beginning of the rune
+		for sz > 0 && !utf8.RuneStart(data[sz]) {
+			sz--
+		}
+
+		chunks = append(chunks, data[:sz])
+		data = data[sz:]
+		space = 127
+	}
+
+	if maxChunks > 0 && len(chunks) > maxChunks {
+		return ErrTooManyChunks
+	}
+
+	var next *Cell
+	for i := len(chunks) - 1; i > 0; i-- {
+		c := BeginCell().MustStoreSlice(chunks[i], uint(len(chunks[i]))*8)
+		if next != nil {
+			c.MustStoreRef(next)
+		}
+		next = c.EndCell()
+	}
+
+	if err := b.StoreSlice(chunks[0], uint(len(chunks[0]))*8); err != nil {
+		return err
+	}
+	if next != nil {
+		return b.StoreRef(next)
+	}
+	return nil
+}
+
 func (b *Builder) StoreBinarySnake(data []byte) error {
 	var f func(space int) (*Builder, error)
 	f = func(space int) (*Builder, error) {
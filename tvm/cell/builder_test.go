@@ -341,6 +341,21 @@ func TestBuilder_VarUint(t *testing.T) {
 	}
 }
 
+func TestBuilder_VarInt(t *testing.T) {
+	for _, v := range []int64{0, 1, -1, 127, 128, -128, -129, 777, -777, 1 << 40, -(1 << 40)} {
+		for i := uint(8); i <= 32; i += 8 {
+			c := BeginCell().MustStoreBigVarInt(big.NewInt(v), i).EndCell()
+			if c.BeginParse().MustLoadVarInt(i).Int64() != v {
+				t.Fatal("var int not eq", v)
+			}
+		}
+	}
+
+	if err := BeginCell().StoreBigVarInt(big.NewInt(128), 2); err != ErrTooBigValue {
+		t.Fatal("should be too big", err)
+	}
+}
+
 func TestBuilder_StoreBuilder(t *testing.T) {
 	c := BeginCell().MustStoreSlice(data1024, 1015).MustStoreRef(BeginCell().EndCell())
 	b1bad := BeginCell().MustStoreSlice([]byte{0xAA, 0xBB}, 16).MustStoreRef(BeginCell().EndCell())
@@ -14,6 +14,8 @@ var ErrSmallSlice = errors.New("too small slice for this size")
 var ErrTooBigSize = errors.New("too big size")
 var ErrTooMuchRefs = errors.New("too much refs")
 var ErrNotFit1023 = errors.New("cell data size should fit into 1023 bits")
+var ErrInvalidUTF8 = errors.New("string is not valid utf8")
+var ErrTooManyChunks = errors.New("too many snake chunks")
 var ErrNoMoreRefs = errors.New("no more refs exists")
 var ErrAddressTypeNotSupported = errors.New("address type is not supported")
 
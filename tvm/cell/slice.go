@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"unicode/utf8"
 
 	"github.com/alan890104/tonutils-go/address"
 )
@@ -302,6 +303,33 @@ func (c *Slice) LoadVarUInt(sz uint) (*big.Int, error) {
 	return value, nil
 }
 
+// LoadVarInt - loads signed integer serialized as VarInteger sz
+func (c *Slice) LoadVarInt(sz uint) (*big.Int, error) {
+	ln, err := c.LoadUInt(uint(big.NewInt(int64(sz - 1)).BitLen()))
+	if err != nil {
+		return nil, err
+	}
+
+	if ln == 0 {
+		return big.NewInt(0), nil
+	}
+
+	value, err := c.LoadBigInt(uint(ln * 8))
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func (c *Slice) MustLoadVarInt(sz uint) *big.Int {
+	s, err := c.LoadVarInt(sz)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
 func (c *Slice) MustLoadVarUInt(sz uint) *big.Int {
 	s, err := c.LoadVarUInt(sz)
 	if err != nil {
@@ -537,6 +565,39 @@ func (c *Slice) LoadStringSnake() (string, error) {
 	return string(a), nil
 }
 
+// LoadTextSnake - loads snake string and validates that it is utf8.
+// When maxChunks > 0, ErrTooManyChunks is returned if string consists of more cells.
+func (c *Slice) LoadTextSnake(maxChunks int) (string, error) {
+	var data []byte
+
+	ref := c
+	for chunks := 1; ; chunks++ {
+		if maxChunks > 0 && chunks > maxChunks {
+			return "", ErrTooManyChunks
+		}
+
+		b, err := ref.LoadSlice(ref.BitsLeft())
+		if err != nil {
+			return "", err
+		}
+		data = append(data, b...)
+
+		if ref.RefsNum() > 1 {
+			return "", fmt.Errorf("more than one ref, it is not snake string")
+		}
+
+		if ref.RefsNum() == 0 {
+			break
+		}
+		ref = ref.MustLoadRef()
+	}
+
+	if !utf8.Valid(data) {
+		return "", ErrInvalidUTF8
+	}
+	return string(data), nil
+}
+
 func (c *Slice) LoadBinarySnake() ([]byte, error) {
 	var data []byte
 
@@ -2,7 +2,9 @@ package cell
 
 import (
 	"bytes"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/alan890104/tonutils-go/address"
 )
@@ -124,3 +126,44 @@ func TestSlice_Snake(t *testing.T) {
 		t.Fatal("str not eq", str, ldStr)
 	}
 }
+
+func TestSlice_TextSnake(t *testing.T) {
+	str := strings.Repeat("привет 😃 ", 60)
+
+	// odd offset to make byte split land inside rune
+	c := BeginCell().MustStoreUInt(0, 35).MustStoreTextSnake(str, 0).EndCell()
+
+	sl := c.BeginParse()
+	sl.MustLoadUInt(35)
+	for chunk := sl.Copy(); ; {
+		data := chunk.MustLoadSlice(chunk.BitsLeft())
+		if !utf8.Valid(data) {
+			t.Fatal("chunk is not valid utf8")
+		}
+		if chunk.RefsNum() == 0 {
+			break
+		}
+		chunk = chunk.MustLoadRef()
+	}
+
+	ldStr, err := sl.Copy().LoadTextSnake(0)
+	if err != nil || ldStr != str {
+		t.Fatal("str not eq", err)
+	}
+
+	if _, err = sl.Copy().LoadTextSnake(2); err != ErrTooManyChunks {
+		t.Fatal("expected too many chunks", err)
+	}
+
+	if err = BeginCell().StoreTextSnake(str, 2); err != ErrTooManyChunks {
+		t.Fatal("expected too many chunks", err)
+	}
+
+	if err = BeginCell().StoreTextSnake("\xff\xfe", 0); err != ErrInvalidUTF8 {
+		t.Fatal("expected invalid utf8", err)
+	}
+
+	if _, err = BeginCell().MustStoreBinarySnake([]byte{0xff, 0xfe}).EndCell().BeginParse().LoadTextSnake(0); err != ErrInvalidUTF8 {
+		t.Fatal("expected invalid utf8 on load", err)
+	}
+}